@@ -0,0 +1,176 @@
+package genbase
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// fieldPath is selector path from struct to field, that may pass through embedded fields.
+type fieldPath struct {
+	owner    string       // name of struct type
+	names    []string     // field names of selector. e.g. ["Base", "ID"] for promoted field ID
+	pointers []bool       // true if field of same index is pointer to embedded struct
+	vars     []*types.Var // fields of same index
+}
+
+// lookupFieldPath finds field or promoted field of struct type by name.
+func (t *TypeInfo) lookupFieldPath(name string) (*fieldPath, error) {
+	obj := t.TypeObject()
+	if obj == nil {
+		return nil, errors.New("types are not resolved")
+	}
+	if _, ok := obj.Type().Underlying().(*types.Struct); !ok {
+		return nil, ErrNotStructType
+	}
+
+	found, index, _ := types.LookupFieldOrMethod(obj.Type(), false, obj.Pkg(), name)
+	if v, ok := found.(*types.Var); !ok || !v.IsField() {
+		if index != nil && found == nil {
+			return nil, fmt.Errorf("field %s of type %s is ambiguous", name, t.Name())
+		}
+		return nil, fmt.Errorf("field %s is not found in type %s", name, t.Name())
+	}
+
+	path := &fieldPath{owner: t.Name()}
+	var typ types.Type = obj.Type()
+	for _, i := range index {
+		if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+			typ = ptr.Elem()
+		}
+		field := typ.Underlying().(*types.Struct).Field(i)
+		typ = field.Type()
+		_, isPointer := typ.Underlying().(*types.Pointer)
+		path.names = append(path.names, field.Name())
+		path.pointers = append(path.pointers, isPointer)
+		path.vars = append(path.vars, field)
+	}
+	return path, nil
+}
+
+// selector returns selector expression of field on recv. e.g. "x.Base.ID"
+func (path *fieldPath) selector(recv string, n int) string {
+	return strings.Join(append([]string{recv}, path.names[:n]...), ".")
+}
+
+// field returns field at the end of path.
+func (path *fieldPath) field() *types.Var {
+	return path.vars[len(path.vars)-1]
+}
+
+// PrintGetter prints getter method of field like "GetName" to buffer.
+// field is name of field, or promoted field of embedded structs.
+// getter returns zero value if receiver or embedded pointer on the way is nil.
+// pointer to non-struct type is dereferenced, e.g. getter of *string field returns string and "" for nil.
+func (g *Generator) PrintGetter(t *TypeInfo, field string) error {
+	path, err := t.lookupFieldPath(field)
+	if err != nil {
+		return err
+	}
+
+	typ := path.field().Type()
+	deref := false
+	if ptr, ok := typ.(*types.Pointer); ok {
+		if _, ok := ptr.Elem().Underlying().(*types.Struct); !ok {
+			typ = ptr.Elem()
+			deref = true
+		}
+	}
+	typeName := t.PackageInfo.renderType(typ, g.Qualifier())
+
+	conds := []string{"x == nil"}
+	for i := range path.names[:len(path.names)-1] {
+		if path.pointers[i] {
+			conds = append(conds, path.selector("x", i+1)+" == nil")
+		}
+	}
+	value := path.selector("x", len(path.names))
+	if deref {
+		conds = append(conds, value+" == nil")
+		value = "*" + value
+	}
+
+	name := "Get" + upperFirst(field)
+	g.Printf("// %s returns %s of %s, or zero value if it is not reachable.\n", name, field, path.owner)
+	g.Printf("func (x *%s) %s() %s {\n", path.owner, name, typeName)
+	g.Printf("if %s {\nreturn %s\n}\n", strings.Join(conds, " || "), zeroValueOfType(typ, typeName))
+	g.Printf("return %s\n}\n\n", value)
+	return nil
+}
+
+// PrintWither prints method like "WithName" that returns copy of receiver which field is replaced, to buffer.
+// field is name of field, or promoted field of embedded structs.
+// embedded pointers on the way are copied or allocated, and slice argument is copied,
+// so receiver is never modified through returned value.
+func (g *Generator) PrintWither(t *TypeInfo, field string) error {
+	path, err := t.lookupFieldPath(field)
+	if err != nil {
+		return err
+	}
+
+	typ := path.field().Type()
+	qualifier := g.Qualifier()
+	typeName := t.PackageInfo.renderType(typ, qualifier)
+
+	name := "With" + upperFirst(field)
+	g.Printf("// %s returns copy of %s that %s is replaced by v.\n", name, path.owner, field)
+	g.Printf("func (x *%[1]s) %[2]s(v %[3]s) *%[1]s {\n", path.owner, name, typeName)
+	g.Printf("var y %s\nif x != nil {\ny = *x\n}\n", path.owner)
+	for i := range path.names[:len(path.names)-1] {
+		if !path.pointers[i] {
+			continue
+		}
+		selector := path.selector("y", i+1)
+		elem := path.vars[i].Type().Underlying().(*types.Pointer).Elem()
+		g.Printf("e%d := new(%s)\n", i, t.PackageInfo.renderType(elem, qualifier))
+		g.Printf("if %s != nil {\n*e%d = *%s\n}\n", selector, i, selector)
+		g.Printf("%s = e%d\n", selector, i)
+	}
+	value := "v"
+	if _, ok := typ.Underlying().(*types.Slice); ok {
+		value = fmt.Sprintf("append(%s(nil), v...)", typeName)
+	}
+	g.Printf("%s = %s\n", path.selector("y", len(path.names)), value)
+	g.Printf("return &y\n}\n\n")
+	return nil
+}
+
+// PrintAccessors prints getters and withers of all fields of struct type to buffer.
+// embedded fields are named by their type, and fields that are skipped by tag are ignored, see FieldInfo.IsSkipped.
+func (g *Generator) PrintAccessors(t *TypeInfo, tag string) error {
+	st, err := t.StructType()
+	if err != nil {
+		return err
+	}
+
+	obj := t.TypeObject()
+	if obj == nil {
+		return errors.New("types are not resolved")
+	}
+	structType := obj.Type().Underlying().(*types.Struct)
+	skipped := make(map[string]bool)
+	for _, f := range st.FieldInfos() {
+		if !f.IsSkipped(tag) {
+			continue
+		}
+		skipped[f.Name()] = true
+		for _, ident := range f.Names {
+			skipped[ident.Name] = true
+		}
+	}
+
+	for i := 0; i < structType.NumFields(); i++ {
+		field := structType.Field(i).Name()
+		if field == "_" || skipped[field] {
+			continue
+		}
+		if err := g.PrintGetter(t, field); err != nil {
+			return err
+		}
+		if err := g.PrintWither(t, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package genbase
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGeneratorPrintAccessors(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", "package sample\n"+
+		"import \"time\"\n"+
+		"type User struct {\n"+
+		"	*Base\n"+
+		"	Meta\n"+
+		"	Name      string\n"+
+		"	Nick      *string\n"+
+		"	Tags      []string\n"+
+		"	Parent    *User\n"+
+		"	CreatedAt time.Time\n"+
+		"	Cache     string `gen:\"-\"`\n"+
+		"}\n"+
+		"type Base struct {\n"+
+		"	ID int\n"+
+		"}\n"+
+		"type Meta struct {\n"+
+		"	Labels map[string]string\n"+
+		"}\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	typeInfo, err := pInfo.FindTypeInfo("User")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewGenerator(pInfo)
+	if err := g.PrintAccessors(typeInfo, "+sample"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.PrintGetter(typeInfo, "ID"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.PrintWither(typeInfo, "ID"); err != nil {
+		t.Fatal(err)
+	}
+	body := g.Buf.String()
+	g.Buf.Reset()
+	g.PrintHeader("sample", &[]string{})
+	g.Printf("%s", body)
+	src, err := g.Format()
+	if err != nil {
+		t.Fatalf("unexpected: %s\n%s", err, string(src))
+	}
+	if _, err := pInfo.CheckGenerated("sample_accessors.go", src); err != nil {
+		t.Fatalf("unexpected: %s\n%s", err, string(src))
+	}
+
+	output := string(src)
+	for _, expected := range []string{
+		"func (x *User) GetBase() *Base {",
+		"func (x *User) GetMeta() Meta {\n\tif x == nil {\n\t\treturn Meta{}\n\t}\n\treturn x.Meta\n}",
+		"func (x *User) GetNick() string {\n\tif x == nil || x.Nick == nil {\n\t\treturn \"\"\n\t}\n\treturn *x.Nick\n}",
+		"func (x *User) GetParent() *User {\n\tif x == nil {\n\t\treturn nil\n\t}",
+		"func (x *User) GetCreatedAt() time.Time {",
+		"y.Tags = append([]string(nil), v...)",
+		"func (x *User) GetID() int {\n\tif x == nil || x.Base == nil {\n\t\treturn 0\n\t}\n\treturn x.Base.ID\n}",
+		"func (x *User) WithID(v int) *User {\n\tvar y User\n\tif x != nil {\n\t\ty = *x\n\t}\n" +
+			"\te0 := new(Base)\n\tif y.Base != nil {\n\t\t*e0 = *y.Base\n\t}\n\ty.Base = e0\n\ty.Base.ID = v\n\treturn &y\n}",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("unexpected: %s is not contained\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "Cache") {
+		t.Errorf("unexpected: %s", output)
+	}
+
+	if err := g.PrintGetter(typeInfo, "Unknown"); err == nil || err.Error() != "field Unknown is not found in type User" {
+		t.Errorf("unexpected: %v", err)
+	}
+}
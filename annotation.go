@@ -0,0 +1,543 @@
+package genbase
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AnnotationArg is argument of annotation. e.g. `output=foo.go`, `name="a b"`, `omitempty`
+type AnnotationArg struct {
+	Key      string
+	Value    string
+	HasValue bool // false for flag argument like `omitempty`
+	Pos      token.Pos
+
+	configFile string // config file that gives argument, Pos is token.NoPos for it
+}
+
+// AnnotationError shows that annotation has malformed arguments.
+type AnnotationError struct {
+	Position token.Position
+	Key      string // argument key if error is about specific argument
+	Reason   string
+}
+
+// AnnotationErrors is []*AnnotationError synonym.
+type AnnotationErrors []*AnnotationError
+
+func (err *AnnotationError) Error() string {
+	return fmt.Sprintf("%s: %s", err.Position, err.Reason)
+}
+
+func (errs AnnotationErrors) Error() string {
+	ss := make([]string, 0, len(errs))
+	for _, err := range errs {
+		ss = append(ss, err.Error())
+	}
+	return strings.Join(ss, "\n")
+}
+
+// annotationSyntaxError is error of malformed arguments in comment at pos.
+type annotationSyntaxError struct {
+	pos token.Pos
+	err error
+}
+
+func (err *annotationSyntaxError) Error() string {
+	return err.err.Error()
+}
+
+func (err *annotationSyntaxError) Unwrap() error {
+	return err.err
+}
+
+// AnnotationInfo is annotation of type. continuation lines of annotation are merged.
+type AnnotationInfo struct {
+	Tag      string // e.g. "+jwg"
+	Args     []*AnnotationArg
+	Raw      string         // text of comments, lines are joined by newline. e.g. "// +jwg: output=a.go"
+	Pos      token.Pos      // position of first comment
+	Position token.Position // resolved position of first comment, it is empty if FileSet is unknown
+	Comments []*ast.Comment // comments of annotation, first one has tag
+}
+
+// annotationOption is suffix of tag for continuation line. e.g. "// +jwg:option key=value"
+const annotationOption = ":option"
+
+// ParseAnnotationArgs parses arguments of annotation comment c that has directive.
+// arguments are separated by commas or spaces. e.g. "// +jwg: output=foo.go, name=\"a b\", omitempty"
+func ParseAnnotationArgs(c *ast.Comment, directive string) ([]*AnnotationArg, error) {
+	text, offset, ok := annotationText(c, directive)
+	if !ok {
+		return nil, fmt.Errorf("comment is not annotated with %s", directive)
+	}
+	return parseAnnotationArgs(text, c.Pos()+token.Pos(offset))
+}
+
+// Annotation returns annotation tag of type that continuation lines are merged, or nil if type is not annotated with tag.
+// line that ends with "\" is continued by next line,
+// and following lines like "// +jwg:option key=value" in same comment group add arguments.
+func (t *TypeInfo) Annotation(tag string) (*AnnotationInfo, error) {
+	for _, doc := range t.Comments() {
+		if info, err := parseAnnotation(doc, tag); err != nil || info != nil {
+			if info != nil {
+				info.Position = t.PackageInfo.position(info.Pos)
+			}
+			return info, err
+		}
+	}
+	return nil, nil
+}
+
+// parseAnnotation returns first annotation tag in doc, or nil if it is not found.
+func parseAnnotation(doc *ast.CommentGroup, tag string) (*AnnotationInfo, error) {
+	infos, err := parseAnnotations(doc, []string{tag})
+	if err != nil || len(infos) == 0 {
+		return nil, err
+	}
+	return infos[0], nil
+}
+
+// parseAnnotations returns all annotations of tags in doc. all annotations are returned if tags are empty.
+// option lines are merged into the nearest preceding annotation of same tag.
+func parseAnnotations(doc *ast.CommentGroup, tags []string) ([]*AnnotationInfo, error) {
+	if doc == nil {
+		return nil, nil
+	}
+	var infos []*AnnotationInfo
+	last := make(map[string]*AnnotationInfo)
+	continued := false
+	var current *AnnotationInfo
+	for _, c := range doc.List {
+		if continued && strings.HasPrefix(c.Text, "//") {
+			line := strings.TrimLeft(c.Text[2:], " ")
+			current.Comments = append(current.Comments, c)
+			var err error
+			continued, err = current.addArgs(line, c.Pos()+token.Pos(len(c.Text)-len(line)))
+			if err != nil {
+				return nil, &annotationSyntaxError{pos: c.Pos(), err: err}
+			}
+			continue
+		}
+		continued = false
+
+		tag := annotationTag(c)
+		if tag == "" || len(tags) != 0 && !containsString(tags, tag) {
+			continue
+		}
+		text, offset, ok := annotationText(c, tag)
+		if !ok {
+			continue
+		}
+		if isAnnotationOption(c, offset) {
+			if current = last[tag]; current == nil {
+				continue
+			}
+			text = text[len(annotationOption)-1:]
+			offset += len(annotationOption) - 1
+			current.Comments = append(current.Comments, c)
+		} else {
+			current = &AnnotationInfo{Tag: tag, Pos: c.Pos(), Comments: []*ast.Comment{c}}
+			infos = append(infos, current)
+			last[tag] = current
+		}
+		var err error
+		continued, err = current.addArgs(text, c.Pos()+token.Pos(offset))
+		if err != nil {
+			return nil, &annotationSyntaxError{pos: c.Pos(), err: err}
+		}
+	}
+	for _, info := range infos {
+		lines := make([]string, 0, len(info.Comments))
+		for _, c := range info.Comments {
+			lines = append(lines, c.Text)
+		}
+		info.Raw = strings.Join(lines, "\n")
+	}
+	return infos, nil
+}
+
+// annotationTag returns tag of annotation comment c. e.g. "+jwg" for "// +jwg: opts"
+// returns "" if c is not annotation.
+func annotationTag(c *ast.Comment) string {
+	text := strings.TrimLeft(c.Text, "/ ")
+	if !strings.HasPrefix(text, "+") {
+		return ""
+	}
+	if idx := strings.IndexAny(text, " \t:"); idx != -1 {
+		text = text[:idx]
+	}
+	if len(text) < 2 {
+		return ""
+	}
+	return text
+}
+
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// setAnnotations sets Annotations and AnnotationInfo of tag.
+func (t *TypeInfo) setAnnotations(tag string) {
+	t.Annotations, _ = t.AllAnnotations(tag)
+	t.AnnotationInfo = nil
+	if len(t.Annotations) != 0 {
+		t.AnnotationInfo = t.Annotations[0]
+	}
+}
+
+// ParseTaggedTypeInfos is CollectTaggedTypeInfos that parses arguments of annotations.
+// it returns AnnotationErrors with position of each comment that has malformed arguments,
+// instead of types that have no Annotations.
+func (pkg *PackageInfo) ParseTaggedTypeInfos(tag string) (TypeInfos, error) {
+	typeInfos := pkg.CollectTaggedTypeInfos(tag)
+	if err := pkg.checkAnnotations(tag, typeInfos); err != nil {
+		return nil, err
+	}
+	return typeInfos, nil
+}
+
+// checkAnnotations returns AnnotationErrors if annotations of tag on typeInfos are malformed.
+func (pkg *PackageInfo) checkAnnotations(tag string, typeInfos TypeInfos) error {
+	var errs AnnotationErrors
+	for _, t := range typeInfos {
+		_, err := t.AllAnnotations(tag)
+		if err == nil {
+			continue
+		}
+		pos := t.AnnotatedComment.Pos()
+		var syntaxErr *annotationSyntaxError
+		if errors.As(err, &syntaxErr) {
+			pos = syntaxErr.pos
+		}
+		errs = append(errs, &AnnotationError{
+			Position: pkg.position(pos),
+			Reason:   fmt.Sprintf("annotation %s of type %s: %s", tag, t.Name(), err),
+		})
+	}
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+// AllAnnotations returns all annotations of tags on type, in order of comments.
+// annotations of all tags are returned if tags are empty, and repeated annotations of same tag are returned separately.
+func (t *TypeInfo) AllAnnotations(tags ...string) ([]*AnnotationInfo, error) {
+	var infos []*AnnotationInfo
+	for _, doc := range t.Comments() {
+		found, err := parseAnnotations(doc, tags)
+		if err != nil {
+			return nil, err
+		}
+		for _, info := range found {
+			info.Position = t.PackageInfo.position(info.Pos)
+		}
+		infos = append(infos, found...)
+	}
+	return infos, nil
+}
+
+// addArgs adds arguments in text of line. returns true if line ends with "\".
+func (info *AnnotationInfo) addArgs(text string, pos token.Pos) (bool, error) {
+	continued := false
+	if trimmed := strings.TrimRight(text, " \t"); strings.HasSuffix(trimmed, "\\") {
+		text = trimmed[:len(trimmed)-1]
+		continued = true
+	}
+	args, err := parseAnnotationArgs(text, pos)
+	if err != nil {
+		return false, err
+	}
+	info.Args = append(info.Args, args...)
+	return continued, nil
+}
+
+// annotationText returns text of arguments in comment c that has directive, and offset of it in comment.
+func annotationText(c *ast.Comment, directive string) (string, int, bool) {
+	text := strings.TrimLeft(c.Text, "/ ")
+	offset := len(c.Text) - len(text)
+	if !strings.HasPrefix(text, directive) {
+		return "", 0, false
+	}
+	offset += len(directive)
+	text = text[len(directive):]
+	if len(text) > 0 && text[0] != ' ' && text[0] != ':' {
+		return "", 0, false
+	}
+	if strings.HasPrefix(text, ":") {
+		offset++
+		text = text[1:]
+	}
+	return text, offset, true
+}
+
+// isAnnotationOption returns true if comment c is continuation line like "// +jwg:option key=value", otherwise returns false.
+// offset is offset of arguments that is returned by annotationText.
+func isAnnotationOption(c *ast.Comment, offset int) bool {
+	rest := c.Text[offset-1:]
+	if !strings.HasPrefix(rest, annotationOption) {
+		return false
+	}
+	rest = rest[len(annotationOption):]
+	return rest == "" || rest[0] == ' ' || rest[0] == '\t'
+}
+
+// parseAnnotationArgs parses arguments in text, pos is position of text.
+func parseAnnotationArgs(text string, pos token.Pos) ([]*AnnotationArg, error) {
+	var args []*AnnotationArg
+	i := 0
+	for i < len(text) {
+		if text[i] == ' ' || text[i] == '\t' || text[i] == ',' {
+			i++
+			continue
+		}
+		start := i
+		for i < len(text) && text[i] != '=' && text[i] != ' ' && text[i] != '\t' && text[i] != ',' {
+			i++
+		}
+		arg := &AnnotationArg{Key: text[start:i], Pos: pos + token.Pos(start)}
+		if arg.Key == "" {
+			return nil, fmt.Errorf("argument has no key at column %d", start+1)
+		}
+		if i < len(text) && text[i] == '=' {
+			i++
+			arg.HasValue = true
+			if i < len(text) && text[i] == '"' {
+				end := i + 1
+				for end < len(text) && text[end] != '"' {
+					if text[end] == '\\' {
+						end++
+					}
+					end++
+				}
+				if end >= len(text) {
+					return nil, fmt.Errorf("argument %s has unterminated quoted value", arg.Key)
+				}
+				value, err := strconv.Unquote(text[i : end+1])
+				if err != nil {
+					return nil, fmt.Errorf("argument %s has malformed quoted value: %s", arg.Key, err)
+				}
+				arg.Value = value
+				i = end + 1
+			} else {
+				valueStart := i
+				for i < len(text) && text[i] != ' ' && text[i] != '\t' && text[i] != ',' {
+					i++
+				}
+				arg.Value = text[valueStart:i]
+			}
+		}
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+// UnmarshalAnnotation populates struct that v points with arguments of annotation tag of type.
+// continuation lines are merged, see TypeInfo.Annotation.
+// field of struct is bound to argument by `gen:"key"` tag, lower camel case of field name is used if it is omitted,
+// and `gen:"-"` field is ignored. `gen:"key,required"` makes argument required,
+// and `gen:"key,expand"` expands variables and placeholders in value by ExpandValue.
+// default value is specified by `gen-default:"value"` tag.
+// supported field types are string, bool, integers, floats, time.Duration and slices of them.
+// argument of slice field can be repeated. flag argument without value is true for bool field.
+// returns *AnnotationError with position of argument if arguments are malformed.
+func (t *TypeInfo) UnmarshalAnnotation(tag string, v interface{}) error {
+	info, err := t.Annotation(tag)
+	if err != nil {
+		pos := t.TypeSpec.Pos()
+		if doc := t.Doc(); doc != nil {
+			pos = doc.Pos()
+		}
+		return &AnnotationError{Position: t.PackageInfo.position(pos), Reason: err.Error()}
+	}
+	if info == nil {
+		return fmt.Errorf("type %s is not annotated with %s", t.Name(), tag)
+	}
+	return t.PackageInfo.unmarshalAnnotationArgs(info.Args, info.Pos, v, t.ExpandContext())
+}
+
+// UnmarshalAnnotation populates struct that v points with arguments of annotation comment c that has directive.
+// see TypeInfo.UnmarshalAnnotation.
+func (pkg *PackageInfo) UnmarshalAnnotation(c *ast.Comment, directive string, v interface{}) error {
+	args, err := ParseAnnotationArgs(c, directive)
+	if err != nil {
+		return &AnnotationError{Position: pkg.position(c.Pos()), Reason: err.Error()}
+	}
+	return pkg.unmarshalAnnotationArgs(args, c.Pos(), v, &ExpandContext{Package: pkg.expandPackage()})
+}
+
+// position returns position of pos, or zero value if pkg has no FileSet.
+func (pkg *PackageInfo) position(pos token.Pos) token.Position {
+	if pkg == nil || pkg.FileSet == nil {
+		return token.Position{}
+	}
+	return pkg.FileSet.Position(pos)
+}
+
+// argPosition returns position of arg, or name of config file for arg that is given by config.
+func (pkg *PackageInfo) argPosition(arg *AnnotationArg) token.Position {
+	if arg.Pos == token.NoPos && arg.configFile != "" {
+		return token.Position{Filename: arg.configFile}
+	}
+	return pkg.position(arg.Pos)
+}
+
+// unmarshalAnnotationArgs populates struct that v points with args, pos is position of annotation.
+// values of field that has `gen:"key,expand"` tag are expanded by ExpandValue with ctx.
+func (pkg *PackageInfo) unmarshalAnnotationArgs(args []*AnnotationArg, pos token.Pos, v interface{}, ctx *ExpandContext) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("v must be non-nil pointer to struct")
+	}
+	rv = rv.Elem()
+
+	type binding struct {
+		field    reflect.Value
+		required bool
+		expand   bool
+		found    bool
+	}
+	bindings := make(map[string]*binding)
+	var keys []string
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		key, opts := SplitTagValue(sf.Tag.Get(SkipTagKey))
+		if key == "-" && len(opts) == 0 {
+			continue
+		}
+		if key == "" {
+			key = LowerCamelCase(sf.Name)
+		}
+		b := &binding{field: rv.Field(i)}
+		for _, opt := range opts {
+			switch opt {
+			case "required":
+				b.required = true
+			case "expand":
+				b.expand = true
+			}
+		}
+		if def, ok := sf.Tag.Lookup(SkipTagKey + "-default"); ok {
+			if b.expand {
+				expanded, err := ExpandValue(def, ctx, nil)
+				if err != nil {
+					return fmt.Errorf("default value of %s: %s", sf.Name, err)
+				}
+				def = expanded
+			}
+			if err := setAnnotationValue(b.field, def, true); err != nil {
+				return fmt.Errorf("default value of %s: %s", sf.Name, err)
+			}
+		}
+		bindings[key] = b
+		keys = append(keys, key)
+	}
+
+	reset := make(map[string]bool)
+	for _, arg := range args {
+		b, ok := bindings[arg.Key]
+		if !ok {
+			return &AnnotationError{Position: pkg.argPosition(arg), Key: arg.Key, Reason: fmt.Sprintf("unknown argument %s", arg.Key)}
+		}
+		if b.field.Kind() == reflect.Slice && !reset[arg.Key] {
+			// default value is replaced by arguments.
+			b.field.Set(reflect.Zero(b.field.Type()))
+			reset[arg.Key] = true
+		}
+		value := arg.Value
+		if b.expand && arg.HasValue {
+			expanded, err := ExpandValue(value, ctx, nil)
+			if err != nil {
+				return &AnnotationError{Position: pkg.argPosition(arg), Key: arg.Key, Reason: fmt.Sprintf("argument %s: %s", arg.Key, err)}
+			}
+			value = expanded
+		}
+		if err := setAnnotationValue(b.field, value, arg.HasValue); err != nil {
+			return &AnnotationError{Position: pkg.argPosition(arg), Key: arg.Key, Reason: fmt.Sprintf("argument %s: %s", arg.Key, err)}
+		}
+		b.found = true
+	}
+	for _, key := range keys {
+		if b := bindings[key]; b.required && !b.found {
+			return &AnnotationError{Position: pkg.position(pos), Key: key, Reason: fmt.Sprintf("argument %s is required", key)}
+		}
+	}
+	return nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setAnnotationValue sets value to field with type conversion.
+func setAnnotationValue(field reflect.Value, value string, hasValue bool) error {
+	if field.Kind() == reflect.Slice {
+		elem := reflect.New(field.Type().Elem()).Elem()
+		if err := setAnnotationValue(elem, value, hasValue); err != nil {
+			return err
+		}
+		field.Set(reflect.Append(field, elem))
+		return nil
+	}
+	if !hasValue && field.Kind() != reflect.Bool {
+		return errors.New("value is required")
+	}
+
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		if !hasValue {
+			field.SetBool(true)
+			return nil
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%q is not bool", value)
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 0, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not %s", value, field.Type())
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 0, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not %s", value, field.Type())
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not %s", value, field.Type())
+		}
+		field.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
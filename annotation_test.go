@@ -0,0 +1,279 @@
+package genbase
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTypeInfoUnmarshalAnnotation(t *testing.T) {
+	type options struct {
+		Output    string        `gen:"output,required"`
+		Suffix    string        `gen-default:"JSON"`
+		OmitEmpty bool          `gen:"omitempty"`
+		Limit     int           `gen-default:"10"`
+		Timeout   time.Duration `gen:"timeout"`
+		Tags      []string      `gen:"tag" gen-default:"json"`
+		Ignored   string        `gen:"-"`
+	}
+
+	parse := func(annotation string) (*options, error) {
+		p := &Parser{}
+		pInfo, err := p.ParseStringSource("main.go", "package sample\n\n"+annotation+"\ntype Sample struct{}\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+		opts := &options{}
+		err = pInfo.CollectTypeInfos([]string{"Sample"})[0].UnmarshalAnnotation("+jwg", opts)
+		return opts, err
+	}
+
+	opts, err := parse(`// +jwg: output=sample_json.go, omitempty limit=0x20 timeout=1s tag=a tag="b c"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.Output != "sample_json.go" || opts.Suffix != "JSON" || !opts.OmitEmpty || opts.Limit != 32 || opts.Timeout != time.Second {
+		t.Errorf("unexpected: %#v", opts)
+	}
+	if v := strings.Join(opts.Tags, ","); v != "a,b c" {
+		t.Errorf("unexpected: %s", v)
+	}
+
+	opts, err = parse(`// +jwg output=a.go`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.Output != "a.go" || opts.Limit != 10 || len(opts.Tags) != 1 || opts.Tags[0] != "json" {
+		t.Errorf("unexpected: %#v", opts)
+	}
+
+	for annotation, expect := range map[string]string{
+		`// +jwg: output=a.go limit=abc`:  `main.go:3:22: argument limit: "abc" is not int`,
+		`// +jwg: output=a.go unknown=1`:  "main.go:3:22: unknown argument unknown",
+		`// +jwg: omitempty`:              "main.go:3:1: argument output is required",
+		`// +jwg: output="a.go`:           "main.go:3:1: argument output has unterminated quoted value",
+		`// +jwg: output=a.go Ignored=ok`: "main.go:3:22: unknown argument Ignored",
+	} {
+		_, err := parse(annotation)
+		if err == nil {
+			t.Errorf("unexpected: %s", annotation)
+			continue
+		}
+		if _, ok := err.(*AnnotationError); !ok {
+			t.Errorf("unexpected: %T", err)
+		}
+		if v := err.Error(); v != expect {
+			t.Errorf("unexpected: %s %s", annotation, v)
+		}
+	}
+}
+
+func FuzzParseAnnotationArgs(f *testing.F) {
+	for _, seed := range []string{
+		`// +jwg: output=a.go, omitempty`,
+		`// +jwg name="a \"b\""`,
+		`// +jwg: output="a.go`,
+		`// +jwg: ="x"`,
+		`// +jwg: a=\`,
+		`// +jwg: a="\`,
+		"// +jwg: ü=ß,\t\xff",
+		"// +jwg:" + strings.Repeat(" a=b", 1000),
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, text string) {
+		c := &ast.Comment{Slash: 1, Text: text}
+		args, err := ParseAnnotationArgs(c, "+jwg")
+		if err != nil {
+			return
+		}
+		for _, arg := range args {
+			if arg.Key == "" {
+				t.Errorf("unexpected: empty key in %q", text)
+			}
+			if arg.Pos < c.Pos() || c.End() <= arg.Pos {
+				t.Errorf("unexpected: %d is out of %q", arg.Pos, text)
+			}
+			if !arg.HasValue && arg.Value != "" {
+				t.Errorf("unexpected: %#v", arg)
+			}
+		}
+
+		type options struct {
+			Output string
+			Limit  int
+			Flag   bool
+			Tags   []string
+		}
+		_ = (*PackageInfo)(nil).UnmarshalAnnotation(c, "+jwg", &options{})
+	})
+}
+
+func TestTypeInfoAnnotationContinuation(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `package sample
+
+// Sample is sample.
+// +jwg: output=sample_json.go, \
+//   omitempty
+// unrelated line
+// +jwg:option limit=20
+// +jwg:optional=1
+type Sample struct{}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := pInfo.CollectTypeInfos([]string{"Sample"})[0]
+	info, err := st.Annotation("+jwg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.Comments) != 3 || info.Tag != "+jwg" {
+		t.Fatalf("unexpected: %#v", info)
+	}
+	var ss []string
+	for _, arg := range info.Args {
+		ss = append(ss, fmt.Sprintf("%s=%s@%s", arg.Key, arg.Value, pInfo.FileSet.Position(arg.Pos)))
+	}
+	if v := strings.Join(ss, " "); v != "output=sample_json.go@main.go:4:10 omitempty=@main.go:5:6 limit=20@main.go:7:16" {
+		t.Errorf("unexpected: %s", v)
+	}
+
+	opts := &struct {
+		Output    string
+		OmitEmpty bool `gen:"omitempty"`
+		Limit     int
+	}{}
+	if err := st.UnmarshalAnnotation("+jwg", opts); err != nil {
+		t.Fatal(err)
+	}
+	if opts.Output != "sample_json.go" || !opts.OmitEmpty || opts.Limit != 20 {
+		t.Errorf("unexpected: %#v", opts)
+	}
+
+	if info, err := st.Annotation("+qbg"); info != nil || err != nil {
+		t.Errorf("unexpected: %v %v", info, err)
+	}
+}
+
+func TestTypeInfoAllAnnotations(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `package sample
+
+// +jwg: output=a.go
+// +qbg
+// +jwg: output=b.go
+// +jwg:option omitempty
+// +qbg:option limit=1
+type Sample struct{}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	describe := func(infos []*AnnotationInfo) string {
+		var ss []string
+		for _, info := range infos {
+			var keys []string
+			for _, arg := range info.Args {
+				keys = append(keys, arg.Key)
+			}
+			ss = append(ss, info.Tag+"("+strings.Join(keys, ",")+")")
+		}
+		return strings.Join(ss, " ")
+	}
+
+	st := pInfo.CollectTypeInfos([]string{"Sample"})[0]
+	infos, err := st.AllAnnotations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := describe(infos); v != "+jwg(output) +qbg(limit) +jwg(output,omitempty)" {
+		t.Errorf("unexpected: %s", v)
+	}
+
+	tagged := pInfo.CollectTaggedTypeInfos("+jwg")
+	if v := describe(tagged[0].Annotations); v != "+jwg(output) +jwg(output,omitempty)" {
+		t.Errorf("unexpected: %s", v)
+	}
+	if v := describe(pInfo.CollectTaggedTypeInfos("+qbg")[0].Annotations); v != "+qbg(limit)" {
+		t.Errorf("unexpected: %s", v)
+	}
+	// annotations of Type are all annotation lines, regardless of collected tag.
+	if v := NewType(tagged[0]).Annotations; len(v) != 5 || v[2] != "// +jwg: output=b.go" {
+		t.Errorf("unexpected: %v", v)
+	}
+}
+
+func TestTypeInfoAnnotationInfo(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `package sample
+
+// Sample is sample.
+// +jwg: output=a.go \
+//   omitempty
+type Sample struct{}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := pInfo.CollectTaggedTypeInfos("+jwg")[0].AnnotationInfo
+	if info == nil {
+		t.Fatal("unexpected: AnnotationInfo is nil")
+	}
+	if info.Tag != "+jwg" || len(info.Args) != 2 || info.Raw != "// +jwg: output=a.go \\\n//   omitempty" {
+		t.Errorf("unexpected: %#v", info)
+	}
+	if v := info.Position.String(); v != "main.go:4:1" || pInfo.FileSet.Position(info.Pos) != info.Position {
+		t.Errorf("unexpected: %s", v)
+	}
+}
+
+func TestPackageInfoParseTaggedTypeInfos(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `package sample
+
+// +test: output=a.go
+type A struct{}
+
+// +test: name="unterminated
+type B struct{}
+
+// +test: \
+// =value
+type C struct{}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = pInfo.ParseTaggedTypeInfos("+test")
+	errs, ok := err.(AnnotationErrors)
+	if !ok || len(errs) != 2 {
+		t.Fatalf("unexpected: %v", err)
+	}
+	if errs[0].Position.Line != 6 || !strings.Contains(errs[0].Reason, "type B") || !strings.Contains(errs[0].Reason, "unterminated") {
+		t.Errorf("unexpected: %s", errs[0])
+	}
+	if errs[1].Position.Line != 10 {
+		t.Errorf("unexpected: %s", errs[1])
+	}
+	if _, err := pInfo.FindTaggedTypeInfos("+test"); err == nil {
+		t.Error("unexpected: error is nil")
+	}
+
+	typeInfos, err := pInfo.ParseTaggedTypeInfos("+other")
+	if err != nil || len(typeInfos) != 0 {
+		t.Errorf("unexpected: %v %v", typeInfos, err)
+	}
+	typeInfos, err = pInfo.ParseTaggedTypeInfos("+test")
+	if err == nil || typeInfos != nil {
+		t.Errorf("unexpected: %v", typeInfos)
+	}
+	if v := len(pInfo.CollectTaggedTypeInfos("+test")); v != 3 {
+		t.Errorf("unexpected: %d", v)
+	}
+}
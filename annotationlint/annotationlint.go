@@ -0,0 +1,167 @@
+// Package annotationlint provides analysis.Analyzer that reports malformed genbase annotations
+// and stale generated files.
+package annotationlint
+
+import (
+	"go/ast"
+	"go/token"
+	"os"
+	"strings"
+
+	"github.com/favclip/genbase"
+	"golang.org/x/tools/go/analysis"
+)
+
+// Analyzer reports malformed annotations and stale generated files.
+var Analyzer = &analysis.Analyzer{
+	Name: "annotationlint",
+	Doc: `check genbase annotations
+
+annotationlint reports annotations attached to non-type declarations,
+misspelled annotations, and generated files older than annotated sources.
+annotations to check are specified by -tags flag.`,
+	Run: run,
+}
+
+var tags string
+
+func init() {
+	Analyzer.Flags.StringVar(&tags, "tags", "", "comma separated annotation tags. e.g. +jwg,+qbg")
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	var directives []string
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			directives = append(directives, tag)
+		}
+	}
+	if len(directives) == 0 {
+		return nil, nil
+	}
+
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				checkMisplaced(pass, decl.Doc, directives)
+			case *ast.GenDecl:
+				if decl.Tok == token.TYPE {
+					continue
+				}
+				checkMisplaced(pass, decl.Doc, directives)
+				for _, spec := range decl.Specs {
+					if vs, ok := spec.(*ast.ValueSpec); ok {
+						checkMisplaced(pass, vs.Doc, directives)
+					}
+				}
+			}
+		}
+		for _, cg := range file.Comments {
+			for _, c := range cg.List {
+				checkMisspelled(pass, c, directives)
+			}
+		}
+	}
+
+	checkStale(pass, directives)
+
+	return nil, nil
+}
+
+func checkMisplaced(pass *analysis.Pass, doc *ast.CommentGroup, directives []string) {
+	for _, directive := range directives {
+		if c := genbase.FindAnnotation(doc, directive); c != nil {
+			pass.Reportf(c.Pos(), "annotation %s must be attached to type declaration", directive)
+		}
+	}
+}
+
+func checkMisspelled(pass *analysis.Pass, c *ast.Comment, directives []string) {
+	text := strings.TrimLeft(c.Text, "/ ")
+	if !strings.HasPrefix(text, "+") {
+		return
+	}
+	fields := strings.FieldsFunc(text, func(r rune) bool { return r == ' ' || r == ':' })
+	word := fields[0]
+	for _, directive := range directives {
+		if word == directive {
+			return
+		}
+	}
+	for _, directive := range directives {
+		switch {
+		case word == "+" && len(fields) > 1 && "+"+fields[1] == directive:
+			pass.Reportf(c.Pos(), "malformed annotation, did you mean %s?", directive)
+			return
+		case strings.EqualFold(word, directive) || genbase.EditDistance(word, directive) == 1:
+			pass.Reportf(c.Pos(), "unknown annotation %s, did you mean %s?", word, directive)
+			return
+		}
+	}
+}
+
+// checkStale reports generated file that is older than source file annotated for its generator.
+// generator name is annotation without "+". e.g. "jwg" for "+jwg".
+func checkStale(pass *analysis.Pass, directives []string) {
+	pkg := &genbase.PackageInfo{}
+	generated := make(map[string]*ast.File)
+	for _, file := range pass.Files {
+		if cmd := generatedBy(file); cmd != "" {
+			generated[cmd] = file
+			continue
+		}
+		pkg.Files = append(pkg.Files, (*genbase.FileInfo)(file))
+	}
+	if len(generated) == 0 {
+		return
+	}
+
+	for _, directive := range directives {
+		genFile, ok := generated[strings.TrimPrefix(directive, "+")]
+		if !ok {
+			continue
+		}
+		genStat, err := os.Stat(pass.Fset.File(genFile.Pos()).Name())
+		if err != nil {
+			continue
+		}
+		for _, t := range pkg.CollectTaggedTypeInfos(directive) {
+			srcName := pass.Fset.File(t.FileInfo.Package).Name()
+			srcStat, err := os.Stat(srcName)
+			if err != nil {
+				continue
+			}
+			if srcStat.ModTime().After(genStat.ModTime()) {
+				pass.Reportf(genFile.Package, "generated file is older than %s, regenerate it", srcName)
+				break
+			}
+		}
+	}
+}
+
+// generatedBy returns command name in "Code generated by" comment of file.
+func generatedBy(file *ast.File) string {
+	for _, cg := range file.Comments {
+		if cg.Pos() > file.Package {
+			break
+		}
+		for _, c := range cg.List {
+			text := strings.TrimPrefix(c.Text, "//")
+			text = strings.TrimSpace(text)
+			if !strings.HasPrefix(text, "Code generated by ") || !strings.Contains(text, "DO NOT EDIT") {
+				continue
+			}
+			fields := strings.Fields(strings.TrimPrefix(text, "Code generated by "))
+			if len(fields) == 0 {
+				continue
+			}
+			cmd := strings.TrimSuffix(fields[0], ";")
+			if idx := strings.LastIndexAny(cmd, `/\`); idx != -1 {
+				cmd = cmd[idx+1:]
+			}
+			return cmd
+		}
+	}
+	return ""
+}
@@ -0,0 +1,54 @@
+package annotationlint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	if err := Analyzer.Flags.Set("tags", "+sample"); err != nil {
+		t.Fatal(err)
+	}
+	defer Analyzer.Flags.Set("tags", "")
+
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "a")
+}
+
+func TestAnalyzerStale(t *testing.T) {
+	if err := Analyzer.Flags.Set("tags", "+sample"); err != nil {
+		t.Fatal(err)
+	}
+	defer Analyzer.Flags.Set("tags", "")
+
+	dir, err := ioutil.TempDir("", "annotationlint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	pkgDir := filepath.Join(dir, "src", "stale")
+	if err := os.MkdirAll(pkgDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	genName := filepath.Join(pkgDir, "model_sample.go")
+	srcName := filepath.Join(pkgDir, "model.go")
+	err = ioutil.WriteFile(genName, []byte("// Code generated by sample; DO NOT EDIT\n\npackage stale // want `generated file is older than .*model.go, regenerate it`\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(srcName, []byte("package stale\n\n// +sample\ntype A struct{}\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(genName, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, dir, Analyzer, "stale")
+}
@@ -0,0 +1,20 @@
+package a
+
+// A is annotated.
+// +sample
+type A struct{}
+
+// B is misspelled.
+// +sampel // want `unknown annotation \+sampel, did you mean \+sample\?`
+type B struct{}
+
+// C is malformed.
+// + sample // want `malformed annotation, did you mean \+sample\?`
+type C struct{}
+
+// F is function.
+// +sample // want `annotation \+sample must be attached to type declaration`
+func F() {}
+
+// +other
+type D struct{}
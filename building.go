@@ -3,8 +3,12 @@ package genbase
 import (
 	"bytes"
 	"fmt"
+	"go/build/constraint"
 	"go/format"
+	"go/types"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -14,6 +18,16 @@ type Generator struct {
 
 	Buf             bytes.Buffer // Accumulated output.
 	RequiredImports []*Import
+	// LocalPrefix is comma separated import path prefixes of local packages. e.g. "github.com/favclip"
+	// imports of them are grouped after external packages like goimports -local.
+	LocalPrefix string
+	// Logger receives diagnostic messages, Logger of Parser that parsed Package is used if it is nil.
+	Logger Logger
+
+	buildConstraint constraint.Expr
+	legacyBuild     bool
+	licenseHeader   string
+	origins         []*sourceOrigin
 }
 
 // Import is import statement information for generated code.
@@ -39,9 +53,87 @@ func (g *Generator) AddImport(path string, ident string) {
 	if strings.HasPrefix(path, `"`) && strings.HasSuffix(path, `"`) {
 		path = path[1 : len(path)-1]
 	}
+	g.logger().Debugf("required import %q", path)
 	g.RequiredImports = append(g.RequiredImports, &Import{Ident: ident, Path: path})
 }
 
+// Qualifier returns qualifier func for RenderTypeName.
+// packages other than generating package are added to RequiredImports when they are used.
+func (g *Generator) Qualifier() func(pkgPath string) string {
+	return func(pkgPath string) string {
+		if pkgPath == "" || (g.Package != nil && pkgPath == g.Package.ImportPath()) {
+			return ""
+		}
+
+		used := make(map[string]bool)
+		for _, imp := range g.RequiredImports {
+			ident := imp.Ident
+			if ident == "" {
+				ident = g.packageName(imp.Path)
+			}
+			if imp.Path == pkgPath {
+				return ident
+			}
+			used[ident] = true
+		}
+
+		// alias is written if name of package is not same as last element of path. e.g. renamed "example.com/pkgname"
+		ident := g.packageName(pkgPath)
+		base := ident
+		for i := 2; used[ident]; i++ {
+			ident = base + strconv.Itoa(i)
+		}
+		if ident == pkgPath[strings.LastIndex(pkgPath, "/")+1:] {
+			g.AddImport(pkgPath, "")
+		} else {
+			g.AddImport(pkgPath, ident)
+		}
+		return ident
+	}
+}
+
+// packageName returns name of package that is imported by generating package, see PackageInfo.packageName.
+func (g *Generator) packageName(path string) string {
+	return g.Package.packageName(path)
+}
+
+// findImportedPackage returns package of path in imports of p transitively, or nil if it is not found.
+func findImportedPackage(p *types.Package, path string, visited map[*types.Package]bool) *types.Package {
+	visited[p] = true
+	for _, imported := range p.Imports() {
+		if imported.Path() == path {
+			return imported
+		}
+	}
+	for _, imported := range p.Imports() {
+		if visited[imported] {
+			continue
+		}
+		if found := findImportedPackage(imported, path, visited); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// SetBuildConstraint sets build constraint of generated code. e.g. "linux && amd64", "appengine"
+// PrintHeader prints it as //go:build line, and // +build lines too if legacy is true.
+// empty expr removes build constraint.
+func (g *Generator) SetBuildConstraint(expr string, legacy bool) error {
+	if strings.TrimSpace(expr) == "" {
+		g.buildConstraint = nil
+		g.legacyBuild = false
+		return nil
+	}
+	x, err := constraint.Parse("//go:build " + expr)
+	if err != nil {
+		return fmt.Errorf("parsing build constraint %q: %s", expr, err)
+	}
+	g.buildConstraint = x
+	g.legacyBuild = legacy
+	return nil
+}
+
 // PrintHeader is print header of generated code to buffer.
 func (g *Generator) PrintHeader(cmdName string, args *[]string) {
 	if cmdName == "" && args != nil {
@@ -58,20 +150,92 @@ func (g *Generator) PrintHeader(cmdName string, args *[]string) {
 	}
 
 	// Print the header and package clause.
+	if g.licenseHeader != "" {
+		g.Printf("%s\n", g.licenseHeader)
+	}
 	g.Printf("// Code generated by %s %s; DO NOT EDIT\n", cmdName, strings.Join(as, " "))
 	g.Printf("\n")
-	g.Printf("package %s\n", g.Package.Name())
+	if g.buildConstraint != nil {
+		g.Printf("//go:build %s\n", g.buildConstraint)
+		if g.legacyBuild {
+			lines, err := constraint.PlusBuildLines(g.buildConstraint)
+			if err == nil {
+				for _, line := range lines {
+					g.Printf("%s\n", line)
+				}
+			}
+		}
+		g.Printf("\n")
+	}
+	g.Printf("package %s\n\n", g.Package.Name())
 	g.Printf("import (\n")
-	for _, imp := range g.RequiredImports {
-		g.Printf("%s \"%s\"\n", imp.Ident, imp.Path)
+	for i, group := range g.importGroups() {
+		if i != 0 {
+			g.Printf("\n")
+		}
+		for _, imp := range group {
+			if imp.Ident == "" {
+				g.Printf("\t\"%s\"\n", imp.Path)
+			} else {
+				g.Printf("\t%s \"%s\"\n", imp.Ident, imp.Path)
+			}
+		}
 	}
 	g.Printf(")\n")
 }
 
+// importGroups returns RequiredImports grouped by stdlib, external and local packages, sorted by path in each group.
+// duplicated imports are removed and empty groups are omitted.
+func (g *Generator) importGroups() [][]*Import {
+	groups := make([][]*Import, 3)
+	seen := make(map[Import]bool)
+	for _, imp := range g.RequiredImports {
+		if seen[*imp] {
+			continue
+		}
+		seen[*imp] = true
+		switch {
+		case g.isLocalImport(imp.Path):
+			groups[2] = append(groups[2], imp)
+		case isStdlibPath(imp.Path):
+			groups[0] = append(groups[0], imp)
+		default:
+			groups[1] = append(groups[1], imp)
+		}
+	}
+
+	var result [][]*Import
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		sort.SliceStable(group, func(i, j int) bool {
+			if group[i].Path != group[j].Path {
+				return group[i].Path < group[j].Path
+			}
+			return group[i].Ident < group[j].Ident
+		})
+		result = append(result, group)
+	}
+	return result
+}
+
+// isLocalImport returns true if path has one of LocalPrefix, otherwise returns false.
+func (g *Generator) isLocalImport(path string) bool {
+	for _, prefix := range strings.Split(g.LocalPrefix, ",") {
+		prefix = strings.TrimSpace(prefix)
+		if prefix != "" && (path == prefix || strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/")) {
+			return true
+		}
+	}
+	return false
+}
+
 // Format is apply gofmt to generated code.
 func (g *Generator) Format() ([]byte, error) {
 	src, err := format.Source(g.Buf.Bytes())
 	if err != nil {
+		g.logger().Warnf("formatting generated code of %s: %s", g.Package.Name(), err)
 		return g.Buf.Bytes(), err
 	}
 
@@ -0,0 +1,71 @@
+package genbase
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGeneratorSetBuildConstraint(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", "package sample\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewGenerator(pInfo)
+	if err := g.SetBuildConstraint("linux && (amd64 || arm64)", true); err != nil {
+		t.Fatal(err)
+	}
+	g.PrintHeader("sample", &[]string{"-type", "Sample"})
+	src, err := g.Format()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := "// Code generated by sample -type Sample; DO NOT EDIT\n\n//go:build linux && (amd64 || arm64)\n// +build linux\n// +build amd64 arm64\n\npackage sample\n"
+	if !strings.HasPrefix(string(src), expect) {
+		t.Fatalf("unexpected: %s", src)
+	}
+
+	g = NewGenerator(pInfo)
+	if err := g.SetBuildConstraint("appengine", false); err != nil {
+		t.Fatal(err)
+	}
+	g.PrintHeader("sample", &[]string{})
+	if v := g.Buf.String(); !strings.Contains(v, "\n//go:build appengine\n\npackage") || strings.Contains(v, "+build") {
+		t.Fatalf("unexpected: %s", v)
+	}
+
+	if err := g.SetBuildConstraint("linux &&", false); err == nil {
+		t.Fatalf("unexpected: %v", err)
+	}
+}
+
+func TestGeneratorPrintHeaderImportGroups(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", "package sample\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewGenerator(pInfo)
+	g.LocalPrefix = "github.com/favclip/genbase, example.com/local"
+	g.AddImport("github.com/favclip/genbase/jsonschema", "")
+	g.AddImport("golang.org/x/tools/go/packages", "")
+	g.AddImport("strings", "")
+	g.AddImport("example.com/localother", "")
+	g.AddImport(`"fmt"`, "")
+	g.AddImport("example.com/local/sub", "sub2")
+	g.AddImport("strings", "")
+	g.PrintHeader("sample", &[]string{})
+	src, err := g.Format()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := "import (\n\t\"fmt\"\n\t\"strings\"\n\n\t\"example.com/localother\"\n\t\"golang.org/x/tools/go/packages\"\n\n\tsub2 \"example.com/local/sub\"\n\t\"github.com/favclip/genbase/jsonschema\"\n)\n"
+	if !strings.HasSuffix(string(src), expect) {
+		t.Errorf("unexpected: %s", src)
+	}
+	if string(src) != g.Buf.String() {
+		t.Errorf("unexpected: %s", g.Buf.String())
+	}
+}
@@ -0,0 +1,124 @@
+package genbase
+
+import (
+	"strings"
+	"unicode"
+)
+
+// CommonInitialisms is initialisms that are written in upper case in Go names, from golint.
+var CommonInitialisms = []string{
+	"ACL", "API", "ASCII", "CPU", "CSS", "DNS", "EOF", "GUID", "HTML", "HTTP", "HTTPS", "ID",
+	"IP", "JSON", "LHS", "QPS", "RAM", "RHS", "RPC", "SLA", "SMTP", "SQL", "SSH", "TCP",
+	"TLS", "TTL", "UDP", "UI", "UID", "UUID", "URI", "URL", "UTF8", "VM", "XML", "XMPP",
+	"XSRF", "XSS",
+}
+
+// CaseConverter converts case of names with initialisms table.
+type CaseConverter struct {
+	initialisms map[string]bool
+}
+
+// DefaultCaseConverter is CaseConverter with CommonInitialisms.
+var DefaultCaseConverter = NewCaseConverter(CommonInitialisms...)
+
+// NewCaseConverter creates new CaseConverter that respects initialisms. e.g. "ID", "URL"
+func NewCaseConverter(initialisms ...string) *CaseConverter {
+	c := &CaseConverter{initialisms: make(map[string]bool)}
+	for _, s := range initialisms {
+		c.initialisms[strings.ToUpper(s)] = true
+	}
+	return c
+}
+
+// CamelCase converts s to CamelCase by DefaultCaseConverter. e.g. "user_id" to "UserID"
+func CamelCase(s string) string {
+	return DefaultCaseConverter.CamelCase(s)
+}
+
+// LowerCamelCase converts s to lowerCamelCase by DefaultCaseConverter. e.g. "user_id" to "userID"
+func LowerCamelCase(s string) string {
+	return DefaultCaseConverter.LowerCamelCase(s)
+}
+
+// SnakeCase converts s to snake_case. e.g. "UserID" to "user_id"
+func SnakeCase(s string) string {
+	return strings.ToLower(strings.Join(SplitWords(s), "_"))
+}
+
+// KebabCase converts s to kebab-case. e.g. "UserID" to "user-id"
+func KebabCase(s string) string {
+	return strings.ToLower(strings.Join(SplitWords(s), "-"))
+}
+
+// ScreamingSnakeCase converts s to SCREAMING_SNAKE_CASE. e.g. "UserID" to "USER_ID"
+func ScreamingSnakeCase(s string) string {
+	return strings.ToUpper(strings.Join(SplitWords(s), "_"))
+}
+
+// CamelCase converts s to CamelCase, initialisms are written in upper case. e.g. "user_id" to "UserID"
+func (c *CaseConverter) CamelCase(s string) string {
+	var b strings.Builder
+	for _, word := range SplitWords(s) {
+		b.WriteString(c.camelWord(word))
+	}
+	return b.String()
+}
+
+// LowerCamelCase converts s to lowerCamelCase, first word is written in lower case.
+// e.g. "user_id" to "userID", "URLPath" to "urlPath"
+func (c *CaseConverter) LowerCamelCase(s string) string {
+	var b strings.Builder
+	for i, word := range SplitWords(s) {
+		if i == 0 {
+			b.WriteString(strings.ToLower(word))
+			continue
+		}
+		b.WriteString(c.camelWord(word))
+	}
+	return b.String()
+}
+
+func (c *CaseConverter) camelWord(word string) string {
+	upper := strings.ToUpper(word)
+	if c.initialisms[upper] {
+		return upper
+	}
+	// plural of initialism. e.g. "ids" to "IDs"
+	if len(word) > 1 && word[len(word)-1] == 's' && c.initialisms[upper[:len(upper)-1]] {
+		return upper[:len(upper)-1] + "s"
+	}
+	return upperFirst(strings.ToLower(word))
+}
+
+// SplitWords splits s into words by separators and case changes, lower "s" after upper case is kept as plural.
+// e.g. "HTTPServer" to "HTTP" and "Server", "user_id" to "user" and "id", "Base64Encode" to "Base64" and "Encode",
+// "APIsList" to "APIs" and "List"
+func SplitWords(s string) []string {
+	var words []string
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		rs := []rune(field)
+		start := 0
+		for i := 1; i < len(rs); i++ {
+			prev, cur := rs[i-1], rs[i]
+			switch {
+			case unicode.IsUpper(cur) && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+				// "userID", "Base64Encode"
+			case unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < len(rs) && unicode.IsLower(rs[i+1]) && !isPluralS(rs, i+1):
+				// "HTTPServer"
+			default:
+				continue
+			}
+			words = append(words, string(rs[start:i]))
+			start = i
+		}
+		words = append(words, string(rs[start:]))
+	}
+	return words
+}
+
+// isPluralS returns true if rs[i] is "s" that ends word. e.g. "s" of "APIs" and "IDsOf"
+func isPluralS(rs []rune, i int) bool {
+	return rs[i] == 's' && (i+1 == len(rs) || !unicode.IsLower(rs[i+1]))
+}
@@ -0,0 +1,62 @@
+package genbase
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitWords(t *testing.T) {
+	expects := map[string][]string{
+		"HTTPServer":   {"HTTP", "Server"},
+		"userID":       {"user", "ID"},
+		"user_id":      {"user", "id"},
+		"Base64Encode": {"Base64", "Encode"},
+		"kebab-case":   {"kebab", "case"},
+		"ID":           {"ID"},
+		"APIs":         {"APIs"},
+		"userIDsOf":    {"user", "IDs", "Of"},
+		"HTTPSession":  {"HTTP", "Session"},
+		"":             nil,
+	}
+	for s, expect := range expects {
+		if v := SplitWords(s); !reflect.DeepEqual(v, expect) {
+			t.Errorf("unexpected: %s %v", s, v)
+		}
+	}
+}
+
+func TestCaseConversion(t *testing.T) {
+	expects := []struct {
+		s, camel, lowerCamel, snake, kebab, screaming string
+	}{
+		{"user_id", "UserID", "userID", "user_id", "user-id", "USER_ID"},
+		{"UserID", "UserID", "userID", "user_id", "user-id", "USER_ID"},
+		{"HTTPServerURL", "HTTPServerURL", "httpServerURL", "http_server_url", "http-server-url", "HTTP_SERVER_URL"},
+		{"api-key", "APIKey", "apiKey", "api_key", "api-key", "API_KEY"},
+		{"created_at", "CreatedAt", "createdAt", "created_at", "created-at", "CREATED_AT"},
+		{"user_ids", "UserIDs", "userIDs", "user_ids", "user-ids", "USER_IDS"},
+		{"APIsList", "APIsList", "apisList", "apis_list", "apis-list", "APIS_LIST"},
+	}
+	for _, expect := range expects {
+		if v := CamelCase(expect.s); v != expect.camel {
+			t.Errorf("unexpected: %s CamelCase %s", expect.s, v)
+		}
+		if v := LowerCamelCase(expect.s); v != expect.lowerCamel {
+			t.Errorf("unexpected: %s LowerCamelCase %s", expect.s, v)
+		}
+		if v := SnakeCase(expect.s); v != expect.snake {
+			t.Errorf("unexpected: %s SnakeCase %s", expect.s, v)
+		}
+		if v := KebabCase(expect.s); v != expect.kebab {
+			t.Errorf("unexpected: %s KebabCase %s", expect.s, v)
+		}
+		if v := ScreamingSnakeCase(expect.s); v != expect.screaming {
+			t.Errorf("unexpected: %s ScreamingSnakeCase %s", expect.s, v)
+		}
+	}
+
+	c := NewCaseConverter("SKU")
+	if v := c.CamelCase("item_sku_id"); v != "ItemSKUId" {
+		t.Fatalf("unexpected: %s", v)
+	}
+}
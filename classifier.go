@@ -0,0 +1,102 @@
+package genbase
+
+import (
+	"sort"
+)
+
+// Classifier is named predicate of field. e.g. "money" for fields of currency amount
+type Classifier func(pkg *PackageInfo, f *FieldInfo) bool
+
+// ClassifierRegistry holds named classifiers, so generators and templates query fields by one mechanism.
+type ClassifierRegistry struct {
+	classifiers map[string]Classifier
+}
+
+// DefaultClassifierRegistry is ClassifierRegistry that is used by FieldInfo.Is.
+// it has built-in classifiers, see RegisterBuiltinClassifiers.
+var DefaultClassifierRegistry = RegisterBuiltinClassifiers(NewClassifierRegistry())
+
+// NewClassifierRegistry creates new empty ClassifierRegistry.
+func NewClassifierRegistry() *ClassifierRegistry {
+	return &ClassifierRegistry{
+		classifiers: make(map[string]Classifier),
+	}
+}
+
+// RegisterBuiltinClassifiers registers classifiers by predicates of FieldInfo to r, and returns r.
+// names are "comparable", "ordered", "nillable", "deep_copy", "json_marshaler", "text_marshaler", "stringer", "sql_scanner",
+// "wrapper", and kinds of wrapper types like "nullable".
+func RegisterBuiltinClassifiers(r *ClassifierRegistry) *ClassifierRegistry {
+	r.Register("comparable", fieldPredicate((*FieldInfo).IsComparable))
+	r.Register("ordered", fieldPredicate((*FieldInfo).IsOrdered))
+	r.Register("nillable", fieldPredicate((*FieldInfo).IsNillable))
+	r.Register("deep_copy", fieldPredicate((*FieldInfo).NeedsDeepCopy))
+	r.Register("json_marshaler", fieldPredicate((*FieldInfo).ImplementsJSONMarshaler))
+	r.Register("text_marshaler", fieldPredicate((*FieldInfo).ImplementsTextMarshaler))
+	r.Register("stringer", fieldPredicate((*FieldInfo).ImplementsStringer))
+	r.Register("sql_scanner", fieldPredicate((*FieldInfo).ImplementsSQLScanner))
+	r.Register("wrapper", func(pkg *PackageInfo, f *FieldInfo) bool {
+		return f.WrapperType(pkg) != nil
+	})
+	for _, kind := range []WrapperKind{WrapperNullable, WrapperUUID, WrapperDecimal, WrapperDate, WrapperKey} {
+		kind := kind
+		r.Register(string(kind), func(pkg *PackageInfo, f *FieldInfo) bool {
+			return f.IsWrapper(pkg, kind)
+		})
+	}
+	return r
+}
+
+// Register registers classifier of name. registered classifier of same name is replaced.
+func (r *ClassifierRegistry) Register(name string, c Classifier) {
+	if r.classifiers == nil {
+		r.classifiers = make(map[string]Classifier)
+	}
+	r.classifiers[name] = c
+}
+
+// Names returns sorted names of registered classifiers.
+func (r *ClassifierRegistry) Names() []string {
+	names := make([]string, 0, len(r.classifiers))
+	for name := range r.classifiers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Has returns true if classifier of name is registered, otherwise returns false.
+func (r *ClassifierRegistry) Has(name string) bool {
+	_, ok := r.classifiers[name]
+	return ok
+}
+
+// Is returns true if classifier of name matches field, otherwise returns false.
+// returns false if classifier is not registered.
+func (r *ClassifierRegistry) Is(pkg *PackageInfo, f *FieldInfo, name string) bool {
+	c, ok := r.classifiers[name]
+	return ok && c(pkg, f)
+}
+
+// Classify returns sorted names of classifiers that match field.
+func (r *ClassifierRegistry) Classify(pkg *PackageInfo, f *FieldInfo) []string {
+	var names []string
+	for _, name := range r.Names() {
+		if r.classifiers[name](pkg, f) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Is returns true if classifier of name in DefaultClassifierRegistry matches field, otherwise returns false.
+func (f *FieldInfo) Is(pkg *PackageInfo, name string) bool {
+	return DefaultClassifierRegistry.Is(pkg, f, name)
+}
+
+// fieldPredicate adapts predicate method of FieldInfo to Classifier.
+func fieldPredicate(predicate func(f *FieldInfo, pkg *PackageInfo) bool) Classifier {
+	return func(pkg *PackageInfo, f *FieldInfo) bool {
+		return predicate(f, pkg)
+	}
+}
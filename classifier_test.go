@@ -0,0 +1,49 @@
+package genbase
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClassifierRegistry(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", "package sample\n"+
+		"import \"database/sql\"\n"+
+		"type Sample struct {\n"+
+		"	Price int64 `money:\"jpy\"`\n"+
+		"	Name  sql.NullString\n"+
+		"	Tags  []string\n"+
+		"}\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := pInfo.CollectTypeInfos([]string{"Sample"})[0].StructType()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := st.FieldInfos()
+
+	if !fields[0].Is(pInfo, "ordered") || fields[2].Is(pInfo, "comparable") || !fields[1].Is(pInfo, "nullable") {
+		t.Error("unexpected: built-in classifiers")
+	}
+	if fields[0].Is(pInfo, "money") {
+		t.Error("unexpected: unknown classifier matches")
+	}
+
+	r := RegisterBuiltinClassifiers(NewClassifierRegistry())
+	r.Register("money", func(pkg *PackageInfo, f *FieldInfo) bool {
+		return f.tagValue("money") != ""
+	})
+	if !r.Has("money") || DefaultClassifierRegistry.Has("money") {
+		t.Error("unexpected: registration")
+	}
+	if !r.Is(pInfo, fields[0], "money") || r.Is(pInfo, fields[1], "money") {
+		t.Error("unexpected: money classifier")
+	}
+	if v := strings.Join(r.Classify(pInfo, fields[0]), ","); v != "comparable,money,ordered" {
+		t.Errorf("unexpected: %s", v)
+	}
+	if v := strings.Join(r.Classify(pInfo, fields[2]), ","); v != "deep_copy,nillable" {
+		t.Errorf("unexpected: %s", v)
+	}
+}
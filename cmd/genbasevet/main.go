@@ -0,0 +1,13 @@
+// genbasevet is vet tool that checks genbase annotations.
+//
+//	go vet -vettool=$(which genbasevet) -annotationlint.tags=+jwg ./...
+package main
+
+import (
+	"github.com/favclip/genbase/annotationlint"
+	"golang.org/x/tools/go/analysis/unitchecker"
+)
+
+func main() {
+	unitchecker.Main(annotationlint.Analyzer)
+}
@@ -0,0 +1,202 @@
+package genbase
+
+import (
+	"go/ast"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// CommentAssociation is strategy to associate comments with types for annotation discovery.
+type CommentAssociation int
+
+const (
+	// DocCommentAssociation uses doc comment placed just before type declaration.
+	DocCommentAssociation CommentAssociation = iota
+	// CommentMapAssociation uses comments associated by ast.CommentMap.
+	// it includes comments separated from type by blank lines, and trailing comments.
+	CommentMapAssociation
+)
+
+// CommentMap returns ast.CommentMap of file.
+// it is built once per file.
+func (pkg *PackageInfo) CommentMap(file *FileInfo) ast.CommentMap {
+	pkg.cacheMu.Lock()
+	defer pkg.cacheMu.Unlock()
+
+	if cmap, ok := pkg.commentMaps[file]; ok {
+		return cmap
+	}
+	if pkg.commentMaps == nil {
+		pkg.commentMaps = make(map[*FileInfo]ast.CommentMap)
+	}
+	cmap := ast.NewCommentMap(pkg.FileSet, file.AstFile(), file.Comments)
+	pkg.commentMaps[file] = cmap
+	return cmap
+}
+
+// Comments returns comment groups associated with type.
+// it depends on CommentAssociation of PackageInfo.
+func (t *TypeInfo) Comments() []*ast.CommentGroup {
+	pkg := t.PackageInfo
+	if pkg == nil || pkg.FileSet == nil || pkg.CommentAssociation == DocCommentAssociation {
+		if doc := t.Doc(); doc != nil {
+			return []*ast.CommentGroup{doc}
+		}
+		return nil
+	}
+
+	cmap := pkg.CommentMap(t.FileInfo)
+	groups := cmap[t.TypeSpec]
+	if len(groups) == 0 || len(t.GenDecl.Specs) == 1 {
+		groups = append(cmap[t.GenDecl], groups...)
+	}
+	return groups
+}
+
+// CommentLines returns lines of comment group without comment markers.
+// first space of line comment, trailing spaces, leading and trailing blank lines are removed,
+// and common leading whitespace of lines in block comment is removed.
+func CommentLines(doc *ast.CommentGroup) []string {
+	if doc == nil {
+		return nil
+	}
+	var lines []string
+	for _, c := range doc.List {
+		lines = append(lines, commentLines(c)...)
+	}
+	for len(lines) != 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) != 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// commentLines returns lines of comment without comment markers.
+func commentLines(c *ast.Comment) []string {
+	text := c.Text
+	if strings.HasPrefix(text, "//") {
+		return []string{strings.TrimRight(strings.TrimPrefix(text[2:], " "), " \t\r")}
+	} else if strings.HasPrefix(text, "/*") && strings.HasSuffix(text, "*/") && len(text) >= 4 {
+		return dedentLines(strings.Split(text[2:len(text)-2], "\n"))
+	}
+	return nil
+}
+
+// dedentLines removes trailing spaces and common leading whitespace of lines.
+func dedentLines(lines []string) []string {
+	prefix, first := "", true
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		lines[i] = line
+		if line == "" {
+			continue
+		}
+		if first {
+			prefix = line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+			first = false
+			continue
+		}
+		for !strings.HasPrefix(line, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, prefix)
+	}
+	return lines
+}
+
+// SplitCommentText returns text of comment group without comment markers and directive lines, and directive lines.
+// directives are annotations that start with "+" like "+jwg: opts", and comments like "//go:generate" that have
+// no space after "//", see IsDirectiveComment.
+// text ends with newline unless it is empty, likes ast.CommentGroup.Text.
+func SplitCommentText(doc *ast.CommentGroup) (string, []string) {
+	if doc == nil {
+		return "", nil
+	}
+	var lines, directives []string
+	for _, c := range doc.List {
+		if IsDirectiveComment(c) {
+			directives = append(directives, strings.TrimSpace(c.Text[2:]))
+			continue
+		}
+		for _, line := range commentLines(c) {
+			if IsDirectiveLine(line) {
+				directives = append(directives, strings.TrimSpace(line))
+				continue
+			}
+			lines = append(lines, line)
+		}
+	}
+	return joinCommentLines(lines), directives
+}
+
+// StripDirectives returns text without annotation lines. text is comment text likes ast.CommentGroup.Text,
+// that other directives are already removed from.
+func StripDirectives(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if !IsDirectiveLine(line) {
+			lines = append(lines, line)
+		}
+	}
+	return joinCommentLines(lines)
+}
+
+// IsDirectiveLine returns true if line of comment text is annotation, otherwise returns false. e.g. "+jwg", "+qbg: opts"
+// other directives can't be distinguished from prose in comment text, use IsDirectiveComment for them.
+func IsDirectiveLine(line string) bool {
+	line = strings.TrimSpace(line)
+	if len(line) < 2 || line[0] != '+' {
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(line[1:])
+	return unicode.IsLetter(r)
+}
+
+// IsDirectiveComment returns true if c is directive that has no space after "//", otherwise returns false.
+// e.g. "//line a.go:10", "//go:generate stringer", "//export F", "//extern f", "//genbase:ignore jwg"
+func IsDirectiveComment(c *ast.Comment) bool {
+	if !strings.HasPrefix(c.Text, "//") {
+		return false
+	}
+	text := c.Text[2:]
+	for _, prefix := range []string{"line ", "export ", "extern "} {
+		if strings.HasPrefix(text, prefix) && len(text) > len(prefix) {
+			return true
+		}
+	}
+	return isToolDirective(text)
+}
+
+// isToolDirective returns true if text is like "go:generate" or "genbase:ignore", likes go/ast.
+func isToolDirective(text string) bool {
+	idx := strings.Index(text, ":")
+	if idx <= 0 || idx+1 >= len(text) {
+		return false
+	}
+	isLowerAlnum := func(b byte) bool { return 'a' <= b && b <= 'z' || '0' <= b && b <= '9' }
+	for i := 0; i < idx; i++ {
+		if !isLowerAlnum(text[i]) {
+			return false
+		}
+	}
+	return isLowerAlnum(text[idx+1])
+}
+
+// joinCommentLines joins lines with newline, leading and trailing blank lines are removed.
+func joinCommentLines(lines []string) string {
+	for len(lines) != 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) != 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
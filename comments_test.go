@@ -0,0 +1,140 @@
+package genbase
+
+import (
+	"testing"
+)
+
+func TestCommentAssociation(t *testing.T) {
+	src := `
+	package sample
+
+	// +test
+
+	type A struct{}
+
+	type B struct{} // +test
+
+	type (
+		// +test
+		C struct{}
+
+		D struct{}
+	)
+
+	// E is not annotated.
+	type E struct{}
+	`
+
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tis := pInfo.CollectTaggedTypeInfos("+test")
+	if len(tis) != 1 || tis[0].Name() != "C" {
+		t.Fatalf("unexpected: %d", len(tis))
+	}
+
+	p = &Parser{CommentAssociation: CommentMapAssociation}
+	pInfo, err = p.ParseStringSource("main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tis = pInfo.CollectTaggedTypeInfos("+test")
+	if len(tis) != 3 {
+		for _, ti := range tis {
+			t.Log(ti.Name())
+		}
+		t.Fatalf("unexpected: %d", len(tis))
+	}
+	for i, name := range []string{"A", "B", "C"} {
+		if tis[i].Name() != name {
+			t.Errorf("unexpected: %s", tis[i].Name())
+		}
+	}
+}
+
+func TestSplitCommentText(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	// Sample is sample.
+	//
+	//	indented
+	// +jwg: opts
+	//go:generate stringer
+	//
+	type Sample struct{}
+
+	/*
+		Other is other.
+		  +qbg
+	*/
+	type Other struct{}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := pInfo.TypeInfos()
+
+	text, directives := SplitCommentText(types[0].Doc())
+	if text != "Sample is sample.\n\n\tindented\n" {
+		t.Errorf("unexpected: %q", text)
+	}
+	if len(directives) != 2 || directives[0] != "+jwg: opts" || directives[1] != "go:generate stringer" {
+		t.Errorf("unexpected: %q", directives)
+	}
+
+	if v := CommentLines(types[1].Doc()); len(v) != 2 || v[0] != "Other is other." || v[1] != "  +qbg" {
+		t.Errorf("unexpected: %q", v)
+	}
+	text, directives = SplitCommentText(types[1].Doc())
+	if text != "Other is other.\n" || len(directives) != 1 || directives[0] != "+qbg" {
+		t.Errorf("unexpected: %q %q", text, directives)
+	}
+
+	if v := StripDirectives("Sample is sample.\n+jwg\n+ 1\n"); v != "Sample is sample.\n+ 1\n" {
+		t.Errorf("unexpected: %q", v)
+	}
+}
+
+func TestSplitCommentTextKeepsProse(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	// Sample keeps prose.
+	// line breaks are kept
+	// export this value
+	// extern is a word too
+	// todo:fix later
+	// go:generate is prose with space
+	//line main.go:1
+	//export Sample
+	//genbase:ignore jwg
+	type Sample struct{}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := pInfo.TypeInfos()[0].Doc()
+
+	text, directives := SplitCommentText(doc)
+	expect := "Sample keeps prose.\nline breaks are kept\nexport this value\nextern is a word too\ntodo:fix later\ngo:generate is prose with space\n"
+	if text != expect {
+		t.Errorf("unexpected: %q", text)
+	}
+	if len(directives) != 3 || directives[0] != "line main.go:1" || directives[1] != "export Sample" || directives[2] != "genbase:ignore jwg" {
+		t.Errorf("unexpected: %q", directives)
+	}
+
+	if v := StripDirectives(doc.Text()); v != expect {
+		t.Errorf("unexpected: %q", v)
+	}
+	for _, line := range []string{"line breaks are kept", "export this value", "todo:fix", "go:generate stringer"} {
+		if IsDirectiveLine(line) {
+			t.Errorf("unexpected: %s", line)
+		}
+	}
+}
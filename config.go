@@ -0,0 +1,235 @@
+package genbase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ConfigFileName is name of config file that supplies default options of generators.
+// it is JSON object that has section of each generator, section is named by annotation tag without "+".
+// e.g. {"jwg": {"output": "json_gen.go", "omitempty": true}}
+const ConfigFileName = "genbase.json"
+
+// Config is merged config files of package directory and its parents up to module root.
+// options of config in nearer directory take precedence over ones in parent directories.
+type Config struct {
+	Files    []string // loaded config files, nearest first
+	sections map[string]map[string]json.RawMessage
+	sources  map[string]map[string]string // config file of each option
+}
+
+// LoadConfig loads config files in directory and its parents up to directory that has go.mod.
+// only config file in directory is loaded if go.mod is not found.
+// missing config files are ignored, so empty Config is returned if no config file is found.
+// top-level values that are not objects are ignored, e.g. {"$schema": "..."}
+func LoadConfig(directory string) (*Config, error) {
+	dir, err := filepath.Abs(directory)
+	if err != nil {
+		return nil, fmt.Errorf("cannot process directory %s: %s", directory, err)
+	}
+	root := moduleRoot(dir)
+	if root == "" {
+		root = dir
+	}
+	c := &Config{
+		sections: make(map[string]map[string]json.RawMessage),
+		sources:  make(map[string]map[string]string),
+	}
+	var files []map[string]json.RawMessage
+	for {
+		fileName := filepath.Join(dir, ConfigFileName)
+		b, err := ioutil.ReadFile(fileName)
+		if err == nil {
+			var file map[string]json.RawMessage
+			if err := json.Unmarshal(b, &file); err != nil {
+				return nil, fmt.Errorf("reading config %s: %s", fileName, err)
+			}
+			c.Files = append(c.Files, fileName)
+			files = append(files, file)
+		} else if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("reading config %s: %s", fileName, err)
+		}
+		if dir == root {
+			break
+		}
+		dir = filepath.Dir(dir)
+	}
+
+	for i := len(files) - 1; i >= 0; i-- {
+		for name, raw := range files[i] {
+			if raw := bytes.TrimSpace(raw); len(raw) == 0 || raw[0] != '{' {
+				continue
+			}
+			var section map[string]json.RawMessage
+			if err := json.Unmarshal(raw, &section); err != nil {
+				return nil, fmt.Errorf("reading config %s: section %s: %s", c.Files[i], name, err)
+			}
+			merged := c.sections[name]
+			if merged == nil {
+				merged = make(map[string]json.RawMessage)
+				c.sections[name] = merged
+				c.sources[name] = make(map[string]string)
+			}
+			for key, value := range section {
+				merged[key] = value
+				c.sources[name][key] = c.Files[i]
+			}
+		}
+	}
+	return c, nil
+}
+
+// moduleRoot returns directory that has go.mod and contains dir, or "" if it is not found.
+func moduleRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// Sections returns sorted names of sections.
+func (c *Config) Sections() []string {
+	names := make([]string, 0, len(c.sections))
+	for name := range c.sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UnmarshalSection unmarshals merged section of name into v by encoding/json.
+// v is not modified if section is not found.
+func (c *Config) UnmarshalSection(name string, v interface{}) error {
+	section, ok := c.sections[name]
+	if !ok {
+		return nil
+	}
+	b, err := json.Marshal(section)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("config section %s: %s", name, err)
+	}
+	return nil
+}
+
+// Args returns options of section as annotation arguments sorted by key.
+// values are strings, numbers, booleans or arrays of them, and array is repeated argument.
+// errors of arguments are reported at config file that gives them.
+func (c *Config) Args(name string) ([]*AnnotationArg, error) {
+	section := c.sections[name]
+	keys := make([]string, 0, len(section))
+	for key := range section {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var args []*AnnotationArg
+	for _, key := range keys {
+		fileName := c.sources[name][key]
+		raw := bytes.TrimSpace(section[key])
+		var values []json.RawMessage
+		if len(raw) != 0 && raw[0] == '[' {
+			if err := json.Unmarshal(raw, &values); err != nil {
+				return nil, fmt.Errorf("%s: config section %s: option %s: %s", fileName, name, key, err)
+			}
+		} else {
+			values = []json.RawMessage{raw}
+		}
+		for _, value := range values {
+			s, err := configValue(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s: config section %s: option %s: %s", fileName, name, key, err)
+			}
+			args = append(args, &AnnotationArg{Key: key, Value: s, HasValue: true, configFile: fileName})
+		}
+	}
+	return args, nil
+}
+
+// configValue returns scalar JSON value as string of annotation argument.
+func configValue(raw json.RawMessage) (string, error) {
+	var v interface{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return "", err
+	}
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return fmt.Sprint(v), nil
+	}
+	return "", fmt.Errorf("unsupported value %s", raw)
+}
+
+// Config returns Config of package directory, it is loaded once.
+func (pkg *PackageInfo) Config() (*Config, error) {
+	pkg.cacheMu.Lock()
+	defer pkg.cacheMu.Unlock()
+	if pkg.config == nil {
+		c, err := LoadConfig(pkg.Dir)
+		if err != nil {
+			return nil, err
+		}
+		pkg.config = c
+	}
+	return pkg.config, nil
+}
+
+// UnmarshalAnnotationWithConfig is UnmarshalAnnotation that options of Config are merged into.
+// section of Config is named by tag without "+". e.g. "jwg" for "+jwg"
+// precedence is annotation arguments, config in package directory, configs in parent directories,
+// and `gen-default` tags in order. argument in annotation replaces all values of same key in config.
+func (t *TypeInfo) UnmarshalAnnotationWithConfig(tag string, v interface{}) error {
+	info, err := t.Annotation(tag)
+	if err != nil {
+		return t.UnmarshalAnnotation(tag, v)
+	}
+	if info == nil {
+		return fmt.Errorf("type %s is not annotated with %s", t.Name(), tag)
+	}
+	c, err := t.PackageInfo.Config()
+	if err != nil {
+		return err
+	}
+	configArgs, err := c.Args(strings.TrimPrefix(tag, "+"))
+	if err != nil {
+		return err
+	}
+
+	var args []*AnnotationArg
+	for _, arg := range configArgs {
+		if !hasAnnotationArg(info.Args, arg.Key) {
+			args = append(args, arg)
+		}
+	}
+	args = append(args, info.Args...)
+	return t.PackageInfo.unmarshalAnnotationArgs(args, info.Pos, v, t.ExpandContext())
+}
+
+// hasAnnotationArg returns true if args have argument of key, otherwise returns false.
+func hasAnnotationArg(args []*AnnotationArg, key string) bool {
+	for _, arg := range args {
+		if arg.Key == key {
+			return true
+		}
+	}
+	return false
+}
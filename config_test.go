@@ -0,0 +1,119 @@
+package genbase
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestTypeInfoUnmarshalAnnotationWithConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genbase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		ConfigFileName:                  `{"test": {"output": "outside.go", "verbose": true}}`,
+		"repo/go.mod":                   "module example.com/repo\n\ngo 1.15\n",
+		"repo/" + ConfigFileName:        `{"test": {"output": "root.go", "names": ["a", "b"], "limit": 3}, "other": {"x": 1}}`,
+		"repo/sample/" + ConfigFileName: `{"test": {"output": "sample.go"}}`,
+		"repo/sample/sample.go":         "package sample\n// +test: limit=5\ntype Sample struct{}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := &Parser{}
+	pInfo, err := p.ParsePackageDir(filepath.Join(dir, "repo", "sample"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := pInfo.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Files) != 2 || !reflect.DeepEqual(c.Sections(), []string{"other", "test"}) {
+		t.Errorf("unexpected: %v %v", c.Files, c.Sections())
+	}
+
+	type options struct {
+		Output  string
+		Names   []string `gen-default:"z"`
+		Limit   int
+		Enabled bool `gen-default:"true"`
+	}
+	var opts options
+	if err := pInfo.CollectTypeInfos([]string{"Sample"})[0].UnmarshalAnnotationWithConfig("+test", &opts); err != nil {
+		t.Fatal(err)
+	}
+	expected := options{Output: "sample.go", Names: []string{"a", "b"}, Limit: 5, Enabled: true}
+	if !reflect.DeepEqual(opts, expected) {
+		t.Errorf("unexpected: %+v", opts)
+	}
+
+	var section struct {
+		Output string   `json:"output"`
+		Names  []string `json:"names"`
+	}
+	if err := c.UnmarshalSection("test", &section); err != nil {
+		t.Fatal(err)
+	}
+	if section.Output != "sample.go" || len(section.Names) != 2 {
+		t.Errorf("unexpected: %+v", section)
+	}
+}
+
+func TestLoadConfigWithoutGoMod(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genbase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		ConfigFileName:                    `{"test": {"output": "outside.go"}}`,
+		"sample/" + ConfigFileName:        `{"$schema": "https://example.com/genbase.json", "test": {"limit": "many"}}`,
+		"sample/sample.go":                "package sample\n// +test\ntype Sample struct{}\n",
+		"sample/nested/" + ConfigFileName: `{"test": {}}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := &Parser{}
+	pInfo, err := p.ParsePackageDir(filepath.Join(dir, "sample"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := pInfo.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	configFile := filepath.Join(pInfo.Dir, ConfigFileName)
+	if len(c.Files) != 1 || c.Files[0] != configFile || !reflect.DeepEqual(c.Sections(), []string{"test"}) {
+		t.Errorf("unexpected: %v %v", c.Files, c.Sections())
+	}
+
+	var opts struct {
+		Limit int
+	}
+	err = pInfo.CollectTypeInfos([]string{"Sample"})[0].UnmarshalAnnotationWithConfig("+test", &opts)
+	if aErr, ok := err.(*AnnotationError); !ok || aErr.Key != "limit" || aErr.Position.Filename != configFile {
+		t.Errorf("unexpected: %v", err)
+	}
+}
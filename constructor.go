@@ -0,0 +1,125 @@
+package genbase
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+)
+
+// ConstructorOptions is options of constructor function, see Generator.PrintConstructor.
+type ConstructorOptions struct {
+	Name     string            // function name, "New" + type name is used if it is empty
+	Required []string          // names of fields that are parameters of constructor, in order
+	Defaults map[string]string // initial values of fields by field name, values are Go expressions. e.g. "10", "time.Now()"
+	Value    bool              // constructor returns value instead of pointer
+}
+
+// ConstructorOptions returns ConstructorOptions made from field annotations of struct type.
+// field is required by "// +<tag>:required" annotation, and initial value is specified by "// +<tag>: default=<expr>".
+// embedded fields and skipped fields are ignored, see FieldInfo.IsSkipped.
+// returns *AnnotationError if annotations are malformed.
+func (t *TypeInfo) ConstructorOptions(tag string) (*ConstructorOptions, error) {
+	st, err := t.StructType()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &ConstructorOptions{Defaults: make(map[string]string)}
+	for _, f := range st.FieldInfos() {
+		if len(f.Names) == 0 || f.IsSkipped(tag) {
+			continue
+		}
+		for _, doc := range []*ast.CommentGroup{f.Doc, f.Comment} {
+			c := findAnnotation(doc, tag)
+			if c == nil {
+				continue
+			}
+			args, err := ParseAnnotationArgs(c, tag)
+			if err != nil {
+				return nil, &AnnotationError{Position: t.PackageInfo.position(c.Pos()), Reason: err.Error()}
+			}
+			for _, arg := range args {
+				for _, ident := range f.Names {
+					switch {
+					case arg.Key == "required" && !arg.HasValue:
+						opts.Required = append(opts.Required, ident.Name)
+					case arg.Key == "default" && arg.HasValue:
+						opts.Defaults[ident.Name] = arg.Value
+					default:
+						return nil, &AnnotationError{
+							Position: t.PackageInfo.position(arg.Pos),
+							Key:      arg.Key,
+							Reason:   fmt.Sprintf("unknown argument %s of field %s", arg.Key, ident.Name),
+						}
+					}
+				}
+			}
+		}
+	}
+	return opts, nil
+}
+
+// PrintConstructor prints constructor function of struct type to buffer.
+// required fields are parameters of function, and other fields that have defaults are initialized by them.
+// types of parameters are qualified by Qualifier, imports for default values must be added by AddImport.
+// returns error if options refer to unknown fields.
+func (g *Generator) PrintConstructor(t *TypeInfo, opts *ConstructorOptions) error {
+	st, err := t.StructType()
+	if err != nil {
+		return err
+	}
+	if opts == nil {
+		opts = &ConstructorOptions{}
+	}
+
+	fields := make(map[string]*FieldInfo)
+	var names []string
+	for _, f := range st.FieldInfos() {
+		for _, ident := range f.Names {
+			fields[ident.Name] = f
+			names = append(names, ident.Name)
+		}
+	}
+	for _, name := range opts.Required {
+		if fields[name] == nil {
+			return fmt.Errorf("required field %s is not found in type %s", name, t.Name())
+		}
+	}
+	for name := range opts.Defaults {
+		if fields[name] == nil {
+			return fmt.Errorf("field %s of default value is not found in type %s", name, t.Name())
+		}
+	}
+
+	funcName := opts.Name
+	if funcName == "" {
+		funcName = "New" + t.Name()
+	}
+	resultType := "*" + t.Name()
+	literal := "&" + t.Name()
+	if opts.Value {
+		resultType = t.Name()
+		literal = t.Name()
+	}
+
+	required := make(map[string]string)
+	params := make([]string, 0, len(opts.Required))
+	for _, name := range opts.Required {
+		param := UnexportedIdent(name)
+		required[name] = param
+		params = append(params, param+" "+t.PackageInfo.RenderTypeName(fields[name].Type, g.Qualifier()))
+	}
+
+	g.Printf("// %s returns new %s.\n", funcName, t.Name())
+	g.Printf("func %s(%s) %s {\n", funcName, strings.Join(params, ", "), resultType)
+	g.Printf("return %s{\n", literal)
+	for _, name := range names {
+		if param, ok := required[name]; ok {
+			g.Printf("%s: %s,\n", name, param)
+		} else if value, ok := opts.Defaults[name]; ok {
+			g.Printf("%s: %s,\n", name, value)
+		}
+	}
+	g.Printf("}\n}\n\n")
+	return nil
+}
@@ -0,0 +1,103 @@
+package genbase
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGeneratorPrintConstructor(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", "package sample\n"+
+		"import \"time\"\n"+
+		"// +new\n"+
+		"type User struct {\n"+
+		"	Base\n"+
+		"	// +new:required\n"+
+		"	Name string\n"+
+		"	Type string // +new:required\n"+
+		"	CreatedAt time.Time // +new:required\n"+
+		"	Limit int // +new: default=10\n"+
+		`	Role string // +new: default="\"member\""`+"\n"+
+		"	Skipped string `gen:\"-\"` // +new:required\n"+
+		"	Other string\n"+
+		"}\n"+
+		"type Base struct {\n"+
+		"	ID int\n"+
+		"}\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	typeInfo, err := pInfo.FindTypeInfo("User")
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, err := typeInfo.ConstructorOptions("+new")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := strings.Join(opts.Required, ","); v != "Name,Type,CreatedAt" {
+		t.Errorf("unexpected: %s", v)
+	}
+	if len(opts.Defaults) != 2 || opts.Defaults["Limit"] != "10" || opts.Defaults["Role"] != `"member"` {
+		t.Errorf("unexpected: %v", opts.Defaults)
+	}
+
+	g := NewGenerator(pInfo)
+	if err := g.PrintConstructor(typeInfo, opts); err != nil {
+		t.Fatal(err)
+	}
+	if v := len(g.RequiredImports); v != 1 || g.RequiredImports[0].Path != "time" {
+		t.Errorf("unexpected: %v", g.RequiredImports)
+	}
+	expected := "// NewUser returns new User.\n" +
+		"func NewUser(name string, type_ string, createdAt time.Time) *User {\n" +
+		"return &User{\n" +
+		"Name: name,\n" +
+		"Type: type_,\n" +
+		"CreatedAt: createdAt,\n" +
+		"Limit: 10,\n" +
+		"Role: \"member\",\n" +
+		"}\n}\n\n"
+	if v := g.Buf.String(); v != expected {
+		t.Errorf("unexpected: %s", v)
+	}
+
+	g = NewGenerator(pInfo)
+	if err := g.PrintConstructor(typeInfo, &ConstructorOptions{Name: "MakeUser", Value: true}); err != nil {
+		t.Fatal(err)
+	}
+	if v := g.Buf.String(); !strings.HasPrefix(v, "// MakeUser returns new User.\nfunc MakeUser() User {\nreturn User{\n") {
+		t.Errorf("unexpected: %s", v)
+	}
+
+	g = NewGenerator(pInfo)
+	err = g.PrintConstructor(typeInfo, &ConstructorOptions{Required: []string{"Nmae"}})
+	if err == nil || err.Error() != "required field Nmae is not found in type User" {
+		t.Errorf("unexpected: %v", err)
+	}
+}
+
+func TestTypeInfoConstructorOptionsError(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", "package sample\n"+
+		"type User struct {\n"+
+		"	Name string // +new: requried\n"+
+		"}\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	typeInfo, err := pInfo.FindTypeInfo("User")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = typeInfo.ConstructorOptions("+new")
+	annotationErr, ok := err.(*AnnotationError)
+	if !ok {
+		t.Fatalf("unexpected: %v", err)
+	}
+	if annotationErr.Key != "requried" || annotationErr.Position.Line != 3 {
+		t.Errorf("unexpected: %s", annotationErr)
+	}
+}
@@ -0,0 +1,100 @@
+package genbase
+
+import (
+	"go/types"
+)
+
+// CopyKind shows how value of type is copied.
+type CopyKind int
+
+const (
+	// CopyUnknown means type is not resolved.
+	CopyUnknown CopyKind = iota
+	// CopyShallow means assignment copies value completely.
+	CopyShallow
+	// CopyDeep means type has pointers, slices or maps, and assignment shares them.
+	CopyDeep
+	// CopyReference means type has channels, funcs or interfaces, and they can't be copied generally.
+	CopyReference
+)
+
+func (k CopyKind) String() string {
+	switch k {
+	case CopyShallow:
+		return "shallow"
+	case CopyDeep:
+		return "deep"
+	case CopyReference:
+		return "reference"
+	}
+	return "unknown"
+}
+
+// CopyKind returns how value of field type is copied.
+// Clone or DeepCopy generators use it to choose copy code.
+func (f *FieldInfo) CopyKind(pkg *PackageInfo) CopyKind {
+	return CopyKindOf(pkg.TypeOf(f.Type))
+}
+
+// NeedsDeepCopy returns true if value of field type shares memory when it is assigned, otherwise returns false.
+func (f *FieldInfo) NeedsDeepCopy(pkg *PackageInfo) bool {
+	return f.CopyKind(pkg) == CopyDeep
+}
+
+// CopyKindOf returns how value of typ is copied.
+// if type contains both of deep and reference, CopyReference is returned.
+func CopyKindOf(typ types.Type) CopyKind {
+	if typ == nil {
+		return CopyUnknown
+	}
+	return copyKindOf(typ, make(map[types.Type]bool))
+}
+
+func copyKindOf(typ types.Type, visited map[types.Type]bool) CopyKind {
+	if visited[typ] {
+		// recursive type is always through pointer, slice or map.
+		return CopyShallow
+	}
+	visited[typ] = true
+
+	if named, ok := typ.(*types.Named); ok {
+		// time.Time has pointer of *time.Location, but it is designed as immutable value.
+		if obj := named.Obj(); obj.Pkg() != nil && obj.Pkg().Path() == "time" && obj.Name() == "Time" {
+			return CopyShallow
+		}
+	}
+
+	switch t := typ.Underlying().(type) {
+	case *types.Basic:
+		if t.Kind() == types.UnsafePointer {
+			return CopyReference
+		}
+		return CopyShallow
+	case *types.Pointer:
+		return maxCopyKind(CopyDeep, copyKindOf(t.Elem(), visited))
+	case *types.Slice:
+		return maxCopyKind(CopyDeep, copyKindOf(t.Elem(), visited))
+	case *types.Map:
+		return maxCopyKind(CopyDeep, copyKindOf(t.Key(), visited), copyKindOf(t.Elem(), visited))
+	case *types.Array:
+		return copyKindOf(t.Elem(), visited)
+	case *types.Struct:
+		kind := CopyShallow
+		for i := 0; i < t.NumFields(); i++ {
+			kind = maxCopyKind(kind, copyKindOf(t.Field(i).Type(), visited))
+		}
+		return kind
+	case *types.Chan, *types.Signature, *types.Interface:
+		return CopyReference
+	}
+	return CopyUnknown
+}
+
+func maxCopyKind(k CopyKind, ks ...CopyKind) CopyKind {
+	for _, v := range ks {
+		if v > k {
+			k = v
+		}
+	}
+	return k
+}
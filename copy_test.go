@@ -0,0 +1,53 @@
+package genbase
+
+import (
+	"testing"
+)
+
+func TestFieldInfoCopyKind(t *testing.T) {
+	src := `
+	package sample
+
+	import "time"
+
+	type Sample struct {
+		A int
+		B [2]string
+		C time.Time
+		D *int
+		E []int
+		F map[string]int
+		G Inner
+		H Node
+		I func()
+		J error
+	}
+
+	type Inner struct {
+		Values []int
+	}
+
+	type Node struct {
+		Next *Node
+	}
+	`
+
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := pInfo.CollectTypeInfos([]string{"Sample"})[0].StructType()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expects := []CopyKind{CopyShallow, CopyShallow, CopyShallow, CopyDeep, CopyDeep, CopyDeep, CopyDeep, CopyDeep, CopyReference, CopyReference}
+	for i, f := range st.FieldInfos() {
+		if v := f.CopyKind(pInfo); v != expects[i] {
+			t.Errorf("unexpected: %s %s", f.Names[0].Name, v)
+		}
+	}
+	if !st.FieldInfos()[4].NeedsDeepCopy(pInfo) {
+		t.Fatalf("unexpected: %v", false)
+	}
+}
@@ -0,0 +1,129 @@
+package genbase
+
+import (
+	"encoding/json"
+	"go/ast"
+	"html/template"
+	"net/http"
+)
+
+// DebugInfo is what genbase saw in package, it is served by DebugHandler.
+type DebugInfo struct {
+	Package      string         `json:"package"`
+	ImportPath   string         `json:"importPath,omitempty"`
+	Dir          string         `json:"dir,omitempty"`
+	Files        []string       `json:"files"`
+	SkippedFiles []*SkippedFile `json:"skippedFiles,omitempty"`
+	Types        []*DebugType   `json:"types"`
+}
+
+// DebugType is type declaration of DebugInfo.
+type DebugType struct {
+	Name            string             `json:"name"`
+	File            string             `json:"file,omitempty"`
+	Line            int                `json:"line,omitempty"`
+	Annotations     []*DebugAnnotation `json:"annotations,omitempty"`
+	AnnotationError string             `json:"annotationError,omitempty"`
+	Refs            []string           `json:"refs,omitempty"` // types in package that type refers to
+	Model           *Type              `json:"model"`
+}
+
+// DebugAnnotation is annotation of DebugType.
+type DebugAnnotation struct {
+	Tag  string           `json:"tag"`
+	Raw  string           `json:"raw"`
+	Args []*AnnotationArg `json:"args,omitempty"`
+}
+
+// NewDebugInfo creates DebugInfo of package.
+func NewDebugInfo(pkg *PackageInfo) *DebugInfo {
+	info := &DebugInfo{
+		Package:      pkg.Name(),
+		ImportPath:   pkg.ImportPath(),
+		Dir:          pkg.Dir,
+		Files:        make([]string, 0, len(pkg.Files)),
+		SkippedFiles: pkg.SkippedFiles,
+	}
+	for _, file := range pkg.Files {
+		info.Files = append(info.Files, pkg.position(file.Package).Filename)
+	}
+	typeInfos := pkg.TypeInfos()
+	info.Types = make([]*DebugType, 0, len(typeInfos))
+	for _, t := range typeInfos {
+		info.Types = append(info.Types, newDebugType(t))
+	}
+	return info
+}
+
+// newDebugType creates DebugType of t with all annotations in its comments.
+func newDebugType(t *TypeInfo) *DebugType {
+	pos := t.PackageInfo.position(t.TypeSpec.Pos())
+	ret := &DebugType{Name: t.Name(), File: pos.Filename, Line: pos.Line, Model: NewType(t)}
+	for _, doc := range t.Comments() {
+		infos, err := parseAnnotations(doc, nil)
+		if err != nil {
+			ret.AnnotationError = err.Error()
+			break
+		}
+		for _, a := range infos {
+			ret.Annotations = append(ret.Annotations, &DebugAnnotation{Tag: a.Tag, Raw: a.Raw, Args: a.Args})
+		}
+	}
+	seen := make(map[string]bool)
+	for _, expr := range typeRefs(t.TypeSpec.Type, nil) {
+		ref := (*FieldInfo)(&ast.Field{Type: expr}).ResolveTypeInfo(t.PackageInfo)
+		if ref != nil && !seen[ref.Name()] {
+			seen[ref.Name()] = true
+			ret.Refs = append(ret.Refs, ref.Name())
+		}
+	}
+	return ret
+}
+
+// NewDebugHandler returns http.Handler that serves DebugInfo of package.
+// "/" is HTML page and "/debug.json" is JSON, mount it by http.StripPrefix under other path.
+// DebugInfo is created for each request, so it reflects current state of package.
+func NewDebugHandler(pkg *PackageInfo) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "", "/":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			if err := debugTemplate.Execute(w, NewDebugInfo(pkg)); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+		case "/debug.json":
+			w.Header().Set("Content-Type", "application/json")
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(NewDebugInfo(pkg)); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// ServeDebug serves NewDebugHandler of package on addr until it fails. e.g. "localhost:6060"
+func ServeDebug(addr string, pkg *PackageInfo) error {
+	return http.ListenAndServe(addr, NewDebugHandler(pkg))
+}
+
+var debugTemplate = template.Must(template.New("debug").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>genbase: {{.Package}}</title></head>
+<body>
+<h1>package {{.Package}}</h1>
+<p>{{.ImportPath}} {{.Dir}} <a href="debug.json">debug.json</a></p>
+<h2>Files</h2>
+<ul>{{range .Files}}<li>{{.}}</li>{{end}}</ul>
+{{if .SkippedFiles}}<h2>Skipped files</h2>
+<ul>{{range .SkippedFiles}}<li>{{.Name}}: {{.Reason}}</li>{{end}}</ul>
+{{end}}<h2>Types</h2>
+<table>
+<tr><th>type</th><th>position</th><th>annotations</th><th>refs</th></tr>
+{{range .Types}}<tr id="{{.Name}}"><td>{{.Name}}</td><td>{{.File}}:{{.Line}}</td><td>{{if .AnnotationError}}<b>{{.AnnotationError}}</b>{{else}}{{range .Annotations}}<code>{{.Raw}}</code><br>{{end}}{{end}}</td><td>{{range .Refs}}<a href="#{{.}}">{{.}}</a> {{end}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
@@ -0,0 +1,93 @@
+package genbase
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewDebugHandler(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("model.go", `
+package sample
+
+// Sample is annotated.
+// +jwg: output=sample_json.go, omitempty
+// +test
+type Sample struct {
+	Items []*Item
+	Owner *Owner
+}
+
+// Item is not annotated.
+type Item struct {
+	Name string
+}
+
+// +jwg: output="broken
+type Owner struct{}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.StripPrefix("/debug", NewDebugHandler(pInfo)))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/debug/debug.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected: %d", resp.StatusCode)
+	}
+	var info DebugInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		t.Fatal(err)
+	}
+	if info.Package != "sample" || len(info.Files) != 1 || info.Files[0] != "model.go" || len(info.Types) != 3 {
+		t.Fatalf("unexpected: %#v", info)
+	}
+	sample := info.Types[0]
+	if sample.Name != "Sample" || sample.Line != 7 || len(sample.Annotations) != 2 || strings.Join(sample.Refs, ",") != "Item,Owner" {
+		t.Errorf("unexpected: %#v", sample)
+	}
+	if a := sample.Annotations[0]; a.Tag != "+jwg" || len(a.Args) != 2 || a.Args[0].Value != "sample_json.go" {
+		t.Errorf("unexpected: %#v", a)
+	}
+	if sample.Model == nil || len(sample.Model.Fields) != 2 {
+		t.Errorf("unexpected: %#v", sample.Model)
+	}
+	if item := info.Types[1]; len(item.Annotations) != 0 || item.AnnotationError != "" {
+		t.Errorf("unexpected: %#v", item)
+	}
+	if owner := info.Types[2]; owner.AnnotationError == "" {
+		t.Errorf("unexpected: %#v", owner)
+	}
+
+	resp, err = http.Get(server.URL + "/debug/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := string(b)
+	if !strings.Contains(resp.Header.Get("Content-Type"), "text/html") || !strings.Contains(page, `<tr id="Sample">`) || !strings.Contains(page, `<a href="#Item">Item</a>`) {
+		t.Errorf("unexpected: %s", page)
+	}
+
+	resp, err = http.Get(server.URL + "/debug/missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unexpected: %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,270 @@
+package genbase
+
+import (
+	"fmt"
+	"go/ast"
+	"go/scanner"
+	"go/token"
+	"sync"
+)
+
+// deferredCheck is state of parsing and type checking on demand, see Parser.DeferTypeCheck.
+type deferredCheck struct {
+	mu      sync.Mutex
+	parser  *Parser
+	done    bool
+	checked map[*FileInfo]bool // fully parsed files that are type checked
+	err     error
+}
+
+// resolveTypesOf fully parses and type checks files that declare typeInfos and files they use, if types are deferred.
+// types are cached, and checked again with union of files when typeInfos need files that are not checked yet.
+// typeInfos are updated to refer fully parsed files. errors are logged too, because collectors don't return them.
+func (pkg *PackageInfo) resolveTypesOf(typeInfos TypeInfos) error {
+	d := pkg.deferred
+	if d == nil || len(typeInfos) == 0 {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	needed := pkg.filesUsedBy(typeInfos)
+	if d.done {
+		missing := false
+		for file := range needed {
+			if !d.checked[file] {
+				missing = true
+				break
+			}
+		}
+		if !missing {
+			return d.err
+		}
+	}
+	d.done = true
+
+	replaced := make(map[*FileInfo]*FileInfo)
+	var files FileInfos
+	for i, file := range pkg.Files {
+		if d.checked[file] {
+			files = append(files, file)
+			continue
+		}
+		if !needed[file] {
+			continue
+		}
+		full, err := pkg.fullyParse(file)
+		if err != nil {
+			d.err = err
+			d.parser.logger().Warnf("%s", err)
+			return err
+		}
+		replaced[file] = full
+		pkg.cacheMu.Lock()
+		pkg.Files[i] = full
+		pkg.cacheMu.Unlock()
+		d.checked[full] = true
+		files = append(files, full)
+	}
+	for _, t := range typeInfos {
+		pkg.replaceFileOf(t, replaced[t.FileInfo])
+	}
+
+	d.parser.logger().Debugf("type checking %d of %d files in %s", len(files), len(pkg.Files), pkg.Dir)
+	d.err = d.parser.checkFiles(pkg, files)
+	if d.err != nil {
+		d.parser.logger().Warnf("types of %s are not resolved: %s", pkg.Dir, d.err)
+	}
+	return d.err
+}
+
+// fullyParse parses source of lightly parsed file with bodies of funcs.
+func (pkg *PackageInfo) fullyParse(file *FileInfo) (*FileInfo, error) {
+	fileName := pkg.position(file.Package).Filename
+	src := pkg.Source(file)
+	if src == nil {
+		return nil, fmt.Errorf("parsing package: %s: %w", fileName, ErrNoSource)
+	}
+	p := pkg.deferred.parser
+	p.logger().Debugf("fully parsing %s", fileName)
+	full, err := p.parseSource(pkg.FileSet, fileName, src)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.afterParseFile(fileName, full); err != nil {
+		return nil, err
+	}
+	return full, nil
+}
+
+// replaceFileOf replaces declaration of t by one in full that is parsed from same source.
+func (pkg *PackageInfo) replaceFileOf(t *TypeInfo, full *FileInfo) {
+	if full == nil {
+		return
+	}
+	offset := pkg.FileSet.File(t.TypeSpec.Pos()).Offset(t.TypeSpec.Pos())
+	pos := pkg.FileSet.File(full.Package).Pos(offset)
+	for _, decl := range full.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			if typeSpec, ok := spec.(*ast.TypeSpec); ok && typeSpec.Pos() == pos {
+				t.FileInfo = full
+				t.GenDecl = genDecl
+				t.TypeSpec = typeSpec
+				pkg.replaceAnnotatedComment(t)
+				return
+			}
+		}
+	}
+}
+
+// replaceAnnotatedComment replaces AnnotatedComment of t by comment at same position in its file.
+func (pkg *PackageInfo) replaceAnnotatedComment(t *TypeInfo) {
+	if t.AnnotatedComment == nil {
+		return
+	}
+	text := t.AnnotatedComment.Text
+	for _, doc := range t.Comments() {
+		for _, c := range doc.List {
+			if c.Text == text {
+				t.AnnotatedComment = c
+				return
+			}
+		}
+	}
+}
+
+// filesUsedBy returns files that declare typeInfos, and files that declare identifiers used by them transitively.
+// bodies of funcs are not followed, because they are not type checked.
+func (pkg *PackageInfo) filesUsedBy(typeInfos TypeInfos) map[*FileInfo]bool {
+	declared := make(map[string][]*FileInfo)
+	for _, file := range pkg.Files {
+		for _, name := range declaredNames(file) {
+			declared[name] = append(declared[name], file)
+		}
+	}
+
+	used := make(map[*FileInfo]bool)
+	var queue FileInfos
+	for _, t := range typeInfos {
+		if !used[t.FileInfo] {
+			used[t.FileInfo] = true
+			queue = append(queue, t.FileInfo)
+		}
+	}
+	for len(queue) != 0 {
+		file := queue[0]
+		queue = queue[1:]
+		for _, name := range identsOutsideFuncBodies(file) {
+			for _, dep := range declared[name] {
+				if !used[dep] {
+					used[dep] = true
+					queue = append(queue, dep)
+				}
+			}
+		}
+	}
+	return used
+}
+
+// declaredNames returns names that are declared at top level of file.
+// methods are returned as name of receiver type, so files of methods are checked with their type.
+func declaredNames(file *FileInfo) []string {
+	var names []string
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					names = append(names, spec.Name.Name)
+				case *ast.ValueSpec:
+					for _, ident := range spec.Names {
+						names = append(names, ident.Name)
+					}
+				}
+			}
+		case *ast.FuncDecl:
+			fn := &FuncInfo{FileInfo: file, FuncDecl: decl}
+			if fn.IsMethod() {
+				names = append(names, fn.ReceiverTypeName())
+			} else {
+				names = append(names, fn.Name())
+			}
+		}
+	}
+	return names
+}
+
+// lightSource returns copy of src that bodies of top-level funcs are blanked out, for annotation discovery.
+// offsets and lines are kept, so positions in lightly parsed file are same as fully parsed one.
+// src is returned as it is if it has syntax error of tokens, then parsing reports it.
+func lightSource(fileName string, src []byte) []byte {
+	var s scanner.Scanner
+	file := token.NewFileSet().AddFile(fileName, -1, len(src))
+	s.Init(file, src, nil, 0)
+
+	light := make([]byte, len(src))
+	copy(light, src)
+	depth := 0                 // depth of (, [ and { at top level
+	prev := token.SEMICOLON    // previous token
+	inHeader := false          // in header of func declaration
+	parens, typeBraces := 0, 0 // depth in header of func declaration
+	for {
+		pos, tok, _ := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		switch {
+		case !inHeader:
+			switch tok {
+			case token.FUNC:
+				inHeader = depth == 0 && prev == token.SEMICOLON
+			case token.LPAREN, token.LBRACK, token.LBRACE:
+				depth++
+			case token.RPAREN, token.RBRACK, token.RBRACE:
+				depth--
+			}
+		case tok == token.LPAREN || tok == token.LBRACK:
+			parens++
+		case tok == token.RPAREN || tok == token.RBRACK:
+			parens--
+		case tok == token.LBRACE && (parens != 0 || typeBraces != 0 || prev == token.STRUCT || prev == token.INTERFACE):
+			typeBraces++
+		case tok == token.RBRACE && typeBraces != 0:
+			typeBraces--
+		case tok == token.LBRACE:
+			start := file.Offset(pos) + 1
+			for braces := 1; braces != 0; {
+				pos, tok, _ = s.Scan()
+				switch tok {
+				case token.EOF:
+					return src
+				case token.LBRACE:
+					braces++
+				case token.RBRACE:
+					braces--
+				}
+			}
+			for i := start; i < file.Offset(pos); i++ {
+				if light[i] != '\n' {
+					light[i] = ' '
+				}
+			}
+			inHeader = false
+			parens, typeBraces = 0, 0
+			tok = token.RBRACE
+		case tok == token.SEMICOLON && parens == 0 && typeBraces == 0:
+			// func without body. e.g. implemented by assembly
+			inHeader = false
+		}
+		prev = tok
+	}
+	if s.ErrorCount != 0 {
+		return src
+	}
+	return light
+}
@@ -0,0 +1,139 @@
+package genbase
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParserDeferTypeCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genbase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"a.go": "package sample\n// +test\ntype A struct{ B B }\n",
+		"b.go": "package sample\ntype B struct{ Kind Kind }\ntype Kind int\n",
+		"c.go": "package sample\nfunc (b B) String() string {\n\treturn \"\" // +test in body\n}\nfunc F() struct{ A int } { return struct{ A int }{} }\n",
+		"d.go": "package sample\ntype Other struct{}\n",
+		"e.go": "package sample\n// +broken\ntype Broken struct{ X Missing }\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	checks := 0
+	p := &Parser{DeferTypeCheck: true, AfterTypeCheck: func(pkg *PackageInfo) error {
+		checks++
+		return nil
+	}}
+	pInfo, err := p.ParsePackageDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pInfo.Types != nil {
+		t.Fatal("unexpected: types are resolved")
+	}
+	for _, fn := range pInfo.FuncInfos() {
+		if len(fn.FuncDecl.Body.List) != 0 {
+			t.Errorf("unexpected: body of %s is parsed", fn.Name())
+		}
+	}
+
+	typeInfos, err := pInfo.FindTaggedTypeInfos("+test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pInfo.Types == nil || checks != 1 {
+		t.Fatalf("unexpected: types are not resolved %d", checks)
+	}
+	if pInfo.Types.Scope().Lookup("Kind") == nil || pInfo.Types.Scope().Lookup("Other") != nil {
+		t.Errorf("unexpected: %v", pInfo.Types.Scope().Names())
+	}
+	if len(typeInfos) != 1 || typeInfos[0].TypeObject() == nil || !pInfo.findTypeInfo("B").HasMethod("String", false) {
+		t.Error("unexpected: types are not resolved")
+	}
+	for _, fn := range pInfo.FuncInfos() {
+		if fn.Name() == "String" && len(fn.FuncDecl.Body.List) != 1 {
+			t.Errorf("unexpected: body of %s is not parsed", fn.Name())
+		}
+	}
+
+	types := pInfo.Types
+	if again, err := pInfo.FindTaggedTypeInfos("+test"); err != nil || len(again) != 1 || again[0].TypeObject() != typeInfos[0].TypeObject() {
+		t.Errorf("unexpected: %v %v", again, err)
+	}
+	if pInfo.Types != types || checks != 1 {
+		t.Errorf("unexpected: types are checked again %d", checks)
+	}
+
+	// types are checked again with files of former and new types.
+	others := pInfo.CollectTypeInfos([]string{"Other"})
+	if len(others) != 1 || others[0].TypeObject() == nil || checks != 2 {
+		t.Fatalf("unexpected: %v %d", others, checks)
+	}
+	if pInfo.Types.Scope().Lookup("Kind") == nil || typeInfos[0].TypeObject() == nil || pInfo.Types.Scope().Lookup("Broken") != nil {
+		t.Errorf("unexpected: %v", pInfo.Types.Scope().Names())
+	}
+	if st, err := others[0].StructType(); err != nil || st == nil {
+		t.Errorf("unexpected: %v", err)
+	}
+
+	pInfo, err = p.ParsePackageDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pInfo.FindTaggedTypeInfos("+broken"); err == nil {
+		t.Error("unexpected: error is nil")
+	}
+}
+
+func TestParserDeferTypeCheckCollectEachTag(t *testing.T) {
+	p := &Parser{DeferTypeCheck: true}
+	pInfo, err := p.ParseStringSources(map[string]string{
+		"a.go": "package sample\n// +a\ntype A struct{ N int }\n",
+		"b.go": "package sample\n// +b\ntype B struct{ S string }\n",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tag := range []string{"+a", "+b"} {
+		typeInfos := pInfo.CollectTaggedTypeInfos(tag)
+		if len(typeInfos) != 1 || typeInfos[0].TypeObject() == nil {
+			t.Fatalf("unexpected: %s %v", tag, typeInfos)
+		}
+		st, err := typeInfos[0].StructType()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if typ := pInfo.TypeOf(st.FieldInfos()[0].Type); typ == nil {
+			t.Errorf("unexpected: type of field of %s is not resolved", tag)
+		}
+	}
+}
+
+func TestLightSource(t *testing.T) {
+	src := "package sample\n\n" +
+		"// F is func.\nfunc F(x struct{ A int }) interface{ M() } {\n\tvar y = map[string]int{\"a\": 1}\n\treturn nil\n}\n\n" +
+		"var G = func() {}\n\n" +
+		"func (t *T[K]) M() {}\n\n" +
+		"func asm(x int)\n\n" +
+		"type T[K any] struct{ F func() }\n"
+	expect := "package sample\n\n" +
+		"// F is func.\nfunc F(x struct{ A int }) interface{ M() } {\n                               \n           \n}\n\n" +
+		"var G = func() {}\n\n" +
+		"func (t *T[K]) M() {}\n\n" +
+		"func asm(x int)\n\n" +
+		"type T[K any] struct{ F func() }\n"
+	if v := string(lightSource("a.go", []byte(src))); v != expect {
+		t.Errorf("unexpected: %q", v)
+	}
+	if v := string(lightSource("a.go", []byte("package a\nfunc F() { `"))); v != "package a\nfunc F() { `" {
+		t.Errorf("unexpected: %q", v)
+	}
+}
@@ -0,0 +1,55 @@
+package genbase
+
+import (
+	"go/ast"
+	"strings"
+)
+
+// deprecatedPrefix is prefix of deprecation paragraph in doc comment, it is go/doc convention.
+const deprecatedPrefix = "Deprecated: "
+
+// IsDeprecated returns true if doc comment of type has "Deprecated: " paragraph, otherwise returns false.
+func (t *TypeInfo) IsDeprecated() bool {
+	_, ok := deprecationMessage(t.Doc())
+	return ok
+}
+
+// DeprecationMessage returns message of "Deprecated: " paragraph in doc comment of type, or "" if it is not deprecated.
+// lines of paragraph are joined by space.
+func (t *TypeInfo) DeprecationMessage() string {
+	msg, _ := deprecationMessage(t.Doc())
+	return msg
+}
+
+// IsDeprecated returns true if doc or line comment of field has "Deprecated: " paragraph, otherwise returns false.
+func (f *FieldInfo) IsDeprecated() bool {
+	_, ok := f.deprecationMessage()
+	return ok
+}
+
+// DeprecationMessage returns message of "Deprecated: " paragraph in doc or line comment of field, or "" if it is not deprecated.
+// lines of paragraph are joined by space.
+func (f *FieldInfo) DeprecationMessage() string {
+	msg, _ := f.deprecationMessage()
+	return msg
+}
+
+func (f *FieldInfo) deprecationMessage() (string, bool) {
+	if msg, ok := deprecationMessage(f.Doc); ok {
+		return msg, true
+	}
+	return deprecationMessage(f.Comment)
+}
+
+// deprecationMessage returns message of paragraph that starts with "Deprecated: " in doc.
+func deprecationMessage(doc *ast.CommentGroup) (string, bool) {
+	text, _ := SplitCommentText(doc)
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		if !strings.HasPrefix(paragraph, deprecatedPrefix) {
+			continue
+		}
+		msg := strings.TrimPrefix(paragraph, deprecatedPrefix)
+		return strings.Join(strings.Fields(msg), " "), true
+	}
+	return "", false
+}
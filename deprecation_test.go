@@ -0,0 +1,55 @@
+package genbase
+
+import (
+	"testing"
+)
+
+func TestTypeInfoIsDeprecated(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	// Sample is sample.
+	//
+	// Deprecated: use Other instead,
+	// it is removed in v2.
+	// +jwg
+	type Sample struct {
+		// A is a.
+		// Deprecated: not a paragraph.
+		A string
+		B string // Deprecated: use A.
+		C string
+	}
+
+	// Other is not deprecated.
+	type Other struct{}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := pInfo.TypeInfos()
+	if !types[0].IsDeprecated() {
+		t.Error("unexpected: Sample is not deprecated")
+	}
+	if v := types[0].DeprecationMessage(); v != "use Other instead, it is removed in v2." {
+		t.Errorf("unexpected: %q", v)
+	}
+	if types[1].IsDeprecated() || types[1].DeprecationMessage() != "" {
+		t.Error("unexpected: Other is deprecated")
+	}
+
+	st, err := types[0].StructType()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expects := []string{"", "use A.", ""}
+	for i, f := range st.FieldInfos() {
+		if v := f.DeprecationMessage(); v != expects[i] {
+			t.Errorf("unexpected: %s %q", f.Name(), v)
+		}
+		if v := f.IsDeprecated(); v != (expects[i] != "") {
+			t.Errorf("unexpected: %s %v", f.Name(), v)
+		}
+	}
+}
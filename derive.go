@@ -0,0 +1,102 @@
+package genbase
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DeriveTag is annotation tag of derive list. e.g. "// +derive:Stringer,Equal,Clone"
+const DeriveTag = "+derive"
+
+// DeriveHandler generates code for derive item of type. arg is item in derive list, e.g. Stringer or Stringer=lower
+type DeriveHandler func(g *Generator, t *TypeInfo, arg *AnnotationArg) error
+
+// DeriveRegistry dispatches items of derive annotation to registered handlers.
+type DeriveRegistry struct {
+	Tag string // annotation tag, DeriveTag is used if it is empty
+
+	handlers map[string]DeriveHandler
+}
+
+// NewDeriveRegistry creates new DeriveRegistry for DeriveTag.
+func NewDeriveRegistry() *DeriveRegistry {
+	return &DeriveRegistry{
+		Tag:      DeriveTag,
+		handlers: make(map[string]DeriveHandler),
+	}
+}
+
+// Register registers handler for derive item of name. registered handler of same name is replaced.
+func (r *DeriveRegistry) Register(name string, h DeriveHandler) {
+	if r.handlers == nil {
+		r.handlers = make(map[string]DeriveHandler)
+	}
+	r.handlers[name] = h
+}
+
+// Names returns sorted names of registered handlers.
+func (r *DeriveRegistry) Names() []string {
+	names := make([]string, 0, len(r.handlers))
+	for name := range r.handlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *DeriveRegistry) tag() string {
+	if r.Tag == "" {
+		return DeriveTag
+	}
+	return r.Tag
+}
+
+// Derives returns derive items of type, items of repeated annotations are concatenated.
+// returns *AnnotationError if same item is derived twice on type.
+func (r *DeriveRegistry) Derives(t *TypeInfo) ([]*AnnotationArg, error) {
+	infos, err := t.AllAnnotations(r.tag())
+	if err != nil {
+		return nil, err
+	}
+	var args []*AnnotationArg
+	seen := make(map[string]bool)
+	for _, info := range infos {
+		for _, arg := range info.Args {
+			if seen[arg.Key] {
+				reason := fmt.Sprintf("duplicate derive %s on type %s", arg.Key, t.Name())
+				return nil, &AnnotationError{Position: t.PackageInfo.position(arg.Pos), Key: arg.Key, Reason: reason}
+			}
+			seen[arg.Key] = true
+			args = append(args, arg)
+		}
+	}
+	return args, nil
+}
+
+// Dispatch calls handlers for derive items of types, in order of types and items.
+// returns *AnnotationError if item has no handler, the error suggests similar names.
+func (r *DeriveRegistry) Dispatch(g *Generator, typeInfos TypeInfos) error {
+	for _, t := range typeInfos {
+		args, err := r.Derives(t)
+		if err != nil {
+			return err
+		}
+		for _, arg := range args {
+			h, ok := r.handlers[arg.Key]
+			if !ok {
+				reason := fmt.Sprintf("unknown derive %s on type %s", arg.Key, t.Name())
+				for _, name := range r.Names() {
+					if isNearMiss(arg.Key, name) {
+						reason += fmt.Sprintf(", did you mean %s?", name)
+						break
+					}
+				}
+				return &AnnotationError{Position: t.PackageInfo.position(arg.Pos), Key: arg.Key, Reason: reason}
+			}
+			if err := h(g, t, arg); err != nil {
+				return fmt.Errorf("derive %s on type %s: %w", arg.Key, t.Name(), err)
+			}
+		}
+	}
+	return nil
+}
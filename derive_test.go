@@ -0,0 +1,82 @@
+package genbase
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDeriveRegistryDispatch(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `package sample
+
+// +derive:Stringer=lower
+// +derive: Equal
+type Sample struct{}
+
+// +derive:Equall
+type Other struct{}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewDeriveRegistry()
+	var calls []string
+	for _, name := range []string{"Stringer", "Equal"} {
+		r.Register(name, func(g *Generator, t *TypeInfo, arg *AnnotationArg) error {
+			calls = append(calls, t.Name()+"."+arg.Key+"="+arg.Value)
+			g.Printf("// %s %s\n", t.Name(), arg.Key)
+			return nil
+		})
+	}
+	if v := strings.Join(r.Names(), ","); v != "Equal,Stringer" {
+		t.Errorf("unexpected: %s", v)
+	}
+
+	g := NewGenerator(pInfo)
+	types := pInfo.CollectTaggedTypeInfos(DeriveTag)
+	if err := r.Dispatch(g, types[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if v := strings.Join(calls, " "); v != "Sample.Stringer=lower Sample.Equal=" {
+		t.Errorf("unexpected: %s", v)
+	}
+	if v := g.Buf.String(); v != "// Sample Stringer\n// Sample Equal\n" {
+		t.Errorf("unexpected: %q", v)
+	}
+
+	err = r.Dispatch(g, types)
+	if err == nil || err.Error() != "main.go:7:12: unknown derive Equall on type Other, did you mean Equal?" {
+		t.Errorf("unexpected: %v", err)
+	}
+}
+
+func TestDeriveRegistryDuplicate(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `package sample
+
+// +derive:Stringer,Equal
+// +derive: Stringer=lower
+type Sample struct{}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewDeriveRegistry()
+	called := 0
+	r.Register("Stringer", func(g *Generator, t *TypeInfo, arg *AnnotationArg) error {
+		called++
+		return nil
+	})
+	r.Register("Equal", func(g *Generator, t *TypeInfo, arg *AnnotationArg) error {
+		return nil
+	})
+	err = r.Dispatch(NewGenerator(pInfo), pInfo.CollectTaggedTypeInfos(DeriveTag))
+	if err == nil || err.Error() != "main.go:4:13: duplicate derive Stringer on type Sample" {
+		t.Errorf("unexpected: %v", err)
+	}
+	if called != 0 {
+		t.Errorf("unexpected: %d", called)
+	}
+}
@@ -0,0 +1,294 @@
+package genbase
+
+import (
+	"errors"
+	"fmt"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Suggestion is near-miss candidate found by failed lookup.
+type Suggestion struct {
+	Found    string // e.g. "+jwgg", "User"
+	TypeName string
+	Position token.Position
+}
+
+// NotFoundError shows that annotated types or named type are not found.
+// it has near-miss candidates as Suggestions.
+type NotFoundError struct {
+	Tag         string // annotation tag, it is set for tagged lookup
+	TypeName    string // type name, it is set for named lookup
+	Suggestions []*Suggestion
+}
+
+// NotFoundErrors is []*NotFoundError synonym.
+type NotFoundErrors []*NotFoundError
+
+func (err *NotFoundError) Error() string {
+	var msg string
+	if err.Tag != "" {
+		msg = fmt.Sprintf("no type annotated with %s", err.Tag)
+	} else {
+		msg = fmt.Sprintf("type %s is not found", err.TypeName)
+	}
+	lines := []string{msg}
+	for _, s := range err.Suggestions {
+		pos := fmt.Sprintf("%s:%d", s.Position.Filename, s.Position.Line)
+		if err.Tag != "" {
+			lines = append(lines, fmt.Sprintf("found `%s` on type %s at %s, did you mean `%s`?", s.Found, s.TypeName, pos, err.Tag))
+		} else {
+			lines = append(lines, fmt.Sprintf("found type %s at %s, did you mean %s?", s.TypeName, pos, s.TypeName))
+		}
+	}
+	return strings.Join(lines, "\n\t")
+}
+
+func (errs NotFoundErrors) Error() string {
+	ss := make([]string, 0, len(errs))
+	for _, err := range errs {
+		ss = append(ss, err.Error())
+	}
+	return strings.Join(ss, "\n")
+}
+
+// FindTaggedTypeInfos is CollectTaggedTypeInfos that returns *NotFoundError when no type is annotated with tag.
+// the error has near-miss annotations like misspelled ones.
+// malformed annotations are reported as AnnotationErrors, see ParseTaggedTypeInfos.
+// duplicates are checked by CheckDuplicates if StrictDuplicateCheck is enabled,
+// and unexported types are checked by UnexportedPolicy.
+func (pkg *PackageInfo) FindTaggedTypeInfos(tag string) (TypeInfos, error) {
+	typeInfos, err := pkg.collectTaggedTypeInfos(tag)
+	if err != nil {
+		return nil, err
+	}
+	if err := pkg.checkAnnotations(tag, typeInfos); err != nil {
+		return nil, err
+	}
+	if len(typeInfos) != 0 && pkg.StrictDuplicateCheck {
+		if err := pkg.CheckDuplicates(tag, typeInfos); err != nil {
+			return nil, err
+		}
+	}
+	if err := pkg.checkUnexported(tag, typeInfos); err != nil {
+		return nil, err
+	}
+	if len(typeInfos) != 0 {
+		return typeInfos, nil
+	}
+
+	nfErr := &NotFoundError{Tag: tag}
+	for _, t := range pkg.TypeInfos() {
+		for _, doc := range t.Comments() {
+			for _, c := range doc.List {
+				text := strings.TrimLeft(c.Text, "/ ")
+				if !strings.HasPrefix(text, "+") {
+					continue
+				}
+				fields := strings.FieldsFunc(text, func(r rune) bool { return r == ' ' || r == ':' })
+				found := fields[0]
+				if found == "+" && len(fields) > 1 {
+					found = "+ " + fields[1]
+				}
+				if !isNearMiss(strings.Replace(found, " ", "", 1), tag) {
+					continue
+				}
+				nfErr.Suggestions = append(nfErr.Suggestions, &Suggestion{
+					Found:    found,
+					TypeName: t.Name(),
+					Position: pkg.FileSet.Position(c.Pos()),
+				})
+			}
+		}
+	}
+	return nil, nfErr
+}
+
+// FindTypeInfo returns TypeInfo that has name.
+// it returns *NotFoundError when type is not found, the error has types that have similar names.
+func (pkg *PackageInfo) FindTypeInfo(name string) (*TypeInfo, error) {
+	if t := pkg.findTypeInfo(name); t != nil {
+		return t, nil
+	}
+
+	err := &NotFoundError{TypeName: name}
+	var distances []int
+	for _, t := range pkg.TypeInfos() {
+		if !isNearMiss(t.Name(), name) {
+			continue
+		}
+		err.Suggestions = append(err.Suggestions, &Suggestion{
+			Found:    t.Name(),
+			TypeName: t.Name(),
+			Position: pkg.FileSet.Position(t.TypeSpec.Pos()),
+		})
+		distances = append(distances, EditDistance(strings.ToLower(t.Name()), strings.ToLower(name)))
+	}
+	sort.Stable(&suggestionsByDistance{err.Suggestions, distances})
+	return nil, err
+}
+
+// FindTypeInfos is CollectTypeInfos that returns NotFoundErrors when some of typeNames are not found.
+// each error has types that have similar names, like FindTypeInfo.
+func (pkg *PackageInfo) FindTypeInfos(typeNames []string) (TypeInfos, error) {
+	typeInfos, err := pkg.collectTypeInfos(typeNames)
+	var errs NotFoundErrors
+	for _, name := range typeNames {
+		if pkg.findTypeInfo(name) != nil {
+			continue
+		}
+		_, err := pkg.FindTypeInfo(name)
+		if nfErr, ok := err.(*NotFoundError); ok {
+			errs = append(errs, nfErr)
+		}
+	}
+	if len(errs) != 0 {
+		return nil, errs
+	}
+	if err != nil {
+		return nil, err
+	}
+	return typeInfos, nil
+}
+
+// isNearMiss returns true if s is like a typo of expect, otherwise returns false.
+func isNearMiss(s, expect string) bool {
+	if s == expect {
+		return s != ""
+	}
+	if strings.EqualFold(s, expect) {
+		return true
+	}
+	d := EditDistance(strings.ToLower(s), strings.ToLower(expect))
+	if d >= minInt(len(s), len(expect)) {
+		return false
+	}
+	switch n := maxInt(len(s), len(expect)); {
+	case n <= 4:
+		return d <= 1
+	case n <= 8:
+		return d <= 2
+	}
+	return d <= 3
+}
+
+type suggestionsByDistance struct {
+	suggestions []*Suggestion
+	distances   []int
+}
+
+func (s *suggestionsByDistance) Len() int { return len(s.suggestions) }
+
+func (s *suggestionsByDistance) Less(i, j int) bool { return s.distances[i] < s.distances[j] }
+
+func (s *suggestionsByDistance) Swap(i, j int) {
+	s.suggestions[i], s.suggestions[j] = s.suggestions[j], s.suggestions[i]
+	s.distances[i], s.distances[j] = s.distances[j], s.distances[i]
+}
+
+// MultiplePackagesError shows that directory has Go files of multiple packages.
+type MultiplePackagesError struct {
+	Dir      string
+	Packages []string            // sorted package names
+	Files    map[string][]string // file names of each package
+}
+
+func (err *MultiplePackagesError) Error() string {
+	found := make([]string, 0, len(err.Packages))
+	for _, name := range err.Packages {
+		found = append(found, fmt.Sprintf("%s (%s)", name, strings.Join(err.Files[name], ", ")))
+	}
+	return fmt.Sprintf("directory %s has files of multiple packages: %s\n\t"+
+		"parse files of one package by ParsePackageFiles, or exclude others by build constraints",
+		err.Dir, strings.Join(found, ", "))
+}
+
+// NoGoFilesError shows that directory has no Go files to parse.
+// Skipped are files that were found and the reasons why they were not parsed.
+type NoGoFilesError struct {
+	Dir     string
+	Skipped []*SkippedFile
+}
+
+func (err *NoGoFilesError) Error() string {
+	lines := []string{fmt.Sprintf("%s: no buildable Go files", err.Dir)}
+	hints := make(map[SkipReason]bool)
+	for _, f := range err.Skipped {
+		lines = append(lines, fmt.Sprintf("found %s: %s", filepath.Base(f.Name), f.Reason))
+		hints[f.Reason] = true
+	}
+	if hints[SkipBuildConstraints] {
+		lines = append(lines, "set BuildTags of Parser to include files excluded by build constraints")
+	}
+	if hints[SkipTestFile] {
+		lines = append(lines, "test files are not parsed, declare annotated types in non-test files")
+	}
+	return strings.Join(lines, "\n\t")
+}
+
+// importDirError returns specific error of build.Context.ImportDir for directory.
+func (p *Parser) importDirError(directory string, pkg *build.Package, err error) error {
+	var multiErr *build.MultiplePackageError
+	var noGoErr *build.NoGoError
+	switch {
+	case errors.As(err, &multiErr):
+		return p.multiplePackagesError(directory, multiErr)
+	case errors.As(err, &noGoErr) && pkg != nil:
+		return &NoGoFilesError{Dir: directory, Skipped: ignoredFiles(directory, pkg)}
+	}
+	return fmt.Errorf("cannot process directory %s: %s", directory, err)
+}
+
+// multiplePackagesError finds all packages in directory by package clauses of Go files,
+// because build.MultiplePackageError has only the first conflict.
+func (p *Parser) multiplePackagesError(directory string, multiErr *build.MultiplePackageError) error {
+	ctxt := p.buildContext()
+	err := &MultiplePackagesError{Dir: directory, Files: make(map[string][]string)}
+	names, _ := filepath.Glob(filepath.Join(directory, "*.go"))
+	sort.Strings(names)
+	fs := token.NewFileSet()
+	for _, name := range names {
+		base := filepath.Base(name)
+		if strings.HasSuffix(base, "_test.go") {
+			continue
+		}
+		if match, matchErr := ctxt.MatchFile(directory, base); matchErr != nil || !match {
+			continue
+		}
+		file, parseErr := parser.ParseFile(fs, name, nil, parser.PackageClauseOnly)
+		if parseErr != nil {
+			continue
+		}
+		if _, ok := err.Files[file.Name.Name]; !ok {
+			err.Packages = append(err.Packages, file.Name.Name)
+		}
+		err.Files[file.Name.Name] = append(err.Files[file.Name.Name], base)
+	}
+	if len(err.Packages) < 2 {
+		// fall back to the conflict that go/build found.
+		err.Packages = nil
+		err.Files = make(map[string][]string)
+		for i, name := range multiErr.Packages {
+			if _, ok := err.Files[name]; !ok {
+				err.Packages = append(err.Packages, name)
+			}
+			err.Files[name] = append(err.Files[name], filepath.Base(multiErr.Files[i]))
+		}
+	}
+	sort.Strings(err.Packages)
+	return err
+}
+
+// ignoredFiles returns files of pkg that are not parsed, like test files and files excluded by build constraints.
+func ignoredFiles(directory string, pkg *build.Package) []*SkippedFile {
+	var skipped []*SkippedFile
+	skipped = appendSkippedFiles(skipped, SkipTestFile, pathJoinAll(directory, pkg.TestGoFiles...)...)
+	skipped = appendSkippedFiles(skipped, SkipTestFile, pathJoinAll(directory, pkg.XTestGoFiles...)...)
+	skipped = appendSkippedFiles(skipped, SkipBuildConstraints, pathJoinAll(directory, pkg.IgnoredGoFiles...)...)
+	skipped = appendSkippedFiles(skipped, SkipNotGoFile, pathJoinAll(directory, pkg.IgnoredOtherFiles...)...)
+	return skipped
+}
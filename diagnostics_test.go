@@ -0,0 +1,177 @@
+package genbase
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPackageInfoFindTaggedTypeInfos(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("user.go", `
+	package sample
+
+	// +jwgg
+	type User struct{}
+
+	// + jwg
+	type Group struct{}
+
+	// +qbg
+	type Item struct{}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if typeInfos, err := pInfo.FindTaggedTypeInfos("+qbg"); err != nil || len(typeInfos) != 1 {
+		t.Fatalf("unexpected: %v %v", typeInfos, err)
+	}
+
+	_, err = pInfo.FindTaggedTypeInfos("+jwg")
+	nfErr, ok := err.(*NotFoundError)
+	if !ok {
+		t.Fatalf("unexpected: %v", err)
+	}
+	if len(nfErr.Suggestions) != 2 {
+		t.Fatalf("unexpected: %v", len(nfErr.Suggestions))
+	}
+	if s := nfErr.Suggestions[0]; s.Found != "+jwgg" || s.TypeName != "User" || s.Position.Line != 4 {
+		t.Fatalf("unexpected: %#v", s)
+	}
+	if s := nfErr.Suggestions[1]; s.Found != "+ jwg" || s.TypeName != "Group" {
+		t.Fatalf("unexpected: %#v", s)
+	}
+	if v := err.Error(); !strings.Contains(v, "found `+jwgg` on type User at user.go:4, did you mean `+jwg`?") {
+		t.Fatalf("unexpected: %s", v)
+	}
+}
+
+func TestPackageInfoFindTypeInfo(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("user.go", `
+	package sample
+
+	type User struct{}
+
+	type Users []User
+
+	type Item struct{}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if typeInfo, err := pInfo.FindTypeInfo("User"); err != nil || typeInfo.Name() != "User" {
+		t.Fatalf("unexpected: %v %v", typeInfo, err)
+	}
+
+	_, err = pInfo.FindTypeInfo("Usr")
+	nfErr, ok := err.(*NotFoundError)
+	if !ok {
+		t.Fatalf("unexpected: %v", err)
+	}
+	if len(nfErr.Suggestions) != 2 || nfErr.Suggestions[0].TypeName != "User" || nfErr.Suggestions[1].TypeName != "Users" {
+		t.Fatalf("unexpected: %v", nfErr.Suggestions)
+	}
+	if v := err.Error(); !strings.HasPrefix(v, "type Usr is not found\n\tfound type User at user.go:4, did you mean User?") {
+		t.Fatalf("unexpected: %s", v)
+	}
+}
+
+func TestPackageInfoFindTypeInfos(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("user.go", `
+	package sample
+
+	type User struct{}
+
+	type Item struct{}
+
+	type Order struct{}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	typeInfos, err := pInfo.FindTypeInfos([]string{"Order", "User"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(typeInfos) != 2 || typeInfos[0].Name() != "User" || typeInfos[1].Name() != "Order" {
+		t.Fatalf("unexpected: %v", typeInfos)
+	}
+
+	_, err = pInfo.FindTypeInfos([]string{"Usr", "Item", "Unknown"})
+	errs, ok := err.(NotFoundErrors)
+	if !ok || len(errs) != 2 {
+		t.Fatalf("unexpected: %v", err)
+	}
+	if errs[0].TypeName != "Usr" || len(errs[0].Suggestions) != 1 || errs[0].Suggestions[0].TypeName != "User" {
+		t.Errorf("unexpected: %v", errs[0])
+	}
+	if errs[1].TypeName != "Unknown" || len(errs[1].Suggestions) != 0 {
+		t.Errorf("unexpected: %v", errs[1])
+	}
+	if v := err.Error(); v != "type Usr is not found\n\tfound type User at user.go:4, did you mean User?\ntype Unknown is not found" {
+		t.Errorf("unexpected: %s", v)
+	}
+}
+
+func TestParsePackageDirDiagnostics(t *testing.T) {
+	writeFiles := func(files map[string]string) string {
+		dir, err := ioutil.TempDir("", "genbase")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name, content := range files {
+			if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return dir
+	}
+
+	dir := writeFiles(map[string]string{
+		"a.go":      "package foo\n",
+		"b.go":      "package foo\n",
+		"main.go":   "package main\n",
+		"c_test.go": "package other\n",
+	})
+	defer os.RemoveAll(dir)
+	_, err := (&Parser{}).ParsePackageDir(dir)
+	multiErr, ok := err.(*MultiplePackagesError)
+	if !ok {
+		t.Fatalf("unexpected: %v", err)
+	}
+	if strings.Join(multiErr.Packages, ",") != "foo,main" || strings.Join(multiErr.Files["foo"], ",") != "a.go,b.go" {
+		t.Errorf("unexpected: %v %v", multiErr.Packages, multiErr.Files)
+	}
+	if !strings.Contains(err.Error(), "foo (a.go, b.go), main (main.go)") {
+		t.Errorf("unexpected: %s", err)
+	}
+	if _, err := NewSession(nil, dir); err == nil || err.Error() != multiErr.Error() {
+		t.Errorf("unexpected: %v", err)
+	}
+
+	dir = writeFiles(map[string]string{
+		"a_test.go": "package foo\n",
+		"b.go":      "//go:build ignore\n\npackage foo\n",
+	})
+	defer os.RemoveAll(dir)
+	_, err = (&Parser{}).ParsePackageDir(dir)
+	noGoErr, ok := err.(*NoGoFilesError)
+	if !ok {
+		t.Fatalf("unexpected: %v", err)
+	}
+	if len(noGoErr.Skipped) != 2 {
+		t.Errorf("unexpected: %v", noGoErr.Skipped)
+	}
+	for _, s := range []string{"no buildable Go files", "found a_test.go: test file", "found b.go: excluded by build constraints", "BuildTags"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("unexpected: %s", err)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package genbase
+
+import (
+	"fmt"
+)
+
+// ChangeKind is kind of Change.
+type ChangeKind string
+
+const (
+	// ChangeAdded shows type or field is added.
+	ChangeAdded ChangeKind = "added"
+	// ChangeRemoved shows type or field is removed.
+	ChangeRemoved ChangeKind = "removed"
+	// ChangeRetyped shows type of field or underlying type is changed.
+	ChangeRetyped ChangeKind = "retyped"
+	// ChangeTagChanged shows tags of field are changed.
+	ChangeTagChanged ChangeKind = "tag_changed"
+)
+
+// Change is difference between two Models.
+type Change struct {
+	Kind  ChangeKind `json:"kind"`
+	Type  string     `json:"type"`
+	Field string     `json:"field,omitempty"` // "" for change of type itself
+	Old   string     `json:"old,omitempty"`   // old type expression or tags
+	New   string     `json:"new,omitempty"`   // new type expression or tags
+}
+
+func (c *Change) String() string {
+	name := c.Type
+	if c.Field != "" {
+		name += "." + c.Field
+	}
+	switch c.Kind {
+	case ChangeAdded, ChangeRemoved:
+		return fmt.Sprintf("%s %s", name, c.Kind)
+	}
+	return fmt.Sprintf("%s %s: %s -> %s", name, c.Kind, c.Old, c.New)
+}
+
+// Diff returns changes from old to new.
+// changes of types in old come first in order of old, and added types follow in order of new.
+func Diff(old, new *Model) []*Change {
+	var changes []*Change
+	newTypes := make(map[string]*Type)
+	for _, t := range new.Types {
+		newTypes[t.Name] = t
+	}
+	oldTypes := make(map[string]*Type)
+	for _, t := range old.Types {
+		oldTypes[t.Name] = t
+		nt, ok := newTypes[t.Name]
+		if !ok {
+			changes = append(changes, &Change{Kind: ChangeRemoved, Type: t.Name})
+			continue
+		}
+		changes = append(changes, diffType(t, nt)...)
+	}
+	for _, t := range new.Types {
+		if _, ok := oldTypes[t.Name]; !ok {
+			changes = append(changes, &Change{Kind: ChangeAdded, Type: t.Name})
+		}
+	}
+	return changes
+}
+
+func diffType(old, new *Type) []*Change {
+	if old.Kind != new.Kind || old.Kind != KindStruct {
+		oldExpr, newExpr := typeExpr(old), typeExpr(new)
+		if oldExpr != newExpr {
+			return []*Change{{Kind: ChangeRetyped, Type: old.Name, Old: oldExpr, New: newExpr}}
+		}
+		return nil
+	}
+
+	var changes []*Change
+	newFields := make(map[string]*Field)
+	for _, f := range new.Fields {
+		newFields[f.Name] = f
+	}
+	oldFields := make(map[string]*Field)
+	for _, f := range old.Fields {
+		oldFields[f.Name] = f
+		nf, ok := newFields[f.Name]
+		if !ok {
+			changes = append(changes, &Change{Kind: ChangeRemoved, Type: old.Name, Field: f.Name})
+			continue
+		}
+		if o, n := f.Type.String(), nf.Type.String(); o != n {
+			changes = append(changes, &Change{Kind: ChangeRetyped, Type: old.Name, Field: f.Name, Old: o, New: n})
+		}
+		if o, n := f.Tags.String(), nf.Tags.String(); o != n {
+			changes = append(changes, &Change{Kind: ChangeTagChanged, Type: old.Name, Field: f.Name, Old: o, New: n})
+		}
+	}
+	for _, f := range new.Fields {
+		if _, ok := oldFields[f.Name]; !ok {
+			changes = append(changes, &Change{Kind: ChangeAdded, Type: old.Name, Field: f.Name})
+		}
+	}
+	return changes
+}
+
+// typeExpr returns Go source of type declared by t.
+func typeExpr(t *Type) string {
+	if t.Kind == KindStruct {
+		return (&TypeRef{Kind: KindStruct, Fields: t.Fields}).String()
+	}
+	return t.Underlying.String()
+}
@@ -0,0 +1,66 @@
+package genbase
+
+import (
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	model := func(src string) *Model {
+		p := &Parser{}
+		pInfo, err := p.ParseStringSource("main.go", src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return NewModel(pInfo, pInfo.TypeInfos())
+	}
+
+	old := model(`
+	package sample
+
+	type User struct {
+		Name  string ` + "`json:\"name\"`" + `
+		Age   int
+		Email string
+	}
+
+	type ID int
+
+	type Group struct{}
+	`)
+	new := model(`
+	package sample
+
+	type User struct {
+		Name    string ` + "`json:\"name,omitempty\"`" + `
+		Age     int64
+		Address string
+	}
+
+	type ID string
+
+	type Item struct{}
+	`)
+
+	expects := []string{
+		`User.Name tag_changed: json:"name" -> json:"name,omitempty"`,
+		"User.Age retyped: int -> int64",
+		"User.Email removed",
+		"User.Address added",
+		"ID retyped: int -> string",
+		"Group removed",
+		"Item added",
+	}
+	changes := Diff(old, new)
+	if len(changes) != len(expects) {
+		t.Fatalf("unexpected: %v", changes)
+	}
+	for i, c := range changes {
+		if v := c.String(); v != expects[i] {
+			t.Errorf("unexpected: %s", v)
+		}
+	}
+
+	if changes := Diff(old, old); len(changes) != 0 {
+		t.Fatalf("unexpected: %v", changes)
+	}
+}
@@ -0,0 +1,253 @@
+package genbase
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DuplicateError shows identifier or annotation is declared twice.
+type DuplicateError struct {
+	Name     string // e.g. "User", "User.String", "+jwg"
+	Position token.Position
+	Previous token.Position
+	Reason   string
+}
+
+// DuplicateErrors is []*DuplicateError synonym.
+type DuplicateErrors []*DuplicateError
+
+func (err *DuplicateError) Error() string {
+	return fmt.Sprintf("%s: %s, previous declaration at %s", err.Position, err.Reason, err.Previous)
+}
+
+func (errs DuplicateErrors) Error() string {
+	ss := make([]string, 0, len(errs))
+	for _, err := range errs {
+		ss = append(ss, err.Error())
+	}
+	return strings.Join(ss, "\n")
+}
+
+// CheckDuplicates checks that annotated types has unique names,
+// and each type doesn't have annotations of tag that have conflicting options.
+// returns DuplicateErrors if duplicates are found.
+func (pkg *PackageInfo) CheckDuplicates(tag string, typeInfos TypeInfos) error {
+	var errs DuplicateErrors
+	declared := make(map[string]*TypeInfo)
+	for _, t := range typeInfos {
+		if prev, ok := declared[t.Name()]; ok {
+			errs = append(errs, &DuplicateError{
+				Name:     t.Name(),
+				Position: pkg.FileSet.Position(t.TypeSpec.Pos()),
+				Previous: pkg.FileSet.Position(prev.TypeSpec.Pos()),
+				Reason:   fmt.Sprintf("type %s is annotated with %s twice", t.Name(), tag),
+			})
+		} else {
+			declared[t.Name()] = t
+		}
+
+		var first *ast.Comment
+		for _, doc := range t.Comments() {
+			for _, c := range doc.List {
+				if findAnnotation(&ast.CommentGroup{List: []*ast.Comment{c}}, tag) == nil {
+					continue
+				}
+				if first == nil {
+					first = c
+					continue
+				}
+				if normalizeAnnotation(c.Text) != normalizeAnnotation(first.Text) {
+					errs = append(errs, &DuplicateError{
+						Name:     tag,
+						Position: pkg.FileSet.Position(c.Pos()),
+						Previous: pkg.FileSet.Position(first.Pos()),
+						Reason:   fmt.Sprintf("type %s is annotated with %s twice with conflicting options", t.Name(), tag),
+					})
+				}
+			}
+		}
+	}
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+// CheckRedeclaration checks that generated code doesn't redeclare identifiers in package.
+// file that has same name as fileName in package is ignored because it is replaced by generated code.
+// methods are checked by "Type.Method" form. returns DuplicateErrors if redeclarations are found.
+func (pkg *PackageInfo) CheckRedeclaration(fileName string, src []byte) error {
+	genFile, err := parser.ParseFile(pkg.FileSet, fileName, src, 0)
+	if err != nil {
+		return fmt.Errorf("parsing generated code: %s: %s", fileName, err)
+	}
+
+	declared := make(map[string]token.Pos)
+	for _, file := range pkg.Files {
+		name := pkg.FileSet.Position(file.Package).Filename
+		if filepath.Base(name) == filepath.Base(fileName) {
+			continue
+		}
+		for name, pos := range topLevelDecls(file.AstFile()) {
+			declared[name] = pos
+		}
+	}
+
+	var errs DuplicateErrors
+	for name, pos := range topLevelDecls(genFile) {
+		prev, ok := declared[name]
+		if !ok {
+			continue
+		}
+		errs = append(errs, &DuplicateError{
+			Name:     name,
+			Position: pkg.FileSet.Position(pos),
+			Previous: pkg.FileSet.Position(prev),
+			Reason:   fmt.Sprintf("%s redeclared in this package", name),
+		})
+	}
+	// declarations are collected into map, so errors are sorted by position.
+	sort.Slice(errs, func(i, j int) bool {
+		a, b := errs[i].Position, errs[j].Position
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Offset < b.Offset
+	})
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+// topLevelDecls returns positions of identifiers that are declared in top level of file.
+func topLevelDecls(file *ast.File) map[string]token.Pos {
+	decls := make(map[string]token.Pos)
+	add := func(ident *ast.Ident) {
+		if ident.Name != "_" && ident.Name != "init" {
+			decls[ident.Name] = ident.Pos()
+		}
+	}
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			fn := &FuncInfo{FuncDecl: decl}
+			if fn.IsMethod() {
+				decls[fn.ReceiverTypeName()+"."+fn.Name()] = decl.Name.Pos()
+				continue
+			}
+			add(decl.Name)
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					add(spec.Name)
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						add(name)
+					}
+				}
+			}
+		}
+	}
+	return decls
+}
+
+// normalizeAnnotation returns annotation text without comment marker and redundant spaces.
+func normalizeAnnotation(text string) string {
+	return strings.Join(strings.Fields(strings.TrimLeft(text, "/ ")), " ")
+}
+
+// PlannedIdent is identifier that generator is going to declare.
+type PlannedIdent struct {
+	Name     string    // e.g. "UserJSONBuilder"
+	Receiver string    // receiver type name if it is method or field. e.g. "User"
+	Origin   token.Pos // position in package that identifier is derived from, e.g. annotated type
+}
+
+func (ident *PlannedIdent) String() string {
+	if ident.Receiver != "" {
+		return ident.Receiver + "." + ident.Name
+	}
+	return ident.Name
+}
+
+// CheckCollisions checks that planned identifiers don't collide with declarations in scope of package,
+// and with each other. methods collide with fields and methods of receiver type.
+// returns DuplicateErrors that have positions of both declarations if collisions are found.
+func (pkg *PackageInfo) CheckCollisions(idents ...*PlannedIdent) error {
+	if pkg.Types == nil {
+		return errors.New("types are not resolved")
+	}
+
+	var errs DuplicateErrors
+	planned := make(map[string]*PlannedIdent)
+	for _, ident := range idents {
+		name := ident.String()
+		if prev, ok := planned[name]; ok {
+			errs = append(errs, &DuplicateError{
+				Name:     name,
+				Position: pkg.FileSet.Position(ident.Origin),
+				Previous: pkg.FileSet.Position(prev.Origin),
+				Reason:   fmt.Sprintf("%s is planned twice", name),
+			})
+			continue
+		}
+		planned[name] = ident
+
+		if obj := pkg.lookupPlanned(ident); obj != nil {
+			errs = append(errs, &DuplicateError{
+				Name:     name,
+				Position: pkg.FileSet.Position(ident.Origin),
+				Previous: pkg.FileSet.Position(obj.Pos()),
+				Reason:   fmt.Sprintf("%s collides with existing %s", name, objectKind(obj)),
+			})
+		}
+	}
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+// lookupPlanned returns existing object that has same name as ident.
+func (pkg *PackageInfo) lookupPlanned(ident *PlannedIdent) types.Object {
+	scope := pkg.Types.Scope()
+	if ident.Receiver == "" {
+		return scope.Lookup(ident.Name)
+	}
+	recv, ok := scope.Lookup(ident.Receiver).(*types.TypeName)
+	if !ok {
+		return nil
+	}
+	obj, _, _ := types.LookupFieldOrMethod(recv.Type(), true, pkg.Types, ident.Name)
+	return obj
+}
+
+// objectKind returns kind of object for messages.
+func objectKind(obj types.Object) string {
+	switch obj := obj.(type) {
+	case *types.TypeName:
+		return "type"
+	case *types.Const:
+		return "const"
+	case *types.Func:
+		if obj.Type().(*types.Signature).Recv() != nil {
+			return "method"
+		}
+		return "func"
+	case *types.Var:
+		if obj.IsField() {
+			return "field"
+		}
+		return "var"
+	}
+	return "declaration"
+}
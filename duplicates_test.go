@@ -0,0 +1,180 @@
+package genbase
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPackageInfoCheckDuplicates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genbase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"a.go": `package sample
+
+// +jwg
+type User struct{}
+
+// +jwg
+// +jwg transcripttag=json
+type Group struct{}
+`,
+		"b.go": `package sample
+
+// +jwg
+type User struct{}
+`,
+	}
+	var names []string
+	for name, content := range files {
+		name = filepath.Join(dir, name)
+		if err := ioutil.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, name)
+	}
+
+	p := &Parser{SkipSemanticsCheck: true, StrictDuplicateCheck: true}
+	pInfo, err := p.ParsePackageFiles(names)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = pInfo.FindTaggedTypeInfos("+jwg")
+	errs, ok := err.(DuplicateErrors)
+	if !ok {
+		t.Fatalf("unexpected: %v", err)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("unexpected: %v", errs)
+	}
+	for _, err := range errs {
+		switch err.Name {
+		case "User":
+			if !strings.Contains(err.Reason, "annotated with +jwg twice") {
+				t.Errorf("unexpected: %s", err.Reason)
+			}
+		case "+jwg":
+			if !strings.Contains(err.Reason, "conflicting options") || err.Position.Line != 7 || err.Previous.Line != 6 {
+				t.Errorf("unexpected: %v", err)
+			}
+		default:
+			t.Errorf("unexpected: %v", err)
+		}
+	}
+
+	pInfo.StrictDuplicateCheck = false
+	if _, err := pInfo.FindTaggedTypeInfos("+jwg"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPackageInfoCheckRedeclaration(t *testing.T) {
+	p := &Parser{StrictDuplicateCheck: true}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	type Sample struct{}
+
+	func (s Sample) String() string { return "" }
+
+	var DefaultSample = Sample{}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pInfo.CheckRedeclaration("sample_gen.go", []byte(`package sample
+
+func (s Sample) String() string { return "" }
+
+func (s *Sample) GoString() string { return "" }
+
+var DefaultSample = Sample{}
+`))
+	errs, ok := err.(DuplicateErrors)
+	if !ok || len(errs) != 2 {
+		t.Fatalf("unexpected: %v", err)
+	}
+	if errs[0].Name != "Sample.String" || errs[1].Name != "DefaultSample" {
+		t.Errorf("unexpected: %v", err)
+	}
+	for _, err := range errs {
+		if err.Position.Filename != "sample_gen.go" || err.Previous.Filename != "main.go" {
+			t.Errorf("unexpected: %v", err)
+		}
+	}
+
+	// replaced file is ignored
+	if err := pInfo.CheckRedeclaration("main.go", []byte("package sample\n\ntype Sample struct{}\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pInfo.CheckGenerated("sample_gen.go", []byte("package sample\n\nvar DefaultSample = Sample{}\n")); err == nil || !strings.Contains(err.Error(), "DefaultSample redeclared") {
+		t.Fatalf("unexpected: %v", err)
+	}
+}
+
+func TestPackageInfoCheckCollisions(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	type Sample struct {
+		Name string
+	}
+
+	func (s *Sample) String() string { return "" }
+
+	type SampleJSON struct{}
+
+	const DefaultName = ""
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	origin := pInfo.CollectTypeInfos([]string{"Sample"})[0].TypeSpec.Pos()
+
+	err = pInfo.CheckCollisions(
+		&PlannedIdent{Name: "SampleJSON", Origin: origin},
+		&PlannedIdent{Name: "SampleBuilder", Origin: origin},
+		&PlannedIdent{Name: "SampleBuilder", Origin: origin},
+		&PlannedIdent{Name: "Name", Receiver: "Sample", Origin: origin},
+		&PlannedIdent{Name: "String", Receiver: "Sample", Origin: origin},
+		&PlannedIdent{Name: "MarshalJSON", Receiver: "Sample", Origin: origin},
+		&PlannedIdent{Name: "DefaultName", Origin: origin},
+	)
+	errs, ok := err.(DuplicateErrors)
+	if !ok {
+		t.Fatalf("unexpected: %v", err)
+	}
+	expects := []string{
+		"SampleJSON collides with existing type",
+		"SampleBuilder is planned twice",
+		"Sample.Name collides with existing field",
+		"Sample.String collides with existing method",
+		"DefaultName collides with existing const",
+	}
+	if len(errs) != len(expects) {
+		t.Fatalf("unexpected: %v", errs)
+	}
+	for i, err := range errs {
+		if err.Reason != expects[i] {
+			t.Errorf("unexpected: %s", err.Reason)
+		}
+		if err.Position.Line != 4 {
+			t.Errorf("unexpected: %v", err.Position)
+		}
+	}
+	if errs[0].Previous.Line != 10 {
+		t.Fatalf("unexpected: %v", errs[0].Previous)
+	}
+
+	if err := pInfo.CheckCollisions(&PlannedIdent{Name: "SampleBuilder"}); err != nil {
+		t.Fatal(err)
+	}
+}
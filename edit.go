@@ -0,0 +1,200 @@
+package genbase
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/printer"
+	"go/token"
+	"sort"
+	"strconv"
+)
+
+// FileEditor rewrites source of file by edits on its nodes.
+// bytes between edits are kept as they are written, so comments and spacing of user are preserved,
+// unlike printing mutated AST by go/printer.
+type FileEditor struct {
+	pkg   *PackageInfo
+	file  *token.File
+	src   []byte
+	edits []*sourceEdit
+}
+
+// sourceEdit replaces src[start:end] by text.
+type sourceEdit struct {
+	start, end int
+	text       string
+}
+
+// NewFileEditor creates FileEditor of file in package, returns ErrNoSource if source of file is not available.
+func (pkg *PackageInfo) NewFileEditor(file *FileInfo) (*FileEditor, error) {
+	src := pkg.Source(file)
+	if src == nil {
+		return nil, ErrNoSource
+	}
+	return &FileEditor{pkg: pkg, file: pkg.FileSet.File(file.Package), src: src}, nil
+}
+
+// offset returns offset of pos in source, or error if pos is not in file.
+func (e *FileEditor) offset(pos token.Pos) (int, error) {
+	if !pos.IsValid() || pos < token.Pos(e.file.Base()) || int(pos) > e.file.Base()+e.file.Size() {
+		return 0, fmt.Errorf("position %d is not in %s", pos, e.file.Name())
+	}
+	return e.file.Offset(pos), nil
+}
+
+// add records edit that replaces source between pos and end by text.
+func (e *FileEditor) add(pos, end token.Pos, text string) error {
+	start, err := e.offset(pos)
+	if err != nil {
+		return err
+	}
+	stop, err := e.offset(end)
+	if err != nil {
+		return err
+	}
+	e.edits = append(e.edits, &sourceEdit{start: start, end: stop, text: text})
+	return nil
+}
+
+// Replace replaces source of node by text. comments in node are replaced too.
+func (e *FileEditor) Replace(node ast.Node, text string) error {
+	return e.add(node.Pos(), node.End(), text)
+}
+
+// ReplaceNode replaces source of node by newNode printed by go/printer.
+// newNode is usually built or copied from AST, and it must not have comments.
+func (e *FileEditor) ReplaceNode(node ast.Node, newNode ast.Node) error {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, token.NewFileSet(), newNode); err != nil {
+		return err
+	}
+	return e.Replace(node, buf.String())
+}
+
+// InsertBefore inserts text before node.
+func (e *FileEditor) InsertBefore(node ast.Node, text string) error {
+	return e.add(node.Pos(), node.Pos(), text)
+}
+
+// InsertAfter inserts text after node.
+func (e *FileEditor) InsertAfter(node ast.Node, text string) error {
+	return e.add(node.End(), node.End(), text)
+}
+
+// Delete deletes node with its doc comment and line comment.
+// lines that become empty are removed.
+func (e *FileEditor) Delete(node ast.Node) error {
+	pos, end := node.Pos(), node.End()
+	doc, comment := nodeComments(node)
+	if doc != nil && doc.Pos() < pos {
+		pos = doc.Pos()
+	}
+	if comment != nil && comment.End() > end {
+		end = comment.End()
+	}
+	start, err := e.offset(pos)
+	if err != nil {
+		return err
+	}
+	stop, err := e.offset(end)
+	if err != nil {
+		return err
+	}
+
+	lineStart := start
+	for lineStart > 0 && (e.src[lineStart-1] == ' ' || e.src[lineStart-1] == '\t') {
+		lineStart--
+	}
+	lineEnd := stop
+	for lineEnd < len(e.src) && (e.src[lineEnd] == ' ' || e.src[lineEnd] == '\t') {
+		lineEnd++
+	}
+	if (lineStart == 0 || e.src[lineStart-1] == '\n') && (lineEnd == len(e.src) || e.src[lineEnd] == '\n') {
+		start = lineStart
+		stop = lineEnd
+		if stop < len(e.src) {
+			stop++
+		}
+	}
+	e.edits = append(e.edits, &sourceEdit{start: start, end: stop})
+	return nil
+}
+
+// DeleteField deletes field of struct with its comments.
+func (e *FileEditor) DeleteField(f *FieldInfo) error {
+	return e.Delete((*ast.Field)(f))
+}
+
+// SetFieldTag sets struct tag of field, tag is value without quotes. e.g. `json:"id"`
+// empty tag removes struct tag.
+func (e *FileEditor) SetFieldTag(f *FieldInfo, tag string) error {
+	lit := ""
+	if tag != "" {
+		lit = "`" + tag + "`"
+		if !strconv.CanBackquote(tag) {
+			lit = strconv.Quote(tag)
+		}
+	}
+	switch {
+	case f.Tag != nil && lit == "":
+		return e.add(f.Type.End(), f.Tag.End(), "")
+	case f.Tag != nil:
+		return e.Replace(f.Tag, lit)
+	case lit != "":
+		return e.add(f.Type.End(), f.Type.End(), " "+lit)
+	}
+	return nil
+}
+
+// Bytes returns source that edits are applied to, it is formatted by gofmt.
+// returns error if edits overlap, or edited source has syntax errors.
+func (e *FileEditor) Bytes() ([]byte, error) {
+	edits := make([]*sourceEdit, len(e.edits))
+	copy(edits, e.edits)
+	sort.SliceStable(edits, func(i, j int) bool {
+		if edits[i].start != edits[j].start {
+			return edits[i].start < edits[j].start
+		}
+		// insertion at start of replaced range is applied before replacement.
+		return edits[i].start == edits[i].end && edits[j].start != edits[j].end
+	})
+
+	var buf bytes.Buffer
+	last := 0
+	for _, edit := range edits {
+		if edit.start < last {
+			return nil, fmt.Errorf("edits overlap at %s", e.file.Position(e.file.Pos(edit.start)))
+		}
+		buf.Write(e.src[last:edit.start])
+		buf.WriteString(edit.text)
+		last = edit.end
+	}
+	buf.Write(e.src[last:])
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return buf.Bytes(), fmt.Errorf("formatting edited %s: %s", e.file.Name(), err)
+	}
+	return src, nil
+}
+
+// nodeComments returns doc comment and line comment of node, they are nil if node doesn't have them.
+func nodeComments(node ast.Node) (doc, comment *ast.CommentGroup) {
+	switch n := node.(type) {
+	case *ast.Field:
+		return n.Doc, n.Comment
+	case *ast.GenDecl:
+		return n.Doc, nil
+	case *ast.FuncDecl:
+		return n.Doc, nil
+	case *ast.TypeSpec:
+		return n.Doc, n.Comment
+	case *ast.ValueSpec:
+		return n.Doc, n.Comment
+	case *ast.ImportSpec:
+		return n.Doc, n.Comment
+	}
+	return nil, nil
+}
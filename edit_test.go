@@ -0,0 +1,83 @@
+package genbase
+
+import (
+	"go/ast"
+	"testing"
+)
+
+func TestFileEditor(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("model.go", `package sample
+
+// Sample is model.
+//
+// +test
+type Sample struct {
+	// ID is key.
+	ID   int64 // line comment of ID
+
+	// Old is deprecated.
+	Old string `+"`json:\"old\"`"+` // remove me
+
+	/* Name is name */
+	Name string   `+"`json:\"name\"`"+`
+	Count int32 // spaces are kept by gofmt
+}
+
+// Other is kept as is.
+type Other int
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	typeInfos := pInfo.CollectTaggedTypeInfos("+test")
+	st, err := typeInfos[0].StructType()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := st.FieldInfos()
+
+	e, err := pInfo.NewFileEditor(typeInfos[0].FileInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.SetFieldTag(fields[0], `json:"id"`); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.DeleteField(fields[1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.SetFieldTag(fields[2], ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.ReplaceNode(fields[3].Type, &ast.Ident{Name: "int64"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.InsertAfter(typeInfos[0].GenDecl, "\n\n// Key returns ID.\nfunc (s *Sample) Key() int64 { return s.ID }"); err != nil {
+		t.Fatal(err)
+	}
+	src, err := e.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := "package sample\n\n// Sample is model.\n//\n// +test\ntype Sample struct {\n" +
+		"\t// ID is key.\n\tID int64 `json:\"id\"` // line comment of ID\n\n" +
+		"\t/* Name is name */\n\tName  string\n\tCount int64 // spaces are kept by gofmt\n}\n\n" +
+		"// Key returns ID.\nfunc (s *Sample) Key() int64 { return s.ID }\n\n" +
+		"// Other is kept as is.\ntype Other int\n"
+	if string(src) != expect {
+		t.Errorf("unexpected: %s", src)
+	}
+
+	if err := e.Replace(fields[1].Type, "[]byte"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Bytes(); err == nil {
+		t.Errorf("unexpected: %v", err)
+	}
+
+	other := &PackageInfo{}
+	if _, err := other.NewFileEditor(typeInfos[0].FileInfo); err != ErrNoSource {
+		t.Errorf("unexpected: %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+package genbase
+
+import (
+	"go/ast"
+)
+
+// FlattenedField is field of struct or field promoted from embedded structs.
+type FlattenedField struct {
+	*FieldInfo
+	Owner *TypeInfo    // type that declares field
+	Name  string       // name of field
+	Via   []*FieldInfo // embedded fields from root type to Owner, empty for field of root type
+}
+
+// flattenedCandidate is field found by walking embedded structs, depth is len(Via).
+type flattenedCandidate struct {
+	field    *FlattenedField
+	expanded bool // embedded struct that is expanded to its fields
+}
+
+// FlattenedFields returns fields of struct type, and fields promoted from embedded struct types in order of declaration.
+// embedded structs are replaced by their fields, and embedded types that are not found or not structs are kept as field.
+// promotion follows Go spec, shallower field hides deeper ones and fields of same name at same depth hide each other.
+// embedded types in other packages are searched from parsed packages.
+func (t *TypeInfo) FlattenedFields(parsed ...*PackageInfo) ([]*FlattenedField, error) {
+	if _, err := t.StructType(); err != nil {
+		return nil, err
+	}
+	var candidates []*flattenedCandidate
+	visiting := map[*ast.TypeSpec]bool{t.TypeSpec: true}
+	candidates = t.flattenedCandidates(candidates, nil, visiting, parsed)
+
+	depths := make(map[string]int)
+	counts := make(map[string]int)
+	for _, c := range candidates {
+		depth := len(c.field.Via)
+		if d, ok := depths[c.field.Name]; !ok || depth < d {
+			depths[c.field.Name] = depth
+			counts[c.field.Name] = 1
+		} else if depth == d {
+			counts[c.field.Name]++
+		}
+	}
+	var fields []*FlattenedField
+	for _, c := range candidates {
+		name := c.field.Name
+		if c.expanded || depths[name] != len(c.field.Via) || counts[name] != 1 {
+			continue
+		}
+		fields = append(fields, c.field)
+	}
+	return fields, nil
+}
+
+// flattenedCandidates appends fields of t and of structs embedded in t to candidates.
+func (t *TypeInfo) flattenedCandidates(candidates []*flattenedCandidate, via []*FieldInfo, visiting map[*ast.TypeSpec]bool, parsed []*PackageInfo) []*flattenedCandidate {
+	st, err := t.StructType()
+	if err != nil {
+		return candidates
+	}
+	for _, f := range st.FieldInfos() {
+		if len(f.Names) != 0 {
+			for _, ident := range f.Names {
+				candidates = append(candidates, &flattenedCandidate{
+					field: &FlattenedField{FieldInfo: f, Owner: t, Name: ident.Name, Via: via},
+				})
+			}
+			continue
+		}
+
+		c := &flattenedCandidate{field: &FlattenedField{FieldInfo: f, Owner: t, Name: f.Name(), Via: via}}
+		candidates = append(candidates, c)
+		embedded := f.ResolveTypeInfo(t.PackageInfo, parsed...)
+		if embedded == nil || visiting[embedded.TypeSpec] {
+			continue
+		}
+		if _, err := embedded.StructType(); err != nil {
+			continue
+		}
+		c.expanded = true
+		visiting[embedded.TypeSpec] = true
+		nested := make([]*FieldInfo, len(via), len(via)+1)
+		copy(nested, via)
+		candidates = embedded.flattenedCandidates(candidates, append(nested, f), visiting, parsed)
+		delete(visiting, embedded.TypeSpec)
+	}
+	return candidates
+}
+
+// Annotation returns annotation of tag in doc or line comment of field.
+// if field doesn't have it, it is inherited from embedded fields in Via, nearest first.
+// e.g. "// +jwg: omitempty" on embedded field applies to its promoted fields.
+func (ff *FlattenedField) Annotation(tag string) (*AnnotationInfo, error) {
+	fields := []*FieldInfo{ff.FieldInfo}
+	for i := len(ff.Via) - 1; i >= 0; i-- {
+		fields = append(fields, ff.Via[i])
+	}
+	for _, f := range fields {
+		for _, doc := range []*ast.CommentGroup{f.Doc, f.Comment} {
+			info, err := parseAnnotation(doc, tag)
+			if err != nil {
+				return nil, err
+			}
+			if info != nil {
+				info.Position = ff.Owner.PackageInfo.position(info.Pos)
+				return info, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// IsSkipped returns true if field or one of embedded fields in Via is skipped, see FieldInfo.IsSkipped.
+func (ff *FlattenedField) IsSkipped(tag string) bool {
+	if ff.FieldInfo.IsSkipped(tag) {
+		return true
+	}
+	for _, f := range ff.Via {
+		if f.IsSkipped(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// InheritedAnnotation returns annotation of tag on type, or on nearest embedded struct type if type is not annotated.
+// so wrapper type that embeds annotated type doesn't need to repeat annotation.
+// embedded types are searched in breadth first order, and in order of declaration at same depth.
+func (t *TypeInfo) InheritedAnnotation(tag string, parsed ...*PackageInfo) (*AnnotationInfo, error) {
+	visited := map[*ast.TypeSpec]bool{t.TypeSpec: true}
+	queue := TypeInfos{t}
+	for len(queue) != 0 {
+		current := queue[0]
+		queue = queue[1:]
+		info, err := current.Annotation(tag)
+		if err != nil || info != nil {
+			return info, err
+		}
+		st, err := current.StructType()
+		if err != nil {
+			continue
+		}
+		for _, f := range st.FieldInfos() {
+			if len(f.Names) != 0 {
+				continue
+			}
+			embedded := f.ResolveTypeInfo(current.PackageInfo, parsed...)
+			if embedded != nil && !visited[embedded.TypeSpec] {
+				visited[embedded.TypeSpec] = true
+				queue = append(queue, embedded)
+			}
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,139 @@
+package genbase
+
+import (
+	"testing"
+)
+
+func TestTypeInfoFlattenedFields(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("model.go", `
+package sample
+
+import "time"
+
+// Base is embedded.
+// +jwg: omitempty
+type Base struct {
+	ID        int64
+	CreatedAt time.Time
+	Name      string
+}
+
+type Audit struct {
+	Name    string
+	Comment string
+	*Base
+}
+
+type Tree struct {
+	*Tree
+	Value int
+}
+
+type Sample struct {
+	// +test: inline
+	Base
+	Audit
+	// +gen:-
+	Secret
+	time.Duration
+	Title, Body string // +test: name=text
+}
+
+type Secret struct {
+	Token string
+}
+
+// Wrapper embeds annotated type.
+type Wrapper struct {
+	Sample
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sample, err := pInfo.FindTypeInfo("Sample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields, err := sample.FlattenedFields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// ambiguous Name of Base and Audit is hidden, and Base of Audit is deeper than Base of Sample.
+	expects := []struct {
+		name  string
+		owner string
+		via   int
+	}{
+		{"ID", "Base", 1},
+		{"CreatedAt", "Base", 1},
+		{"Comment", "Audit", 1},
+		{"Token", "Secret", 1},
+		{"Duration", "Sample", 0},
+		{"Title", "Sample", 0},
+		{"Body", "Sample", 0},
+	}
+	if len(fields) != len(expects) {
+		for _, f := range fields {
+			t.Logf("%s %s", f.Name, f.Owner.Name())
+		}
+		t.Fatalf("unexpected: %d", len(fields))
+	}
+	for i, expect := range expects {
+		f := fields[i]
+		if f.Name != expect.name || f.Owner.Name() != expect.owner || len(f.Via) != expect.via {
+			t.Errorf("unexpected: %d %s %s %d", i, f.Name, f.Owner.Name(), len(f.Via))
+		}
+	}
+
+	info, err := fields[0].Annotation("+test")
+	if err != nil || info == nil || len(info.Args) != 1 || info.Args[0].Key != "inline" {
+		t.Errorf("unexpected: %v %v", info, err)
+	}
+	if info, err := fields[2].Annotation("+test"); err != nil || info != nil {
+		t.Errorf("unexpected: %v %v", info, err)
+	}
+	if info, err := fields[6].Annotation("+test"); err != nil || info == nil || info.Args[0].Value != "text" {
+		t.Errorf("unexpected: %v %v", info, err)
+	}
+	if !fields[3].IsSkipped("+test") || fields[2].IsSkipped("+test") {
+		t.Errorf("unexpected: %v %v", fields[3].IsSkipped("+test"), fields[2].IsSkipped("+test"))
+	}
+
+	tree, err := pInfo.FindTypeInfo("Tree")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields, err = tree.FlattenedFields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fields) != 2 || fields[0].Name != "Tree" || fields[1].Name != "Value" {
+		t.Errorf("unexpected: %v", fields)
+	}
+
+	wrapper, err := pInfo.FindTypeInfo("Wrapper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err = wrapper.InheritedAnnotation("+jwg")
+	if err != nil || info == nil || info.Args[0].Key != "omitempty" || info.Position.Line != 7 {
+		t.Errorf("unexpected: %v %v", info, err)
+	}
+	if info, err := wrapper.InheritedAnnotation("+unknown"); err != nil || info != nil {
+		t.Errorf("unexpected: %v %v", info, err)
+	}
+
+	base, err := pInfo.FindTypeInfo("Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields, err := base.FlattenedFields(); err != nil || len(fields) != 3 {
+		t.Errorf("unexpected: %v %v", fields, err)
+	}
+	if info, err := base.InheritedAnnotation("+jwg"); err != nil || info == nil {
+		t.Errorf("unexpected: %v %v", info, err)
+	}
+}
@@ -0,0 +1,231 @@
+// Package equalgen generates Equal methods for structs parsed by genbase.
+//
+// it is small reference generator that shows how Model, Generator and import management fit together.
+// fields that have `equal:"-"` or `gen:"-"` tag are not compared.
+// types that have "//genbase:ignore equal" directive are not generated.
+package equalgen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"github.com/favclip/genbase"
+)
+
+// Tag is default annotation of target types.
+const Tag = "+equal"
+
+// Name is name of generator, used by skip tags and suppress directives.
+const Name = "equal"
+
+// ErrTypesNotResolved is returned when package is parsed without type information.
+var ErrTypesNotResolved = errors.New("types are not resolved")
+
+// Generate generates Equal methods of struct types that are annotated by tag.
+// returned source is formatted by gofmt.
+func Generate(pkg *genbase.PackageInfo, tag string) ([]byte, error) {
+	if pkg.Types == nil || pkg.TypesInfo == nil {
+		return nil, ErrTypesNotResolved
+	}
+	typeInfos, err := pkg.FindTaggedTypeInfos(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	g := genbase.NewGenerator(pkg)
+	body := &emitter{
+		pkg:       pkg,
+		qualifier: g.Qualifier(),
+		targets:   make(map[*types.TypeName]bool),
+	}
+
+	var structs genbase.TypeInfos
+	for _, t := range typeInfos {
+		if genbase.NewType(t).Kind != genbase.KindStruct || t.IsSuppressedFor(Name) {
+			continue
+		}
+		if obj := t.TypeObject(); obj != nil {
+			body.targets[obj] = true
+		}
+		structs = append(structs, t)
+	}
+	for _, t := range structs {
+		if err := body.emitType(t); err != nil {
+			return nil, err
+		}
+	}
+
+	g.PrintHeader("equalgen", &[]string{tag})
+	g.Buf.Write(body.buf.Bytes())
+	return g.Format()
+}
+
+// emitter accumulates Equal methods.
+// methods are written to own buffer because imports are determined while methods are emitted.
+type emitter struct {
+	pkg       *genbase.PackageInfo
+	qualifier func(pkgPath string) string
+	targets   map[*types.TypeName]bool
+	buf       bytes.Buffer
+}
+
+func (e *emitter) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&e.buf, format, args...)
+}
+
+func (e *emitter) emitType(t *genbase.TypeInfo) error {
+	st, err := t.StructType()
+	if err != nil {
+		return err
+	}
+
+	name := t.Name()
+	e.printf("// Equal returns true if all fields of a and b are equal.\n")
+	e.printf("func (a *%[1]s) Equal(b *%[1]s) bool {\n", name)
+	e.printf("if a == nil || b == nil {\nreturn a == b\n}\n")
+	for _, f := range st.FieldInfos() {
+		if f.IsSkipped(Name) || f.IsSuppressedFor(Name) {
+			continue
+		}
+		typ := e.pkg.TypeOf(f.Type)
+		if typ == nil {
+			return fmt.Errorf("%s: type of field is not resolved", e.pkg.FileSet.Position((*ast.Field)(f).Pos()))
+		}
+		for _, fieldName := range fieldNames(f) {
+			if fieldName == "_" {
+				continue
+			}
+			e.compare(typ, "a."+fieldName, "b."+fieldName, 0)
+		}
+	}
+	e.printf("return true\n}\n\n")
+	return nil
+}
+
+// compare emits statements that return false when x and y are not equal.
+// x and y must be addressable expressions.
+func (e *emitter) compare(typ types.Type, x, y string, depth int) {
+	if named, ok := typ.(*types.Named); ok && e.targets[named.Obj()] {
+		e.printf("if !(&%s).Equal(&%s) {\nreturn false\n}\n", x, y)
+		return
+	}
+	if hasEqualMethod(typ) {
+		e.printf("if !%s.Equal(%s) {\nreturn false\n}\n", x, y)
+		return
+	}
+
+	switch u := typ.Underlying().(type) {
+	case *types.Pointer:
+		if named, ok := u.Elem().(*types.Named); ok && e.targets[named.Obj()] {
+			e.printf("if !%s.Equal(%s) {\nreturn false\n}\n", x, y)
+			return
+		}
+		e.printf("if (%s == nil) != (%s == nil) {\nreturn false\n}\n", x, y)
+		e.printf("if %s != nil {\n", x)
+		e.compare(u.Elem(), "(*"+x+")", "(*"+y+")", depth)
+		e.printf("}\n")
+		return
+	case *types.Slice:
+		if basic, ok := u.Elem().(*types.Basic); ok && basic.Kind() == types.Byte {
+			e.printf("if !%s.Equal(%s, %s) {\nreturn false\n}\n", e.qualifier("bytes"), x, y)
+			return
+		}
+		i := fmt.Sprintf("i%d", depth)
+		e.printf("if len(%s) != len(%s) {\nreturn false\n}\n", x, y)
+		e.printf("for %s := range %s {\n", i, x)
+		e.compare(u.Elem(), x+"["+i+"]", y+"["+i+"]", depth+1)
+		e.printf("}\n")
+		return
+	case *types.Array:
+		if !e.isPlainComparable(u) {
+			i := fmt.Sprintf("i%d", depth)
+			e.printf("for %s := range %s {\n", i, x)
+			e.compare(u.Elem(), x+"["+i+"]", y+"["+i+"]", depth+1)
+			e.printf("}\n")
+			return
+		}
+	case *types.Map:
+		k, v, w, ok := fmt.Sprintf("k%d", depth), fmt.Sprintf("v%d", depth), fmt.Sprintf("w%d", depth), fmt.Sprintf("ok%d", depth)
+		e.printf("if len(%s) != len(%s) {\nreturn false\n}\n", x, y)
+		e.printf("for %s, %s := range %s {\n", k, v, x)
+		e.printf("%s, %s := %s[%s]\n", w, ok, y, k)
+		e.printf("if !%s {\nreturn false\n}\n", ok)
+		e.compare(u.Elem(), v, w, depth+1)
+		e.printf("}\n")
+		return
+	}
+
+	if e.isPlainComparable(typ) {
+		e.printf("if %s != %s {\nreturn false\n}\n", x, y)
+		return
+	}
+	e.printf("if !%s.DeepEqual(%s, %s) {\nreturn false\n}\n", e.qualifier("reflect"), x, y)
+}
+
+// isPlainComparable returns true if == operator compares all parts of typ by value.
+// interfaces are excluded because == panics on incomparable dynamic values.
+func (e *emitter) isPlainComparable(typ types.Type) bool {
+	if !types.Comparable(typ) {
+		return false
+	}
+	if named, ok := typ.(*types.Named); ok && e.targets[named.Obj()] || hasEqualMethod(typ) {
+		return false
+	}
+	switch u := typ.Underlying().(type) {
+	case *types.Interface:
+		return false
+	case *types.Array:
+		return e.isPlainComparable(u.Elem())
+	case *types.Struct:
+		for i := 0; i < u.NumFields(); i++ {
+			if !e.isPlainComparable(u.Field(i).Type()) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// hasEqualMethod returns true if typ has "Equal(typ) bool" method. e.g. time.Time
+func hasEqualMethod(typ types.Type) bool {
+	if _, ok := typ.Underlying().(*types.Interface); ok {
+		return false
+	}
+	obj, _, _ := types.LookupFieldOrMethod(typ, false, nil, "Equal")
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+	sig := fn.Type().(*types.Signature)
+	if sig.Params().Len() != 1 || sig.Results().Len() != 1 {
+		return false
+	}
+	result, ok := sig.Results().At(0).Type().(*types.Basic)
+	return ok && result.Kind() == types.Bool && types.Identical(sig.Params().At(0).Type(), typ)
+}
+
+// fieldNames returns names of field, name of embedded field is derived from its type.
+func fieldNames(f *genbase.FieldInfo) []string {
+	if len(f.Names) != 0 {
+		names := make([]string, 0, len(f.Names))
+		for _, ident := range f.Names {
+			names = append(names, ident.Name)
+		}
+		return names
+	}
+
+	expr := f.Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return []string{expr.Name}
+	case *ast.SelectorExpr:
+		return []string{expr.Sel.Name}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package equalgen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/favclip/genbase"
+)
+
+func TestGenerate(t *testing.T) {
+	p := &genbase.Parser{}
+	pInfo, err := p.ParseStringSource("main.go", "package sample\n"+
+		"import \"time\"\n"+
+		"// +equal\n"+
+		"type User struct {\n"+
+		"	Base\n"+
+		"	Name      string\n"+
+		"	Nick      *string\n"+
+		"	Raw       []byte\n"+
+		"	Scores    map[string][]int\n"+
+		"	Parent    *User\n"+
+		"	Children  [2]Base\n"+
+		"	Extra     interface{}\n"+
+		"	CreatedAt time.Time\n"+
+		"	Cache     string `equal:\"-\"`\n"+
+		"	Callback  func()\n"+
+		"}\n"+
+		"// +equal\n"+
+		"type Base struct {\n"+
+		"	ID, Rev int\n"+
+		"}\n"+
+		"// +equal\n"+
+		"//genbase:ignore equal\n"+
+		"type Ignored struct {\n"+
+		"	ID int\n"+
+		"}\n"+
+		"// +equal\n"+
+		"type IDs []int\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := Generate(pInfo, Tag)
+	if err != nil {
+		t.Fatalf("unexpected: %s\n%s", err, string(src))
+	}
+	if _, err := pInfo.CheckGenerated("sample_equal.go", src); err != nil {
+		t.Fatalf("unexpected: %s\n%s", err, string(src))
+	}
+
+	output := string(src)
+	for _, expected := range []string{
+		"// Code generated by equalgen +equal; DO NOT EDIT",
+		"\"bytes\"",
+		"\"reflect\"",
+		"func (a *User) Equal(b *User) bool {",
+		"func (a *Base) Equal(b *Base) bool {",
+		"if !(&a.Base).Equal(&b.Base) {",
+		"if (a.Nick == nil) != (b.Nick == nil) {",
+		"if (*a.Nick) != (*b.Nick) {",
+		"if !bytes.Equal(a.Raw, b.Raw) {",
+		"w0, ok0 := b.Scores[k0]",
+		"if len(v0) != len(w0) {",
+		"if v0[i1] != w0[i1] {",
+		"if !a.Parent.Equal(b.Parent) {",
+		"if !(&a.Children[i0]).Equal(&b.Children[i0]) {",
+		"if !reflect.DeepEqual(a.Extra, b.Extra) {",
+		"if !a.CreatedAt.Equal(b.CreatedAt) {",
+		"if !reflect.DeepEqual(a.Callback, b.Callback) {",
+		"if a.Rev != b.Rev {",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("unexpected: %s is not contained\n%s", expected, output)
+		}
+	}
+	for _, unexpected := range []string{"Cache", "Ignored", "IDs"} {
+		if strings.Contains(output, unexpected) {
+			t.Errorf("unexpected: %s is contained\n%s", unexpected, output)
+		}
+	}
+}
+
+func TestGenerateWithoutTypes(t *testing.T) {
+	p := &genbase.Parser{SkipSemanticsCheck: true}
+	pInfo, err := p.ParseStringSource("main.go", "package sample\n"+
+		"// +equal\n"+
+		"type User struct {\n"+
+		"	Name Unknown\n"+
+		"}\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Generate(pInfo, Tag); err != ErrTypesNotResolved {
+		t.Errorf("unexpected: %v", err)
+	}
+}
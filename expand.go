@@ -0,0 +1,142 @@
+package genbase
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// ExpandContext is data of placeholders in annotation values. e.g. {{.Type.Name}}
+type ExpandContext struct {
+	Type    ExpandType
+	Package ExpandPackage
+}
+
+// ExpandType is type of ExpandContext.
+type ExpandType struct {
+	Name string
+}
+
+// ExpandPackage is package of ExpandContext.
+type ExpandPackage struct {
+	Name       string
+	ImportPath string
+	Dir        string
+}
+
+// expandFuncs are functions of placeholders. e.g. {{snake .Type.Name}}
+var expandFuncs = template.FuncMap{
+	"camel":      CamelCase,
+	"lowerCamel": LowerCamelCase,
+	"snake":      SnakeCase,
+	"kebab":      KebabCase,
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"plural":     Pluralize,
+}
+
+// ExpandValue expands environment variables like ${GEN_OUT_DIR} and placeholders like {{.Type.Name}} in annotation value.
+// only braced form of variables is expanded and "$$" is "$", undefined variable is error.
+// placeholders are text/template actions with ExpandContext, quote value in annotation if it has spaces.
+// e.g. output="{{snake .Type.Name}}_gen.go". functions of placeholders are camel, lowerCamel, snake, kebab,
+// lower, upper and plural. unknown fields are error.
+// placeholders are executed before variables, so values of variables are never parsed as template.
+// lookupEnv is os.LookupEnv if it is nil.
+func ExpandValue(value string, ctx *ExpandContext, lookupEnv func(key string) (string, bool)) (string, error) {
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+	executed := value
+	if strings.Contains(value, "{{") {
+		tmpl, err := template.New("value").Funcs(expandFuncs).Option("missingkey=error").Parse(value)
+		if err != nil {
+			return "", fmt.Errorf("expanding %q: %s", value, err)
+		}
+		if ctx == nil {
+			ctx = &ExpandContext{}
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, ctx.escaped()); err != nil {
+			return "", fmt.Errorf("expanding %q: %s", value, err)
+		}
+		executed = buf.String()
+	}
+	expanded, err := expandEnv(executed, lookupEnv)
+	if err != nil {
+		return "", fmt.Errorf("expanding %q: %s", value, err)
+	}
+	return expanded, nil
+}
+
+// escaped returns copy of ctx that "$" in values are "$$", they are not expanded as variables.
+func (ctx *ExpandContext) escaped() *ExpandContext {
+	escape := func(s string) string {
+		return strings.ReplaceAll(s, "$", "$$")
+	}
+	return &ExpandContext{
+		Type: ExpandType{Name: escape(ctx.Type.Name)},
+		Package: ExpandPackage{
+			Name:       escape(ctx.Package.Name),
+			ImportPath: escape(ctx.Package.ImportPath),
+			Dir:        escape(ctx.Package.Dir),
+		},
+	}
+}
+
+// expandEnv expands ${NAME} in value by lookupEnv.
+func expandEnv(value string, lookupEnv func(key string) (string, bool)) (string, error) {
+	if !strings.Contains(value, "$") {
+		return value, nil
+	}
+	var buf strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '$' || i+1 == len(value) {
+			buf.WriteByte(value[i])
+			continue
+		}
+		switch value[i+1] {
+		case '$':
+			buf.WriteByte('$')
+			i++
+		case '{':
+			end := strings.IndexByte(value[i:], '}')
+			if end == -1 {
+				return "", errors.New("unterminated variable")
+			}
+			name := value[i+2 : i+end]
+			if name == "" {
+				return "", errors.New("empty variable name")
+			}
+			v, ok := lookupEnv(name)
+			if !ok {
+				return "", fmt.Errorf("environment variable %s is not defined", name)
+			}
+			buf.WriteString(v)
+			i += end
+		default:
+			buf.WriteByte('$')
+		}
+	}
+	return buf.String(), nil
+}
+
+// ExpandContext returns ExpandContext of type.
+func (t *TypeInfo) ExpandContext() *ExpandContext {
+	return &ExpandContext{Type: ExpandType{Name: t.Name()}, Package: t.PackageInfo.expandPackage()}
+}
+
+// expandPackage returns ExpandPackage of package, it is empty if package is unknown.
+func (pkg *PackageInfo) expandPackage() ExpandPackage {
+	if pkg == nil || len(pkg.Files) == 0 {
+		return ExpandPackage{}
+	}
+	return ExpandPackage{Name: pkg.Name(), ImportPath: pkg.ImportPath(), Dir: pkg.Dir}
+}
+
+// ExpandValue expands value of annotation on type by ExpandValue with environment variables of process.
+func (t *TypeInfo) ExpandValue(value string) (string, error) {
+	return ExpandValue(value, t.ExpandContext(), nil)
+}
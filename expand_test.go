@@ -0,0 +1,82 @@
+package genbase
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestExpandValue(t *testing.T) {
+	env := map[string]string{"GEN_OUT_DIR": "out", "EMPTY": "", "INJECT": "{{.Type.Name}}"}
+	lookupEnv := func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+	ctx := &ExpandContext{Type: ExpandType{Name: "UserItem"}, Package: ExpandPackage{Name: "model", Dir: "/src/${HOME}"}}
+
+	for value, expected := range map[string]string{
+		"foo.go":                              "foo.go",
+		"${GEN_OUT_DIR}/foo_gen.go":           "out/foo_gen.go",
+		"${EMPTY}a":                           "a",
+		"$$HOME $5":                           "$HOME $5",
+		"{{snake .Type.Name}}_gen.go":         "user_item_gen.go",
+		"${GEN_OUT_DIR}/{{.Package.Name}}.go": "out/model.go",
+		"{{plural (lowerCamel .Type.Name)}}":  "userItems",
+		"${INJECT}_gen.go":                    "{{.Type.Name}}_gen.go",
+		"{{.Package.Dir}}":                    "/src/${HOME}",
+	} {
+		v, err := ExpandValue(value, ctx, lookupEnv)
+		if err != nil {
+			t.Errorf("unexpected: %s %s", value, err)
+		} else if v != expected {
+			t.Errorf("unexpected: %s %s", value, v)
+		}
+	}
+
+	for value, reason := range map[string]string{
+		"${UNDEFINED}":      "UNDEFINED is not defined",
+		"${GEN_OUT_DIR":     "unterminated variable",
+		"${}":               "empty variable name",
+		"{{.Type.Unknown}}": "Unknown",
+		"{{.Type.Name":      "unclosed action",
+	} {
+		if _, err := ExpandValue(value, ctx, lookupEnv); err == nil || !strings.Contains(err.Error(), reason) {
+			t.Errorf("unexpected: %s %v", value, err)
+		}
+	}
+}
+
+func TestTypeInfoUnmarshalAnnotationExpand(t *testing.T) {
+	os.Setenv("GENBASE_TEST_OUT", "gen")
+	defer os.Unsetenv("GENBASE_TEST_OUT")
+
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `package sample
+
+// +test: output="${GENBASE_TEST_OUT}/{{snake .Type.Name}}.go", raw=${GENBASE_TEST_OUT}
+type UserItem struct{}
+
+// +test: output=${UNDEFINED_GENBASE_TEST}
+type Broken struct{}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type options struct {
+		Output string `gen:"output,expand"`
+		Name   string `gen:"name,expand" gen-default:"{{.Package.Name}}.{{.Type.Name}}"`
+		Raw    string
+	}
+	var opts options
+	if err := pInfo.CollectTypeInfos([]string{"UserItem"})[0].UnmarshalAnnotation("+test", &opts); err != nil {
+		t.Fatal(err)
+	}
+	if opts.Output != "gen/user_item.go" || opts.Name != "sample.UserItem" || opts.Raw != "${GENBASE_TEST_OUT}" {
+		t.Errorf("unexpected: %+v", opts)
+	}
+
+	err = pInfo.CollectTypeInfos([]string{"Broken"})[0].UnmarshalAnnotation("+test", &opts)
+	if aErr, ok := err.(*AnnotationError); !ok || aErr.Key != "output" || aErr.Position.Line != 6 {
+		t.Errorf("unexpected: %v", err)
+	}
+}
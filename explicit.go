@@ -0,0 +1,101 @@
+package genbase
+
+import (
+	"bufio"
+	"fmt"
+	"go/importer"
+	"go/token"
+	"io"
+	"os"
+	"strings"
+)
+
+// ExplicitInput is inputs of package for hermetic build systems like Bazel.
+// nothing is discovered from file system, GOPATH or go.mod.
+type ExplicitInput struct {
+	ImportPath string   // import path of package, optional
+	Files      []string // all source files of package, build constraints are not evaluated
+	ImportCfg  string   // importcfg file that has packagefile and importmap lines, optional
+	// Exports maps import path to export data file, it is merged with ImportCfg.
+	Exports map[string]string
+	// ImportMap maps import path in source to actual import path, it is merged with ImportCfg.
+	ImportMap map[string]string
+}
+
+// ParseExplicitInput parses package that consists of explicit files,
+// and resolves imports only from export data in ImportCfg or Exports.
+// GoVersion of Parser is used as language version.
+func (p *Parser) ParseExplicitInput(in *ExplicitInput) (*PackageInfo, error) {
+	exports := make(map[string]string)
+	importMap := make(map[string]string)
+	if in.ImportCfg != "" {
+		f, err := os.Open(in.ImportCfg)
+		if err != nil {
+			return nil, fmt.Errorf("reading importcfg: %s", err)
+		}
+		defer f.Close()
+		if err := ParseImportCfg(f, exports, importMap); err != nil {
+			return nil, fmt.Errorf("reading importcfg %s: %s", in.ImportCfg, err)
+		}
+	}
+	for path, file := range in.Exports {
+		exports[path] = file
+	}
+	for path, actual := range in.ImportMap {
+		importMap[path] = actual
+	}
+
+	explicit := *p
+	fs := token.NewFileSet()
+	explicit.Importer = NewStaticExportDataImporter(fs, exports, importMap)
+
+	return explicit.parsePackageWithFileSet(fs, ".", in.ImportPath, in.Files, nil, p.GoVersion)
+}
+
+// ParseImportCfg reads importcfg of go tool compile,
+// "packagefile path=file" lines are stored to exports, and "importmap path=actual" lines are stored to importMap.
+func ParseImportCfg(r io.Reader, exports map[string]string, importMap map[string]string) error {
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		verb, args := line, ""
+		if idx := strings.Index(line, " "); idx != -1 {
+			verb, args = line[:idx], strings.TrimSpace(line[idx+1:])
+		}
+		idx := strings.Index(args, "=")
+		switch {
+		case verb != "packagefile" && verb != "importmap":
+			// other verbs like modinfo are not used by type checking.
+			continue
+		case idx <= 0 || idx == len(args)-1:
+			return fmt.Errorf("line %d: invalid %s: %s", lineNum, verb, args)
+		case verb == "packagefile":
+			exports[args[:idx]] = args[idx+1:]
+		default:
+			importMap[args[:idx]] = args[idx+1:]
+		}
+	}
+	return scanner.Err()
+}
+
+// NewStaticExportDataImporter creates ExportDataImporter that loads export data only from exports.
+// exports maps import path to export data file, importMap maps import path in source to actual import path.
+// it never runs go command.
+func NewStaticExportDataImporter(fs *token.FileSet, exports map[string]string, importMap map[string]string) *ExportDataImporter {
+	imp := &ExportDataImporter{
+		exports:   make(map[string]string),
+		importMap: make(map[string]string),
+		static:    true,
+	}
+	for path, file := range exports {
+		imp.exports[path] = file
+	}
+	for path, actual := range importMap {
+		imp.importMap[path] = actual
+	}
+	imp.imp = importer.ForCompiler(fs, "gc", imp.lookup)
+	return imp
+}
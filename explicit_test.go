@@ -0,0 +1,116 @@
+package genbase
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParserParseExplicitInput(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genbase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	out, err := exec.Command("go", "list", "-export", "-deps", "-f", "{{.ImportPath}}={{.Export}}", "time").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg bytes.Buffer
+	fmt.Fprintln(&cfg, "# import config")
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if !strings.HasSuffix(line, "=") {
+			fmt.Fprintf(&cfg, "packagefile %s\n", line)
+		}
+	}
+	fmt.Fprintln(&cfg, "importmap example.com/clock=time")
+	importCfg := filepath.Join(dir, "importcfg")
+	if err := ioutil.WriteFile(importCfg, cfg.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// build constraints are not evaluated in explicit mode.
+	name := filepath.Join(dir, "sample_plan9.go")
+	if err := ioutil.WriteFile(name, []byte(`package sample
+
+import (
+	clock "example.com/clock"
+)
+
+type Sample struct {
+	At clock.Time
+}
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &Parser{}
+	pInfo, err := p.ParseExplicitInput(&ExplicitInput{
+		ImportPath: "example.com/sample",
+		Files:      []string{name},
+		ImportCfg:  importCfg,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := pInfo.ImportPath(); v != "example.com/sample" {
+		t.Errorf("unexpected: %s", v)
+	}
+	if v := pInfo.Types.Path(); v != "example.com/sample" {
+		t.Errorf("unexpected: %s", v)
+	}
+	obj := pInfo.Types.Scope().Lookup("Sample")
+	if obj == nil {
+		t.Fatal("unexpected: Sample is not resolved")
+	}
+	if v := obj.Type().Underlying().String(); v != "struct{At time.Time}" {
+		t.Errorf("unexpected: %s", v)
+	}
+
+	genPkg, err := pInfo.CheckGenerated("sample_gen.go", []byte(`package sample
+
+import (
+	clock "example.com/clock"
+)
+
+func (s *Sample) SetAt(at clock.Time) {
+	s.At = at
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := genPkg.Path(); v != "example.com/sample" {
+		t.Errorf("unexpected: %s", v)
+	}
+
+	_, err = p.ParseExplicitInput(&ExplicitInput{
+		Files:   []string{name},
+		Exports: map[string]string{},
+	})
+	if err == nil || !strings.Contains(err.Error(), "no export data for example.com/clock") {
+		t.Errorf("unexpected: %v", err)
+	}
+}
+
+func TestParseImportCfg(t *testing.T) {
+	exports := make(map[string]string)
+	importMap := make(map[string]string)
+	err := ParseImportCfg(strings.NewReader("# comment\npackagefile fmt=/cache/fmt.a\nimportmap a=b\nmodinfo \"x\"\n"), exports, importMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exports) != 1 || exports["fmt"] != "/cache/fmt.a" || len(importMap) != 1 || importMap["a"] != "b" {
+		t.Errorf("unexpected: %v %v", exports, importMap)
+	}
+
+	if err := ParseImportCfg(strings.NewReader("packagefile fmt\n"), exports, importMap); err == nil {
+		t.Error("unexpected: err is nil")
+	}
+}
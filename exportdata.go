@@ -0,0 +1,242 @@
+package genbase
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ExportDataImporter is types.Importer that loads compiled export data of dependencies from build cache.
+// export data is located by `go list -export`, so it works with modules and vendoring.
+type ExportDataImporter struct {
+	Dir string // directory to run go command
+	// CacheDir is directory to cache locations of export data across runs. cache is disabled if it is empty.
+	CacheDir string
+
+	mu        sync.Mutex
+	exports   map[string]string // import path to export data file
+	importMap map[string]string // import path in source to actual import path
+	static    bool              // exports are given, go command is not used
+	imp       types.Importer
+	cache     map[string]*exportDataEntry
+	cacheFile string
+}
+
+// exportDataEntry is cached location of export data.
+type exportDataEntry struct {
+	Export  string `json:"export"`
+	BuildID string `json:"buildID"`
+}
+
+// DefaultExportDataCacheDir returns directory in user cache dir to cache export data locations.
+func DefaultExportDataCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "genbase", "exportdata"), nil
+}
+
+// NewExportDataImporter creates new ExportDataImporter.
+func NewExportDataImporter(fs *token.FileSet, directory string) *ExportDataImporter {
+	imp := &ExportDataImporter{
+		Dir:     directory,
+		exports: make(map[string]string),
+	}
+	imp.imp = importer.ForCompiler(fs, "gc", imp.lookup)
+	return imp
+}
+
+// Import imports package of path.
+func (imp *ExportDataImporter) Import(path string) (*types.Package, error) {
+	if path == "unsafe" {
+		return types.Unsafe, nil
+	}
+	if actual, ok := imp.importMap[path]; ok {
+		path = actual
+	}
+	return imp.imp.Import(path)
+}
+
+func (imp *ExportDataImporter) lookup(path string) (io.ReadCloser, error) {
+	imp.mu.Lock()
+	defer imp.mu.Unlock()
+
+	if _, ok := imp.exports[path]; !ok && !imp.static {
+		if err := imp.load(path); err != nil {
+			return nil, err
+		}
+	}
+	exportFile := imp.exports[path]
+	if exportFile == "" {
+		return nil, fmt.Errorf("no export data for %s", path)
+	}
+	return os.Open(exportFile)
+}
+
+// load runs `go list -export` for path and its dependencies.
+// if CacheDir is specified, cached location is used when export data still exists and has cached build ID.
+func (imp *ExportDataImporter) load(path string) error {
+	if imp.CacheDir != "" {
+		imp.loadCache()
+		if entry, ok := imp.cache[path]; ok && entry.BuildID != "" && exportBuildID(entry.Export) == entry.BuildID {
+			imp.exports[path] = entry.Export
+			return nil
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	format := "{{.ImportPath}}\t{{.Export}}\t{{.BuildID}}\t{{.Standard}}\t{{with .Module}}{{if .Replace}}{{.Replace.Version}}{{else}}{{.Version}}{{end}}{{end}}"
+	cmd := exec.Command("go", "list", "-export", "-deps", "-f", format, path)
+	cmd.Dir = imp.Dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("go list -export %s: %s: %s", path, err, strings.TrimSpace(stderr.String()))
+	}
+	updated := false
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		ss := strings.Split(line, "\t")
+		if len(ss) != 5 {
+			continue
+		}
+		imp.exports[ss[0]] = ss[1]
+		// packages in main module or replaced directories can be changed without changing their paths.
+		if imp.cache != nil && ss[1] != "" && (ss[3] == "true" || ss[4] != "") {
+			imp.cache[ss[0]] = &exportDataEntry{Export: ss[1], BuildID: ss[2]}
+			updated = true
+		}
+	}
+	if _, ok := imp.exports[path]; !ok {
+		imp.exports[path] = ""
+	}
+	if updated {
+		imp.saveCache()
+	}
+	return nil
+}
+
+// loadCache loads cache file for environment of Dir once.
+// cache is keyed by go env of Dir, and go.mod and go.sum of module. cache is disabled if go env fails.
+func (imp *ExportDataImporter) loadCache() {
+	if imp.cache != nil {
+		return
+	}
+	imp.cache = make(map[string]*exportDataEntry)
+
+	env, err := goEnvOf(imp.Dir)
+	if err != nil {
+		return
+	}
+	h := sha256.New()
+	h.Write([]byte(env))
+	if dir, err := filepath.Abs(imp.Dir); err == nil {
+		for ; ; dir = filepath.Dir(dir) {
+			if b, err := ioutil.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+				fmt.Fprintf(h, "%s\n%s\n", dir, b)
+				b, _ = ioutil.ReadFile(filepath.Join(dir, "go.sum"))
+				h.Write(b)
+				break
+			}
+			if filepath.Dir(dir) == dir {
+				break
+			}
+		}
+	}
+	imp.cacheFile = filepath.Join(imp.CacheDir, hex.EncodeToString(h.Sum(nil))+".json")
+
+	b, err := ioutil.ReadFile(imp.cacheFile)
+	if err != nil {
+		return
+	}
+	// broken cache is ignored, it is overwritten by next save.
+	_ = json.Unmarshal(b, &imp.cache)
+	if imp.cache == nil {
+		imp.cache = make(map[string]*exportDataEntry)
+	}
+}
+
+// saveCache writes cache file. errors are ignored because cache is optional.
+func (imp *ExportDataImporter) saveCache() {
+	if imp.cacheFile == "" {
+		return
+	}
+	b, err := json.Marshal(imp.cache)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(imp.CacheDir, 0755); err != nil {
+		return
+	}
+	f, err := ioutil.TempFile(imp.CacheDir, "tmp-")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(b)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), imp.cacheFile)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+}
+
+var goEnvs sync.Map // directory to output of go env
+
+// goEnvOf returns go env that affects export data, for toolchain selected in directory.
+// it is memoized per directory, so go command runs once in process.
+func goEnvOf(directory string) (string, error) {
+	if env, ok := goEnvs.Load(directory); ok {
+		return env.(string), nil
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("go", "env", "GOVERSION", "GOOS", "GOARCH", "GOFLAGS", "CGO_ENABLED", "GOEXPERIMENT", "GOAMD64", "GOARM64")
+	cmd.Dir = directory
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("go env: %s: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	env, _ := goEnvs.LoadOrStore(directory, stdout.String())
+	return env.(string), nil
+}
+
+// exportBuildID returns build ID in header of export data file, or empty string if it can't be read.
+func exportBuildID(name string) string {
+	f, err := os.Open(name)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	header := make([]byte, 1024)
+	n, _ := io.ReadFull(f, header)
+	for _, line := range strings.Split(string(header[:n]), "\n") {
+		if strings.HasPrefix(line, "build id ") {
+			id, err := strconv.Unquote(strings.TrimPrefix(line, "build id "))
+			if err != nil {
+				return ""
+			}
+			return id
+		}
+		if line == "$$B" {
+			break
+		}
+	}
+	return ""
+}
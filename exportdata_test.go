@@ -0,0 +1,110 @@
+package genbase
+
+import (
+	"encoding/json"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParserUseExportData(t *testing.T) {
+	p := &Parser{UseExportData: true}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	import (
+		"net/http"
+		"time"
+
+		"golang.org/x/tools/go/analysis"
+	)
+
+	type Sample struct {
+		Client   *http.Client
+		At       time.Time
+		Analyzer *analysis.Analyzer
+	}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj := pInfo.Types.Scope().Lookup("Sample")
+	if obj == nil {
+		t.Fatal("unexpected: Sample is not resolved")
+	}
+	if v := obj.Type().Underlying().String(); v != "struct{Client *net/http.Client; At time.Time; Analyzer *golang.org/x/tools/go/analysis.Analyzer}" {
+		t.Fatalf("unexpected: %s", v)
+	}
+}
+
+func TestExportDataImporterCacheDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genbase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	imp := NewExportDataImporter(token.NewFileSet(), ".")
+	imp.CacheDir = dir
+	if _, err := imp.Import("time"); err != nil {
+		t.Fatal(err)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("unexpected: %v", files)
+	}
+
+	// go list is not run when cache is available, go env is memoized.
+	path := os.Getenv("PATH")
+	os.Setenv("PATH", "")
+	defer os.Setenv("PATH", path)
+
+	imp = NewExportDataImporter(token.NewFileSet(), ".")
+	imp.CacheDir = dir
+	pkg, err := imp.Import("time")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pkg.Scope().Lookup("Time") == nil {
+		t.Error("unexpected: time.Time is not found")
+	}
+
+	imp = NewExportDataImporter(token.NewFileSet(), ".")
+	if _, err := imp.Import("time"); err == nil {
+		t.Error("unexpected: err is nil")
+	}
+	os.Setenv("PATH", path)
+
+	// entry of different build ID is reloaded.
+	b, err := ioutil.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cache map[string]*exportDataEntry
+	if err := json.Unmarshal(b, &cache); err != nil {
+		t.Fatal(err)
+	}
+	buildID := cache["time"].BuildID
+	if buildID == "" || exportBuildID(cache["time"].Export) != buildID {
+		t.Fatalf("unexpected: %#v", cache["time"])
+	}
+	cache["time"].BuildID = "stale"
+	b, _ = json.Marshal(cache)
+	if err := ioutil.WriteFile(files[0], b, 0644); err != nil {
+		t.Fatal(err)
+	}
+	imp = NewExportDataImporter(token.NewFileSet(), ".")
+	imp.CacheDir = dir
+	if _, err := imp.Import("time"); err != nil {
+		t.Fatal(err)
+	}
+	if imp.cache["time"].BuildID != buildID {
+		t.Errorf("unexpected: %#v", imp.cache["time"])
+	}
+}
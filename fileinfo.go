@@ -0,0 +1,82 @@
+package genbase
+
+import (
+	"go/build/constraint"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// PackageName returns name in package clause of file.
+func (file *FileInfo) PackageName() string {
+	if file.Name == nil {
+		return ""
+	}
+	return file.Name.Name
+}
+
+// FileName returns name of file in fs.
+func (file *FileInfo) FileName(fs *token.FileSet) string {
+	if fs == nil {
+		return ""
+	}
+	return fs.Position(file.Package).Filename
+}
+
+// BuildConstraint returns build constraint in header of file, or nil if file has no constraint.
+// "//go:build" line takes precedence over "// +build" lines like go/build.
+func (file *FileInfo) BuildConstraint() (constraint.Expr, error) {
+	var plusBuild constraint.Expr
+	for _, cg := range file.Comments {
+		if cg.Pos() > file.Package {
+			break
+		}
+		for _, c := range cg.List {
+			switch {
+			case constraint.IsGoBuild(c.Text):
+				return constraint.Parse(c.Text)
+			case constraint.IsPlusBuild(c.Text):
+				x, err := constraint.Parse(c.Text)
+				if err != nil {
+					return nil, err
+				}
+				if plusBuild == nil {
+					plusBuild = x
+				} else {
+					plusBuild = &constraint.AndExpr{X: plusBuild, Y: x}
+				}
+			}
+		}
+	}
+	return plusBuild, nil
+}
+
+// IsGenerated returns true if file has "// Code generated ... DO NOT EDIT" comment before package clause, otherwise returns false.
+func (file *FileInfo) IsGenerated() bool {
+	for _, cg := range file.Comments {
+		if cg.Pos() > file.Package {
+			break
+		}
+		for _, c := range cg.List {
+			if strings.HasPrefix(c.Text, "// Code generated ") && strings.Contains(c.Text, "DO NOT EDIT") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsTest returns true if file is test file named "*_test.go", otherwise returns false.
+func (file *FileInfo) IsTest(fs *token.FileSet) bool {
+	return strings.HasSuffix(file.FileName(fs), "_test.go")
+}
+
+// IsCgo returns true if file imports "C", otherwise returns false.
+func (file *FileInfo) IsCgo() bool {
+	for _, imp := range file.Imports {
+		if path, err := strconv.Unquote(imp.Path.Value); err == nil && path == "C" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package genbase
+
+import (
+	"testing"
+)
+
+func TestFileInfoHeader(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSources(map[string]string{
+		"a.go":      "// Code generated by jwg; DO NOT EDIT\n\n//go:build linux && !appengine\n// +build linux,!appengine\n\npackage sample\n",
+		"b.go":      "// +build linux darwin\n// +build amd64\n\n// Package sample is sample.\npackage sample\n\n// #include <stdlib.h>\nimport \"C\"\n\n// Code generated by nothing; DO NOT EDIT\nvar X int\n",
+		"c_test.go": "package sample\n",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]*FileInfo)
+	for _, file := range pInfo.Files {
+		files[file.FileName(pInfo.FileSet)] = file
+	}
+	if len(files) != 3 {
+		t.Fatalf("unexpected: %v", files)
+	}
+
+	a, b, c := files["a.go"], files["b.go"], files["c_test.go"]
+	if a.PackageName() != "sample" || !a.IsGenerated() || a.IsTest(pInfo.FileSet) || a.IsCgo() {
+		t.Errorf("unexpected: %v %v %v", a.IsGenerated(), a.IsTest(pInfo.FileSet), a.IsCgo())
+	}
+	if b.IsGenerated() || b.IsTest(pInfo.FileSet) || !b.IsCgo() {
+		t.Errorf("unexpected: %v %v %v", b.IsGenerated(), b.IsTest(pInfo.FileSet), b.IsCgo())
+	}
+	if c.IsGenerated() || !c.IsTest(pInfo.FileSet) || c.IsCgo() {
+		t.Errorf("unexpected: %v %v %v", c.IsGenerated(), c.IsTest(pInfo.FileSet), c.IsCgo())
+	}
+
+	x, err := a.BuildConstraint()
+	if err != nil || x == nil || x.String() != "linux && !appengine" {
+		t.Errorf("unexpected: %v %v", x, err)
+	}
+	x, err = b.BuildConstraint()
+	if err != nil || x == nil || x.String() != "(linux || darwin) && amd64" {
+		t.Errorf("unexpected: %v %v", x, err)
+	}
+	if x, err := c.BuildConstraint(); err != nil || x != nil {
+		t.Errorf("unexpected: %v %v", x, err)
+	}
+}
@@ -0,0 +1,50 @@
+package genbase
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// SkipFilePattern shows file is excluded by IncludeFiles or ExcludeFiles of Parser.
+const SkipFilePattern SkipReason = "excluded by file pattern"
+
+// filterFiles returns files that match IncludeFiles and don't match ExcludeFiles, and skipped files.
+// patterns are matched with base name of file by filepath.Match.
+func (p *Parser) filterFiles(names []string) ([]string, []*SkippedFile, error) {
+	if len(p.IncludeFiles) == 0 && len(p.ExcludeFiles) == 0 {
+		return names, nil, nil
+	}
+	var kept []string
+	var skipped []*SkippedFile
+	for _, name := range names {
+		base := filepath.Base(name)
+		included, err := matchAnyPattern(p.IncludeFiles, base)
+		if err != nil {
+			return nil, nil, err
+		}
+		excluded, err := matchAnyPattern(p.ExcludeFiles, base)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(p.IncludeFiles) != 0 && !included || excluded {
+			skipped = appendSkippedFiles(skipped, SkipFilePattern, name)
+			continue
+		}
+		kept = append(kept, name)
+	}
+	return kept, skipped, nil
+}
+
+// matchAnyPattern returns true if name matches one of patterns, otherwise returns false.
+func matchAnyPattern(patterns []string, name string) (bool, error) {
+	for _, pattern := range patterns {
+		match, err := filepath.Match(pattern, name)
+		if err != nil {
+			return false, fmt.Errorf("file pattern %q: %s", pattern, err)
+		}
+		if match {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,65 @@
+package genbase
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestParserFilterFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genbase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"model_user.go", "model_user_mock.go", "model_item.go", "handler.go"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte("package sample\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	parsedFiles := func(pInfo *PackageInfo) string {
+		var names []string
+		for _, file := range pInfo.Files {
+			names = append(names, filepath.Base(pInfo.FileSet.File(file.Package).Name()))
+		}
+		sort.Strings(names)
+		return strings.Join(names, ",")
+	}
+
+	p := &Parser{IncludeFiles: []string{"model_*.go"}, ExcludeFiles: []string{"*_mock.go"}}
+	pInfo, err := p.ParsePackageDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := parsedFiles(pInfo); v != "model_item.go,model_user.go" {
+		t.Errorf("unexpected: %s", v)
+	}
+	var skipped []string
+	for _, f := range pInfo.SkippedFiles {
+		if f.Reason == SkipFilePattern {
+			skipped = append(skipped, filepath.Base(f.Name))
+		}
+	}
+	sort.Strings(skipped)
+	if v := strings.Join(skipped, ","); v != "handler.go,model_user_mock.go" {
+		t.Errorf("unexpected: %s", v)
+	}
+
+	p = &Parser{ExcludeFiles: []string{"model_*"}}
+	pInfo, err = p.ParsePackageFiles(pathJoinAll(dir, "model_user.go", "handler.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := parsedFiles(pInfo); v != "handler.go" {
+		t.Errorf("unexpected: %s", v)
+	}
+
+	p = &Parser{ExcludeFiles: []string{"["}}
+	if _, err := p.ParsePackageDir(dir); err == nil || !strings.Contains(err.Error(), `file pattern "["`) {
+		t.Errorf("unexpected: %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+package genbase
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// FuncInfo is function or method declaration in package.
+type FuncInfo struct {
+	PackageInfo *PackageInfo
+	FileInfo    *FileInfo
+	FuncDecl    *ast.FuncDecl
+}
+
+// FuncInfos is []*FuncInfo synonym.
+type FuncInfos []*FuncInfo
+
+// FuncInfos is gathering FuncInfos, it included in package.
+func (pkg *PackageInfo) FuncInfos() FuncInfos {
+	var funcs FuncInfos
+	for _, file := range pkg.Files {
+		if file == nil {
+			continue
+		}
+		for _, decl := range file.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			funcs = append(funcs, &FuncInfo{
+				PackageInfo: pkg,
+				FileInfo:    file,
+				FuncDecl:    fd,
+			})
+		}
+	}
+	return funcs
+}
+
+// Methods returns FuncInfos that have receiver of type.
+// both of value and pointer receivers are included.
+func (t *TypeInfo) Methods() FuncInfos {
+	var methods FuncInfos
+	for _, fn := range t.PackageInfo.FuncInfos() {
+		if fn.IsMethod() && fn.ReceiverTypeName() == t.Name() {
+			methods = append(methods, fn)
+		}
+	}
+	return methods
+}
+
+// Name returns name of function.
+func (fn *FuncInfo) Name() string {
+	return fn.FuncDecl.Name.Name
+}
+
+// Signature returns Signature of function. receiver is not included.
+func (fn *FuncInfo) Signature() *Signature {
+	return NewSignature(fn.FuncDecl.Type)
+}
+
+// IsMethod returns true if function has receiver, otherwise returns false.
+func (fn *FuncInfo) IsMethod() bool {
+	return fn.FuncDecl.Recv != nil && len(fn.FuncDecl.Recv.List) != 0
+}
+
+// receiver returns type expression of receiver without pointer.
+func (fn *FuncInfo) receiver() (expr ast.Expr, ptr bool) {
+	if !fn.IsMethod() {
+		return nil, false
+	}
+	expr = fn.FuncDecl.Recv.List[0].Type
+	if paren, ok := expr.(*ast.ParenExpr); ok {
+		expr = paren.X
+	}
+	if star, ok := expr.(*ast.StarExpr); ok {
+		return star.X, true
+	}
+	return expr, false
+}
+
+// ReceiverName returns name of receiver. returns "" if receiver is unnamed or function is not method.
+func (fn *FuncInfo) ReceiverName() string {
+	if !fn.IsMethod() || len(fn.FuncDecl.Recv.List[0].Names) == 0 {
+		return ""
+	}
+	return fn.FuncDecl.Recv.List[0].Names[0].Name
+}
+
+// IsPointerReceiver returns true if method has pointer receiver, otherwise returns false.
+func (fn *FuncInfo) IsPointerReceiver() bool {
+	_, ptr := fn.receiver()
+	return ptr
+}
+
+// ReceiverTypeName returns name of receiver type without pointer and type parameters.
+// e.g. "Sample" for "(s *Sample[T])"
+func (fn *FuncInfo) ReceiverTypeName() string {
+	expr, _ := fn.receiver()
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.IndexExpr:
+		if ident, ok := t.X.(*ast.Ident); ok {
+			return ident.Name
+		}
+	case *ast.IndexListExpr:
+		if ident, ok := t.X.(*ast.Ident); ok {
+			return ident.Name
+		}
+	}
+	return ""
+}
+
+// ReceiverTypeParams returns names of type parameters in receiver of generic type.
+// e.g. []string{"K", "V"} for "(m *Map[K, V])"
+func (fn *FuncInfo) ReceiverTypeParams() []string {
+	var indices []ast.Expr
+	expr, _ := fn.receiver()
+	switch t := expr.(type) {
+	case *ast.IndexExpr:
+		indices = []ast.Expr{t.Index}
+	case *ast.IndexListExpr:
+		indices = t.Indices
+	}
+	names := make([]string, 0, len(indices))
+	for _, index := range indices {
+		names = append(names, types.ExprString(index))
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	return names
+}
+
+// ReceiverString returns Go source of receiver that has same style. e.g. "(s *Sample[T])"
+// name is used when receiver is unnamed.
+func (fn *FuncInfo) ReceiverString(name string) string {
+	if !fn.IsMethod() {
+		return ""
+	}
+	if n := fn.ReceiverName(); n != "" && n != "_" {
+		name = n
+	}
+	return "(" + name + " " + types.ExprString(fn.FuncDecl.Recv.List[0].Type) + ")"
+}
+
+// ReceiverTypeInfo returns TypeInfo of receiver type. returns nil if it is not found.
+func (fn *FuncInfo) ReceiverTypeInfo() *TypeInfo {
+	name := fn.ReceiverTypeName()
+	if name == "" {
+		return nil
+	}
+	return fn.PackageInfo.findTypeInfo(name)
+}
@@ -0,0 +1,77 @@
+package genbase
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFuncInfoReceiver(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	type Sample struct{}
+
+	func (s Sample) Value() string { return "" }
+
+	func (s *Sample) Pointer() {}
+
+	type Map[K comparable, V any] map[K]V
+
+	func (m *Map[K, V]) Get(key K) V { return (*m)[key] }
+
+	type List[T any] []T
+
+	func (List[T]) Len() int { return 0 }
+
+	func NewSample() *Sample { return nil }
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	funcs := pInfo.FuncInfos()
+	if len(funcs) != 5 {
+		t.Fatalf("unexpected: %v", len(funcs))
+	}
+
+	expects := []struct {
+		method     bool
+		ptr        bool
+		typeName   string
+		typeParams []string
+		receiver   string
+	}{
+		{true, false, "Sample", nil, "(s Sample)"},
+		{true, true, "Sample", nil, "(s *Sample)"},
+		{true, true, "Map", []string{"K", "V"}, "(m *Map[K, V])"},
+		{true, false, "List", []string{"T"}, "(r List[T])"},
+		{false, false, "", nil, ""},
+	}
+	for i, fn := range funcs {
+		expect := expects[i]
+		if v := fn.IsMethod(); v != expect.method {
+			t.Errorf("unexpected: %s IsMethod %v", fn.Name(), v)
+		}
+		if v := fn.IsPointerReceiver(); v != expect.ptr {
+			t.Errorf("unexpected: %s IsPointerReceiver %v", fn.Name(), v)
+		}
+		if v := fn.ReceiverTypeName(); v != expect.typeName {
+			t.Errorf("unexpected: %s ReceiverTypeName %v", fn.Name(), v)
+		}
+		if v := fn.ReceiverTypeParams(); !reflect.DeepEqual(v, expect.typeParams) {
+			t.Errorf("unexpected: %s ReceiverTypeParams %v", fn.Name(), v)
+		}
+		if v := fn.ReceiverString("r"); v != expect.receiver {
+			t.Errorf("unexpected: %s ReceiverString %v", fn.Name(), v)
+		}
+		if ti := fn.ReceiverTypeInfo(); (ti != nil) != expect.method || ti != nil && ti.Name() != expect.typeName {
+			t.Errorf("unexpected: %s ReceiverTypeInfo %v", fn.Name(), ti)
+		}
+	}
+
+	methods := pInfo.CollectTypeInfos([]string{"Sample"})[0].Methods()
+	if len(methods) != 2 || methods[0].Name() != "Value" || methods[1].Name() != "Pointer" {
+		t.Fatalf("unexpected: %v", methods)
+	}
+}
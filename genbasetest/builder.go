@@ -0,0 +1,182 @@
+package genbasetest
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/favclip/genbase"
+)
+
+// PackageBuilder builds PackageInfo for generator tests without writing Go source.
+type PackageBuilder struct {
+	name    string
+	imports []string
+	types   []*typeDecl
+	deps    map[string]*PackageBuilder
+}
+
+type typeDecl struct {
+	name        string
+	annotations []string
+	fields      []*Field
+	underlying  string // non struct type if it is not empty
+}
+
+// Field is field of struct type built by PackageBuilder.
+type Field struct {
+	Name string // empty for embedded field
+	Type string // type expression. e.g. "string", "*time.Time", "[]User"
+	Tag  string // struct tag without back quotes. e.g. `json:"name"`
+	Doc  string
+}
+
+// F returns Field of name and type, tag is optional.
+func F(name, typ string, tag ...string) *Field {
+	f := &Field{Name: name, Type: typ}
+	if len(tag) != 0 {
+		f.Tag = tag[0]
+	}
+	return f
+}
+
+// NewTestPackage creates PackageBuilder of package "sample".
+func NewTestPackage() *PackageBuilder {
+	return &PackageBuilder{
+		name: "sample",
+		deps: make(map[string]*PackageBuilder),
+	}
+}
+
+// Name sets package name.
+func (b *PackageBuilder) Name(name string) *PackageBuilder {
+	b.name = name
+	return b
+}
+
+// Import adds imports of packages. e.g. "time"
+func (b *PackageBuilder) Import(paths ...string) *PackageBuilder {
+	b.imports = append(b.imports, paths...)
+	return b
+}
+
+// AddDependency adds virtual dependency package of path, and imports it.
+func (b *PackageBuilder) AddDependency(path string, dep *PackageBuilder) *PackageBuilder {
+	b.deps[path] = dep
+	return b.Import(path)
+}
+
+// AddType adds struct type that has fields.
+func (b *PackageBuilder) AddType(name string, fields ...*Field) *PackageBuilder {
+	b.types = append(b.types, &typeDecl{name: name, fields: fields})
+	return b
+}
+
+// AddNamedType adds non struct type. e.g. AddNamedType("Status", "int")
+func (b *PackageBuilder) AddNamedType(name string, underlying string) *PackageBuilder {
+	b.types = append(b.types, &typeDecl{name: name, underlying: underlying})
+	return b
+}
+
+// Annotate adds annotation to the type added last. e.g. "+jwg", "+qbg: opts"
+func (b *PackageBuilder) Annotate(annotations ...string) *PackageBuilder {
+	if len(b.types) == 0 {
+		panic("genbasetest: Annotate is called before AddType")
+	}
+	t := b.types[len(b.types)-1]
+	t.annotations = append(t.annotations, annotations...)
+	return b
+}
+
+// Source returns Go source of package.
+func (b *PackageBuilder) Source() ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "package %s\n\n", b.name)
+	if len(b.imports) != 0 {
+		fmt.Fprintf(&buf, "import (\n")
+		for _, path := range b.imports {
+			fmt.Fprintf(&buf, "%s\n", strconv.Quote(path))
+		}
+		fmt.Fprintf(&buf, ")\n\n")
+	}
+	for _, t := range b.types {
+		for _, annotation := range t.annotations {
+			fmt.Fprintf(&buf, "// %s\n", annotation)
+		}
+		if t.underlying != "" {
+			fmt.Fprintf(&buf, "type %s %s\n\n", t.name, t.underlying)
+			continue
+		}
+		fmt.Fprintf(&buf, "type %s struct {\n", t.name)
+		for _, f := range t.fields {
+			if f.Doc != "" {
+				fmt.Fprintf(&buf, "// %s\n", f.Doc)
+			}
+			fmt.Fprintf(&buf, "%s %s", f.Name, f.Type)
+			if f.Tag != "" {
+				fmt.Fprintf(&buf, " `%s`", f.Tag)
+			}
+			fmt.Fprintf(&buf, "\n")
+		}
+		fmt.Fprintf(&buf, "}\n\n")
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting package %s: %s\n%s", b.name, err, buf.String())
+	}
+	return src, nil
+}
+
+// Build parses and type checks package, it fails tb if package is invalid.
+func (b *PackageBuilder) Build(tb testing.TB) *genbase.PackageInfo {
+	tb.Helper()
+
+	files, deps, err := b.sources()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	p := &genbase.Parser{}
+	pInfo, err := p.ParseStringSources(files, deps)
+	if err != nil {
+		tb.Fatalf("building package %s: %s", b.name, err)
+	}
+	return pInfo
+}
+
+// Model builds package and returns Model of all types.
+func (b *PackageBuilder) Model(tb testing.TB) *genbase.Model {
+	tb.Helper()
+
+	pInfo := b.Build(tb)
+	return genbase.NewModel(pInfo, pInfo.TypeInfos())
+}
+
+// sources returns virtual files of package and dependencies.
+func (b *PackageBuilder) sources() (map[string]string, map[string]map[string]string, error) {
+	src, err := b.Source()
+	if err != nil {
+		return nil, nil, err
+	}
+	files := map[string]string{b.name + ".go": string(src)}
+	deps := make(map[string]map[string]string)
+
+	paths := make([]string, 0, len(b.deps))
+	for path := range b.deps {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		depFiles, depDeps, err := b.deps[path].sources()
+		if err != nil {
+			return nil, nil, err
+		}
+		deps[path] = depFiles
+		for depPath, files := range depDeps {
+			deps[depPath] = files
+		}
+	}
+	return files, deps, nil
+}
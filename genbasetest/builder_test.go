@@ -0,0 +1,42 @@
+package genbasetest
+
+import (
+	"testing"
+)
+
+func TestPackageBuilder(t *testing.T) {
+	model := NewTestPackage().
+		AddDependency("example.com/model", NewTestPackage().Name("model").AddNamedType("ID", "int64")).
+		Import("time").
+		AddType("User",
+			F("ID", "model.ID", `json:"id"`),
+			F("Name", "string"),
+			F("Group", "*Group"),
+			F("At", "time.Time"),
+		).Annotate("+jwg").
+		AddType("Group", F("", "Base")).
+		AddType("Base").
+		Model(t)
+
+	if len(model.Types) != 3 {
+		t.Fatalf("unexpected: %d", len(model.Types))
+	}
+	user := model.Types[0]
+	if user.Name != "User" || len(user.Fields) != 4 {
+		t.Fatalf("unexpected: %#v", user)
+	}
+	if v, _ := user.Fields[0].Tags.Get("json"); v != "id" {
+		t.Errorf("unexpected: %s", v)
+	}
+	if v := user.Fields[0].Type.String(); v != "model.ID" {
+		t.Errorf("unexpected: %s", v)
+	}
+	if group := model.Types[1]; len(group.Fields) != 1 || !group.Fields[0].Embedded {
+		t.Errorf("unexpected: %#v", group)
+	}
+
+	pInfo := NewTestPackage().AddType("Sample", F("A", "string")).Annotate("+qbg").Build(t)
+	if v := len(pInfo.CollectTaggedTypeInfos("+qbg")); v != 1 {
+		t.Errorf("unexpected: %d", v)
+	}
+}
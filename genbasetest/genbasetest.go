@@ -0,0 +1,65 @@
+// Package genbasetest provides utilities for testing code generators built on genbase.
+package genbasetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/favclip/genbase"
+)
+
+// TypeCheck type-checks generated code together with the package in srcDir.
+// function bodies of generated code are checked too.
+func TypeCheck(tb testing.TB, srcDir string, fileName string, src []byte) {
+	tb.Helper()
+
+	p := &genbase.Parser{}
+	pInfo, err := p.ParsePackageDir(srcDir)
+	if err != nil {
+		tb.Fatalf("parsing %s: %s", srcDir, err)
+	}
+	if _, err := pInfo.CheckGenerated(filepath.Join(srcDir, fileName), src); err != nil {
+		tb.Fatalf("generated code %s doesn't compile: %s", fileName, err)
+	}
+}
+
+// GoBuild runs `go build` over the package in srcDir with generated code.
+// generated code is written to temporary directory and overlaid into srcDir,
+// so srcDir is not modified.
+func GoBuild(tb testing.TB, srcDir string, fileName string, src []byte) {
+	tb.Helper()
+
+	dir := tb.TempDir()
+	srcDir, err := filepath.Abs(srcDir)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	genPath := filepath.Join(dir, fileName)
+	if err := ioutil.WriteFile(genPath, src, 0644); err != nil {
+		tb.Fatal(err)
+	}
+	overlay, err := json.Marshal(map[string]map[string]string{
+		"Replace": {filepath.Join(srcDir, fileName): genPath},
+	})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	overlayPath := filepath.Join(dir, "overlay.json")
+	if err := ioutil.WriteFile(overlayPath, overlay, 0644); err != nil {
+		tb.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	cmd := exec.Command("go", "build", "-overlay", overlayPath, "-o", filepath.Join(dir, "out"), ".")
+	cmd.Dir = srcDir
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		tb.Fatalf("generated code %s doesn't compile: %s\n%s", fileName, err, out.String())
+	}
+}
@@ -0,0 +1,21 @@
+package genbasetest
+
+import (
+	"testing"
+)
+
+var generated = []byte(`package a
+
+func (a *A) String() string { return "A" }
+`)
+
+func TestTypeCheck(t *testing.T) {
+	TypeCheck(t, "../misc/fixture/a", "model_gen.go", generated)
+}
+
+func TestGoBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("go build is skipped in short mode")
+	}
+	GoBuild(t, "../misc/fixture/a", "model_gen.go", generated)
+}
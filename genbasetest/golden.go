@@ -0,0 +1,151 @@
+package genbasetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/favclip/genbase"
+)
+
+// UpdateGoldenEnv is environment variable that makes Golden rewrite golden files instead of comparing them.
+// e.g. GENBASE_UPDATE_GOLDEN=1 go test ./...
+const UpdateGoldenEnv = "GENBASE_UPDATE_GOLDEN"
+
+// GoldenModelFile and GoldenOutputFile are names of golden files in each fixture directory of goldenDir.
+const (
+	GoldenModelFile  = "model.json"
+	GoldenOutputFile = "output.golden"
+)
+
+// GenerateFunc is generator under golden test, it returns generated code of package.
+type GenerateFunc func(pkg *genbase.PackageInfo) ([]byte, error)
+
+// Golden parses each fixture package in subdirectories of corpusDir as subtest,
+// and compares serialized Model of all types and output of generate with golden files in same named subdirectory of goldenDir.
+// golden files are rewritten if UpdateGoldenEnv is set, commit them to notice behavior changes across genbase versions.
+// generate may be nil to record Model only. output must not depend on environment, e.g. os.Args of PrintHeader.
+func Golden(t *testing.T, corpusDir string, goldenDir string, generate GenerateFunc) {
+	t.Helper()
+
+	entries, err := ioutil.ReadDir(corpusDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	update := os.Getenv(UpdateGoldenEnv) != ""
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		t.Run(name, func(t *testing.T) {
+			p := &genbase.Parser{}
+			pInfo, err := p.ParsePackageDir(filepath.Join(corpusDir, name))
+			if err != nil {
+				t.Fatalf("parsing %s: %s", name, err)
+			}
+
+			dir := filepath.Join(goldenDir, name)
+			model := genbase.NewModel(pInfo, pInfo.TypeInfos())
+			model.Dir = "" // depends on location of corpus
+			if err := CompareGoldenModel(filepath.Join(dir, GoldenModelFile), model, update); err != nil {
+				t.Error(err)
+			}
+
+			if generate == nil {
+				return
+			}
+			src, err := generate(pInfo)
+			if err != nil {
+				t.Fatalf("generating %s: %s", name, err)
+			}
+			if err := CompareGolden(filepath.Join(dir, GoldenOutputFile), src, update); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// CompareGoldenModel compares model with Model serialized in golden file.
+// returned error lists changes of types and fields by genbase.Diff.
+// golden file is rewritten if update is true.
+func CompareGoldenModel(path string, model *genbase.Model, update bool) error {
+	b, err := json.MarshalIndent(model, "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	if update {
+		return writeGolden(path, b)
+	}
+
+	golden, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading golden file: %s, set %s=1 to create it", err, UpdateGoldenEnv)
+	}
+	if bytes.Equal(golden, b) {
+		return nil
+	}
+
+	recorded, err := genbase.LoadModel(golden)
+	if err != nil {
+		return fmt.Errorf("%s: loading golden model: %s", path, err)
+	}
+	lines := []string{fmt.Sprintf("%s: model differs from golden file", path)}
+	for _, change := range genbase.Diff(recorded, model) {
+		lines = append(lines, change.String())
+	}
+	if len(lines) == 1 {
+		lines = append(lines, firstDifference(golden, b))
+	}
+	return fmt.Errorf("%s", strings.Join(lines, "\n\t"))
+}
+
+// CompareGolden compares got with content of golden file.
+// returned error shows first different line.
+// golden file is rewritten if update is true.
+func CompareGolden(path string, got []byte, update bool) error {
+	if update {
+		return writeGolden(path, got)
+	}
+
+	golden, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading golden file: %s, set %s=1 to create it", err, UpdateGoldenEnv)
+	}
+	if bytes.Equal(golden, got) {
+		return nil
+	}
+	return fmt.Errorf("%s: output differs from golden file\n\t%s", path, firstDifference(golden, got))
+}
+
+func writeGolden(path string, b []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0644)
+}
+
+// firstDifference describes first different line between golden and got.
+func firstDifference(golden, got []byte) string {
+	goldenLines := strings.Split(string(golden), "\n")
+	gotLines := strings.Split(string(got), "\n")
+	for i := 0; i < len(goldenLines) || i < len(gotLines); i++ {
+		var want, have string
+		if i < len(goldenLines) {
+			want = goldenLines[i]
+		}
+		if i < len(gotLines) {
+			have = gotLines[i]
+		}
+		if i >= len(goldenLines) || i >= len(gotLines) || want != have {
+			return fmt.Sprintf("line %d: golden %q, got %q", i+1, want, have)
+		}
+	}
+	return "no difference"
+}
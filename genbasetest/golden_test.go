@@ -0,0 +1,58 @@
+package genbasetest
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/favclip/genbase"
+)
+
+func TestGolden(t *testing.T) {
+	Golden(t, "../misc/fixture", "testdata/golden", func(pkg *genbase.PackageInfo) ([]byte, error) {
+		g := genbase.NewGenerator(pkg)
+		g.PrintHeader("golden", &[]string{})
+		for _, typeInfo := range pkg.TypeInfos() {
+			g.Printf("func (%s) TypeName() string { return %q }\n", typeInfo.Name(), typeInfo.Name())
+		}
+		return g.Format()
+	})
+}
+
+func TestCompareGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a", GoldenOutputFile)
+	if err := CompareGolden(path, []byte("a\nb\n"), false); err == nil || !strings.Contains(err.Error(), UpdateGoldenEnv) {
+		t.Errorf("unexpected: %v", err)
+	}
+	if err := CompareGolden(path, []byte("a\nb\n"), true); err != nil {
+		t.Fatal(err)
+	}
+	if err := CompareGolden(path, []byte("a\nb\n"), false); err != nil {
+		t.Errorf("unexpected: %s", err)
+	}
+	err := CompareGolden(path, []byte("a\nc\n"), false)
+	if err == nil || !strings.HasSuffix(err.Error(), `line 2: golden "b", got "c"`) {
+		t.Errorf("unexpected: %v", err)
+	}
+}
+
+func TestCompareGoldenModel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), GoldenModelFile)
+	old := NewTestPackage().AddType("User", F("Name", "string")).Model(t)
+	if err := CompareGoldenModel(path, old, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := CompareGoldenModel(path, old, false); err != nil {
+		t.Errorf("unexpected: %s", err)
+	}
+
+	new := NewTestPackage().AddType("User", F("Name", "int"), F("Age", "int")).Model(t)
+	err := CompareGoldenModel(path, new, false)
+	if err == nil {
+		t.Fatal("unexpected: no error")
+	}
+	expected := path + ": model differs from golden file\n\tUser.Name retyped: string -> int\n\tUser.Age added"
+	if err.Error() != expected {
+		t.Errorf("unexpected: %s", err)
+	}
+}
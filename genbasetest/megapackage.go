@@ -0,0 +1,101 @@
+package genbasetest
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+)
+
+// MegaPackageTag is annotation of every type in package of MegaPackage.
+const MegaPackageTag = "+mega"
+
+// MegaPackage is spec of large generated package for benchmarks.
+// same spec always writes same sources, so results are comparable across genbase versions.
+// e.g. go test -run XXX -bench MegaPackage ./genbasetest
+type MegaPackage struct {
+	Name   string // package name, "mega" if empty
+	Files  int    // number of files
+	Types  int    // number of struct types per file
+	Fields int    // number of fields per type
+}
+
+// Write writes go.mod and sources of package to dir.
+// types have fields of builtin, stdlib, pointer, slice, map and embedded types, and method per type.
+func (m *MegaPackage) Write(dir string) error {
+	name := m.Name
+	if name == "" {
+		name = "mega"
+	}
+	goMod := fmt.Sprintf("module example.com/%s\n\ngo 1.15\n", name)
+	if err := ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0644); err != nil {
+		return err
+	}
+	for i := 0; i < m.Files; i++ {
+		fileName := filepath.Join(dir, fmt.Sprintf("file%d.go", i))
+		if err := ioutil.WriteFile(fileName, m.source(name, i), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TypeCount returns number of types in package.
+func (m *MegaPackage) TypeCount() int {
+	return m.Files * m.Types
+}
+
+// source returns source of file.
+func (m *MegaPackage) source(name string, file int) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "package %s\n\n", name)
+	if m.Types > 0 && m.Fields > 4 {
+		// time.Time is type of field 4.
+		fmt.Fprintf(&buf, "import \"time\"\n\n")
+	}
+	for i := 0; i < m.Types; i++ {
+		typeName := fmt.Sprintf("T%d_%d", file, i)
+		fmt.Fprintf(&buf, "// %s is generated type.\n// %s\ntype %s struct {\n", typeName, MegaPackageTag, typeName)
+		if file != 0 || i != 0 {
+			fmt.Fprintf(&buf, "\t*%s\n", m.previous(file, i))
+		}
+		for j := 0; j < m.Fields; j++ {
+			fmt.Fprintf(&buf, "\tF%d %s `json:\"f%d,omitempty\"`\n", j, m.fieldType(file, i, j), j)
+		}
+		fmt.Fprintf(&buf, "}\n\n")
+		fmt.Fprintf(&buf, "// Name returns name of type.\nfunc (t *%s) Name() string { return %q }\n\n", typeName, typeName)
+	}
+	return buf.Bytes()
+}
+
+// fieldType returns type of field j in type i of file.
+func (m *MegaPackage) fieldType(file, i, j int) string {
+	switch j % 8 {
+	case 0:
+		return "int64"
+	case 1:
+		return "string"
+	case 2:
+		return "*bool"
+	case 3:
+		return "[]float64"
+	case 4:
+		return "time.Time"
+	case 5:
+		return "map[string][]byte"
+	case 6:
+		if file != 0 || i != 0 {
+			return "[]*" + m.previous(file, i)
+		}
+		return "[]*string"
+	}
+	return "struct{ A, B int }"
+}
+
+// previous returns name of type that is declared before type i of file.
+func (m *MegaPackage) previous(file, i int) string {
+	if i == 0 {
+		return fmt.Sprintf("T%d_%d", file-1, m.Types-1)
+	}
+	return fmt.Sprintf("T%d_%d", file, i-1)
+}
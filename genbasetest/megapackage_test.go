@@ -0,0 +1,134 @@
+package genbasetest
+
+import (
+	"fmt"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/favclip/genbase"
+)
+
+// megaPackages are sizes of benchmarks.
+var megaPackages = []*MegaPackage{
+	{Files: 5, Types: 20, Fields: 10},
+	{Files: 20, Types: 50, Fields: 20},
+}
+
+func (m *MegaPackage) String() string {
+	return fmt.Sprintf("%dx%dx%d", m.Files, m.Types, m.Fields)
+}
+
+func writeMegaPackage(tb testing.TB, m *MegaPackage) string {
+	dir := tb.TempDir()
+	if err := m.Write(dir); err != nil {
+		tb.Fatal(err)
+	}
+	return dir
+}
+
+func parseMegaPackage(tb testing.TB, dir string) *genbase.PackageInfo {
+	p := &genbase.Parser{}
+	pInfo, err := p.ParsePackageDir(dir)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return pInfo
+}
+
+// generateMega is output phase of benchmarks.
+func generateMega(pkg *genbase.PackageInfo) ([]byte, error) {
+	g := genbase.NewGenerator(pkg)
+	g.PrintHeader("mega", &[]string{})
+	for _, t := range pkg.CollectTaggedTypeInfos(MegaPackageTag) {
+		st, err := t.StructType()
+		if err != nil {
+			return nil, err
+		}
+		g.Printf("func (t *%s) FieldNames() []string {\nreturn []string{\n", t.Name())
+		for _, f := range st.FieldInfos() {
+			g.Printf("%q, // %s\n", f.Name(), f.TypeName())
+		}
+		g.Printf("}\n}\n\n")
+	}
+	return g.Format()
+}
+
+func TestMegaPackage(t *testing.T) {
+	m := &MegaPackage{Files: 2, Types: 3, Fields: 9}
+	pInfo := parseMegaPackage(t, writeMegaPackage(t, m))
+	if v := len(pInfo.CollectTaggedTypeInfos(MegaPackageTag)); v != m.TypeCount() {
+		t.Errorf("unexpected: %d", v)
+	}
+	if pInfo.Name() != "mega" {
+		t.Errorf("unexpected: %s", pInfo.Name())
+	}
+	b, err := generateMega(pInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gen := genbase.NewGenerator(pInfo)
+	gen.Printf("%s", b)
+	if err := gen.Verify("mega_gen.go"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMegaPackageVet(t *testing.T) {
+	for _, m := range []*MegaPackage{{Files: 1, Types: 2, Fields: 3}, {Files: 2, Types: 1, Fields: 5}} {
+		cmd := exec.Command("go", "vet", ".")
+		cmd.Dir = writeMegaPackage(t, m)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("unexpected: %s %s %s", m, err, out)
+		}
+	}
+}
+
+// BenchmarkMegaPackageParse measures parsing and type checking, time of each phase is reported by Stats of package.
+func BenchmarkMegaPackageParse(b *testing.B) {
+	for _, m := range megaPackages {
+		b.Run(m.String(), func(b *testing.B) {
+			dir := writeMegaPackage(b, m)
+			var parse, typeCheck time.Duration
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				stats := parseMegaPackage(b, dir).Stats()
+				parse += stats.ParseTime
+				typeCheck += stats.TypeCheckTime
+			}
+			b.ReportMetric(float64(parse.Nanoseconds())/float64(b.N), "parse-ns/op")
+			b.ReportMetric(float64(typeCheck.Nanoseconds())/float64(b.N), "typecheck-ns/op")
+		})
+	}
+}
+
+func BenchmarkMegaPackageCollect(b *testing.B) {
+	for _, m := range megaPackages {
+		b.Run(m.String(), func(b *testing.B) {
+			pInfo := parseMegaPackage(b, writeMegaPackage(b, m))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if v := len(pInfo.CollectTaggedTypeInfos(MegaPackageTag)); v != m.TypeCount() {
+					b.Fatalf("unexpected: %d", v)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkMegaPackageOutput(b *testing.B) {
+	for _, m := range megaPackages {
+		b.Run(m.String(), func(b *testing.B) {
+			pInfo := parseMegaPackage(b, writeMegaPackage(b, m))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := generateMega(pInfo); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package genbase
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// TypeParam is type parameter of generic type declaration.
+type TypeParam struct {
+	Name       string
+	Constraint ast.Expr
+}
+
+// Term is element of type set. e.g. ~int
+type Term struct {
+	Tilde bool // true if underlying types of Type are permitted
+	Type  types.Type
+}
+
+// TypeSet is structured form of constraint interface.
+type TypeSet struct {
+	// Terms are permitted types. all types are permitted if it is empty.
+	Terms      []*Term
+	Methods    []*types.Func
+	Comparable bool
+}
+
+// TypeParams returns type parameters of generic type. returns nil if type is not generic.
+// type parameters declared together like "[K, V any]" are split into each TypeParam.
+func (t *TypeInfo) TypeParams() []*TypeParam {
+	if t.TypeSpec.TypeParams == nil {
+		return nil
+	}
+	var params []*TypeParam
+	for _, f := range t.TypeSpec.TypeParams.List {
+		for _, name := range f.Names {
+			params = append(params, &TypeParam{Name: name.Name, Constraint: f.Type})
+		}
+	}
+	return params
+}
+
+// TypeSet returns TypeSet of constraint of type parameter.
+// returns nil if types are not resolved.
+func (tp *TypeParam) TypeSet(pkg *PackageInfo) *TypeSet {
+	return pkg.TypeSetOf(tp.Constraint)
+}
+
+// TypeSetOf returns TypeSet of constraint expression. e.g. "~int | ~string", "Number"
+// unions are flattened and embedded constraints are intersected.
+// returns nil if types are not resolved.
+func (pkg *PackageInfo) TypeSetOf(constraint ast.Expr) *TypeSet {
+	typ := pkg.TypeOf(constraint)
+	if typ == nil {
+		return nil
+	}
+	ts := &TypeSet{}
+	ts.Terms, _ = termsOf(typ)
+	if iface, ok := typ.Underlying().(*types.Interface); ok {
+		ts.Comparable = iface.IsComparable()
+		for i := 0; i < iface.NumMethods(); i++ {
+			ts.Methods = append(ts.Methods, iface.Method(i))
+		}
+	}
+	return ts
+}
+
+// Permits returns true if typ satisfies terms of type set, otherwise returns false.
+// methods are not checked.
+func (ts *TypeSet) Permits(typ types.Type) bool {
+	if len(ts.Terms) == 0 {
+		return true
+	}
+	for _, term := range ts.Terms {
+		if term.Permits(typ) {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns Go source of terms. e.g. "~int | ~string"
+func (ts *TypeSet) String() string {
+	if len(ts.Terms) == 0 {
+		return "any"
+	}
+	ss := make([]string, 0, len(ts.Terms))
+	for _, term := range ts.Terms {
+		ss = append(ss, term.String())
+	}
+	return strings.Join(ss, " | ")
+}
+
+// Permits returns true if typ is in term, otherwise returns false.
+func (term *Term) Permits(typ types.Type) bool {
+	if term.Tilde {
+		return types.Identical(term.Type, typ.Underlying())
+	}
+	return types.Identical(term.Type, typ)
+}
+
+// String returns Go source of term. e.g. "~int", "time.Duration"
+// named types are qualified by package name.
+func (term *Term) String() string {
+	s := types.TypeString(term.Type, func(p *types.Package) string { return p.Name() })
+	if term.Tilde {
+		return "~" + s
+	}
+	return s
+}
+
+// termsOf returns terms of constraint. all is true if all types are permitted.
+func termsOf(typ types.Type) (terms []*Term, all bool) {
+	switch t := typ.(type) {
+	case *types.Union:
+		for i := 0; i < t.Len(); i++ {
+			term := t.Term(i)
+			if _, ok := term.Type().Underlying().(*types.Interface); ok {
+				sub, subAll := termsOf(term.Type())
+				if subAll {
+					return nil, true
+				}
+				terms = appendTerms(terms, sub...)
+				continue
+			}
+			terms = appendTerms(terms, &Term{Tilde: term.Tilde(), Type: term.Type()})
+		}
+		return terms, false
+	}
+
+	iface, ok := typ.Underlying().(*types.Interface)
+	if !ok {
+		return []*Term{{Type: typ}}, false
+	}
+	all = true
+	for i := 0; i < iface.NumEmbeddeds(); i++ {
+		sub, subAll := termsOf(iface.EmbeddedType(i))
+		switch {
+		case subAll:
+		case all:
+			terms, all = sub, false
+		default:
+			terms = intersectTerms(terms, sub)
+		}
+	}
+	return terms, all
+}
+
+func intersectTerms(a, b []*Term) []*Term {
+	var terms []*Term
+	for _, x := range a {
+		for _, y := range b {
+			switch {
+			case x.Tilde && y.Tilde && types.Identical(x.Type, y.Type):
+				terms = appendTerms(terms, x)
+			case x.Tilde && !y.Tilde && x.Permits(y.Type):
+				terms = appendTerms(terms, y)
+			case !x.Tilde && y.Permits(x.Type):
+				terms = appendTerms(terms, x)
+			}
+		}
+	}
+	return terms
+}
+
+// appendTerms appends terms without duplication.
+func appendTerms(terms []*Term, ts ...*Term) []*Term {
+outer:
+	for _, t := range ts {
+		for _, term := range terms {
+			if term.Tilde == t.Tilde && types.Identical(term.Type, t.Type) {
+				continue outer
+			}
+		}
+		terms = append(terms, t)
+	}
+	return terms
+}
+
+// ParseInstantiation parses instantiation expression to type name and type arguments.
+// e.g. "Foo[int, *Bar]" returns "Foo" and []string{"int", "*Bar"}.
+func ParseInstantiation(expr string) (string, []string, error) {
+	x, err := parser.ParseExpr(expr)
+	if err != nil {
+		return "", nil, fmt.Errorf("parsing instantiation %q: %s", expr, err)
+	}
+	var indices []ast.Expr
+	switch t := x.(type) {
+	case *ast.IndexExpr:
+		x, indices = t.X, []ast.Expr{t.Index}
+	case *ast.IndexListExpr:
+		x, indices = t.X, t.Indices
+	}
+	ident, ok := x.(*ast.Ident)
+	if !ok || len(indices) == 0 {
+		return "", nil, fmt.Errorf("%q is not instantiation", expr)
+	}
+	typeArgs := make([]string, 0, len(indices))
+	for _, index := range indices {
+		typeArgs = append(typeArgs, types.ExprString(index))
+	}
+	return ident.Name, typeArgs, nil
+}
+
+// Instantiate returns fields of generic struct type, all type parameters are replaced by typeArgs.
+// returned FieldInfos are copies, so the original declaration is not changed.
+// typeArgs are checked against constraints if types are resolved.
+func (t *TypeInfo) Instantiate(typeArgs ...string) (FieldInfos, error) {
+	params := t.TypeParams()
+	if len(params) != len(typeArgs) {
+		return nil, fmt.Errorf("%s: got %d type arguments, but %d type parameters", t.Name(), len(typeArgs), len(params))
+	}
+	st, err := t.StructType()
+	if err != nil {
+		return nil, err
+	}
+
+	subst := make(map[string]string)
+	for i, param := range params {
+		if _, err := parser.ParseExpr(typeArgs[i]); err != nil {
+			return nil, fmt.Errorf("%s: parsing type argument %q: %s", t.Name(), typeArgs[i], err)
+		}
+		subst[param.Name] = typeArgs[i]
+	}
+	if err := t.validateTypeArgs(typeArgs); err != nil {
+		return nil, err
+	}
+
+	var fields FieldInfos
+	for _, f := range st.FieldInfos() {
+		typ, err := substitute(f.Type, subst)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", t.Name(), err)
+		}
+		field := *f
+		field.Type = typ
+		fields = append(fields, &field)
+	}
+	return fields, nil
+}
+
+// validateTypeArgs checks that typeArgs satisfy constraints.
+func (t *TypeInfo) validateTypeArgs(typeArgs []string) error {
+	obj := t.TypeObject()
+	if obj == nil {
+		return nil
+	}
+	named, ok := obj.Type().(*types.Named)
+	if !ok {
+		return nil
+	}
+	pkg := t.PackageInfo
+	targs := make([]types.Type, 0, len(typeArgs))
+	for _, arg := range typeArgs {
+		tv, err := types.Eval(pkg.FileSet, pkg.Types, t.TypeSpec.Pos(), arg)
+		if err != nil {
+			return fmt.Errorf("%s: type argument %s: %s", t.Name(), arg, err)
+		}
+		if !tv.IsType() {
+			return fmt.Errorf("%s: type argument %s is not type", t.Name(), arg)
+		}
+		targs = append(targs, tv.Type)
+	}
+	if _, err := types.Instantiate(nil, named, targs, true); err != nil {
+		return fmt.Errorf("%s: %s", t.Name(), err)
+	}
+	return nil
+}
+
+// substitute returns copy of type expression, identifiers in subst are replaced by parsed expressions.
+func substitute(expr ast.Expr, subst map[string]string) (ast.Expr, error) {
+	x, err := parser.ParseExpr(types.ExprString(expr))
+	if err != nil {
+		return nil, err
+	}
+	x = astutil.Apply(x, func(c *astutil.Cursor) bool {
+		switch c.Parent().(type) {
+		case *ast.SelectorExpr:
+			// package name and selected name
+			return false
+		case *ast.Field:
+			if c.Name() == "Names" {
+				return false
+			}
+		}
+		if ident, ok := c.Node().(*ast.Ident); ok {
+			if arg, ok := subst[ident.Name]; ok {
+				argExpr, _ := parser.ParseExpr(arg)
+				c.Replace(argExpr)
+			}
+		}
+		return true
+	}, nil).(ast.Expr)
+	return x, nil
+}
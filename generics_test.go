@@ -0,0 +1,126 @@
+package genbase
+
+import (
+	"go/types"
+	"testing"
+)
+
+func TestTypeParamTypeSet(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	type Integer interface {
+		~int | ~int64
+	}
+
+	type Number interface {
+		Integer | float64
+	}
+
+	type Both interface {
+		Number
+		Integer
+	}
+
+	type ID int64
+
+	type Sample[N Number, I Both, K comparable, V any, S ~string | ID] struct{}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	params := pInfo.CollectTypeInfos([]string{"Sample"})[0].TypeParams()
+	if len(params) != 5 {
+		t.Fatalf("unexpected: %v", len(params))
+	}
+	expects := []string{"~int | ~int64 | float64", "~int | ~int64", "any", "any", "~string | sample.ID"}
+	for i, param := range params {
+		ts := param.TypeSet(pInfo)
+		if v := ts.String(); v != expects[i] {
+			t.Errorf("unexpected: %s %s", param.Name, v)
+		}
+	}
+
+	if ts := params[2].TypeSet(pInfo); !ts.Comparable {
+		t.Fatalf("unexpected: %v", ts.Comparable)
+	}
+	ts := params[0].TypeSet(pInfo)
+	id := pInfo.Types.Scope().Lookup("ID").Type()
+	if !ts.Permits(id) || ts.Permits(types.Typ[types.String]) {
+		t.Fatalf("unexpected: %v", ts)
+	}
+
+	if params := pInfo.CollectTypeInfos([]string{"ID"})[0].TypeParams(); params != nil {
+		t.Fatalf("unexpected: %v", params)
+	}
+}
+
+func TestTypeInfoInstantiate(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	import "time"
+
+	type Number interface {
+		~int | ~float64
+	}
+
+	// +jwg instantiate=Pair[int,time.Time]
+	type Pair[K Number, V any] struct {
+		Key    K
+		Values []*V           `+"`json:\"values\"`"+`
+		Lookup map[K]V
+		Nested struct{ V V }
+		Time   time.Time
+	}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name, typeArgs, err := ParseInstantiation("Pair[int, time.Time]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "Pair" || len(typeArgs) != 2 || typeArgs[1] != "time.Time" {
+		t.Fatalf("unexpected: %s %v", name, typeArgs)
+	}
+
+	typeInfo := pInfo.CollectTypeInfos([]string{name})[0]
+	fields, err := typeInfo.Instantiate(typeArgs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expects := []string{"int", "[]*time.Time", "map[int]time.Time", "struct{V time.Time}", "time.Time"}
+	for i, f := range fields {
+		if v := types.ExprString(f.Type); v != expects[i] {
+			t.Errorf("unexpected: %s %s", f.Names[0].Name, v)
+		}
+	}
+	if fields[1].Tag == nil || fields[1].Tag.Value != "`json:\"values\"`" {
+		t.Fatalf("unexpected: %v", fields[1].Tag)
+	}
+
+	// original declaration is not changed
+	if v := types.ExprString(typeInfo.TypeParams()[0].Constraint); v != "Number" {
+		t.Fatalf("unexpected: %s", v)
+	}
+	st, _ := typeInfo.StructType()
+	if v := types.ExprString(st.FieldInfos()[0].Type); v != "K" {
+		t.Fatalf("unexpected: %s", v)
+	}
+
+	for _, args := range [][]string{{"int"}, {"string", "int"}, {"int", "Unknown"}} {
+		if _, err := typeInfo.Instantiate(args...); err == nil {
+			t.Errorf("unexpected: %v is instantiated", args)
+		}
+	}
+	for _, expr := range []string{"Pair", "pkg.Pair[int]", "Pair["} {
+		if _, _, err := ParseInstantiation(expr); err == nil {
+			t.Errorf("unexpected: %s is parsed", expr)
+		}
+	}
+}
@@ -1,3 +1,11 @@
 module github.com/favclip/genbase
 
-go 1.15
+go 1.22
+
+require golang.org/x/tools v0.1.0
+
+require (
+	golang.org/x/mod v0.3.0 // indirect
+	golang.org/x/sys v0.0.0-20210119212857-b64e53b001e4 // indirect
+	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1 // indirect
+)
@@ -0,0 +1,143 @@
+package genbase
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ModuleGoVersion returns language version in go directive of go.mod that contains directory. e.g. "go1.18"
+// returns "" if go.mod or go directive is not found.
+func ModuleGoVersion(directory string) (string, error) {
+	b, err := readGoMod(directory)
+	if err != nil || b == nil {
+		return "", err
+	}
+	return parseGoDirective(b), nil
+}
+
+// readGoMod returns content of go.mod that contains directory, or nil if go.mod is not found.
+func readGoMod(directory string) ([]byte, error) {
+	dir, err := filepath.Abs(directory)
+	if err != nil {
+		return nil, fmt.Errorf("cannot process directory %s: %s", directory, err)
+	}
+	for {
+		b, err := ioutil.ReadFile(filepath.Join(dir, "go.mod"))
+		if err == nil {
+			return b, nil
+		} else if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("reading go.mod: %s", err)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// modFile is module path, required module paths and replacements of go.mod.
+type modFile struct {
+	module   string
+	requires []string
+	replaces map[string]string // module path to replacement. e.g. "../uuid", "example.com/fork v1.0.0"
+}
+
+// parseModFile parses module, require and replace directives in go.mod content.
+func parseModFile(b []byte) *modFile {
+	mod := &modFile{replaces: make(map[string]string)}
+	block := ""
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.Index(line, "//"); idx != -1 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+		case block != "" && fields[0] == ")":
+			block = ""
+		case block == "require":
+			mod.requires = append(mod.requires, strings.Trim(fields[0], `"`))
+		case block == "replace":
+			mod.addReplace(fields)
+		case fields[0] == "module" && len(fields) == 2:
+			mod.module = strings.Trim(fields[1], `"`)
+		case (fields[0] == "require" || fields[0] == "replace") && len(fields) == 2 && fields[1] == "(":
+			block = fields[0]
+		case fields[0] == "require" && len(fields) >= 3:
+			mod.requires = append(mod.requires, strings.Trim(fields[1], `"`))
+		case fields[0] == "replace":
+			mod.addReplace(fields[1:])
+		}
+	}
+	return mod
+}
+
+// addReplace adds fields of replace directive. e.g. ["a", "v1.0.0", "=>", "../a"]
+func (mod *modFile) addReplace(fields []string) {
+	for i, field := range fields {
+		if field == "=>" && i > 0 && i+1 < len(fields) {
+			for j := range fields {
+				fields[j] = strings.Trim(fields[j], `"`)
+			}
+			mod.replaces[fields[0]] = strings.Join(fields[i+1:], " ")
+			return
+		}
+	}
+}
+
+// moduleOf returns path of module that provides package of path, or "" if it is unknown.
+// main module is preferred, and the longest required or replaced module path is used otherwise.
+func (mod *modFile) moduleOf(path string) string {
+	if hasPathPrefix(path, mod.module) {
+		return mod.module
+	}
+	found := ""
+	for _, req := range mod.requires {
+		if hasPathPrefix(path, req) && len(req) > len(found) {
+			found = req
+		}
+	}
+	for old := range mod.replaces {
+		if hasPathPrefix(path, old) && len(old) > len(found) {
+			found = old
+		}
+	}
+	return found
+}
+
+// hasPathPrefix returns true if path is prefix or prefix followed by "/" and more elements, otherwise returns false.
+func hasPathPrefix(path, prefix string) bool {
+	return prefix != "" && (path == prefix || strings.HasPrefix(path, prefix+"/"))
+}
+
+// parseGoDirective returns version of go directive in go.mod content.
+func parseGoDirective(b []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.Index(line, "//"); idx != -1 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "go" {
+			return "go" + fields[1]
+		}
+	}
+	return ""
+}
+
+// goVersion returns language version for package in directory.
+func (p *Parser) goVersion(directory string) (string, error) {
+	if p.GoVersion != "" {
+		return p.GoVersion, nil
+	}
+	return ModuleGoVersion(directory)
+}
@@ -0,0 +1,101 @@
+package genbase
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestModuleGoVersion(t *testing.T) {
+	v, err := ModuleGoVersion("./misc/fixture/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "go1.22" {
+		t.Fatalf("unexpected: %s", v)
+	}
+
+	if v := parseGoDirective([]byte("module example.com/a\n\ngo 1.21.0 // comment\n")); v != "go1.21.0" {
+		t.Fatalf("unexpected: %s", v)
+	}
+	if v := parseGoDirective([]byte("module example.com/a\n")); v != "" {
+		t.Fatalf("unexpected: %s", v)
+	}
+}
+
+func TestParserGoVersion(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genbase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"go.mod": "module example.com/sample\n\ngo 1.17\n",
+		"sample.go": `package sample
+
+type List[T any] []T
+`,
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := &Parser{}
+	_, err = p.ParsePackageDir(dir)
+	if err == nil || !strings.Contains(err.Error(), "go1.18") {
+		t.Fatalf("unexpected: %v", err)
+	}
+
+	p = &Parser{GoVersion: "go1.18"}
+	pInfo, err := p.ParsePackageDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pInfo.GoVersion != "go1.18" {
+		t.Fatalf("unexpected: %s", pInfo.GoVersion)
+	}
+}
+
+func TestParseModFile(t *testing.T) {
+	mod := parseModFile([]byte("module example.com/app // main\n\n" +
+		"go 1.15\n\n" +
+		"require golang.org/x/tools v0.1.0\n\n" +
+		"require (\n" +
+		"	cloud.google.com/go v0.80.0\n" +
+		"	cloud.google.com/go/datastore v1.5.0 // indirect\n" +
+		")\n\n" +
+		"replace golang.org/x/tools => ../tools\n\n" +
+		"replace (\n" +
+		"	cloud.google.com/go v0.80.0 => example.com/fork v0.1.0\n" +
+		"	example.com/lib => ./lib\n" +
+		")\n"))
+	if mod.module != "example.com/app" {
+		t.Errorf("unexpected: %s", mod.module)
+	}
+	if v := strings.Join(mod.requires, ","); v != "golang.org/x/tools,cloud.google.com/go,cloud.google.com/go/datastore" {
+		t.Errorf("unexpected: %s", v)
+	}
+	if len(mod.replaces) != 3 || mod.replaces["golang.org/x/tools"] != "../tools" ||
+		mod.replaces["cloud.google.com/go"] != "example.com/fork v0.1.0" || mod.replaces["example.com/lib"] != "./lib" {
+		t.Errorf("unexpected: %v", mod.replaces)
+	}
+
+	for path, expected := range map[string]string{
+		"example.com/app/model":               "example.com/app",
+		"golang.org/x/tools/go/ast/astutil":   "golang.org/x/tools",
+		"cloud.google.com/go/civil":           "cloud.google.com/go",
+		"cloud.google.com/go/datastore":       "cloud.google.com/go/datastore",
+		"cloud.google.com/go/datastore/admin": "cloud.google.com/go/datastore",
+		"example.com/application":             "",
+		"example.com/lib/sub":                 "example.com/lib",
+	} {
+		if v := mod.moduleOf(path); v != expected {
+			t.Errorf("unexpected: %s %s", path, v)
+		}
+	}
+}
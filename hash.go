@@ -0,0 +1,38 @@
+package genbase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+)
+
+// ShapeHash returns stable hash of shape of type, it is same as ShapeHash of NewType(t).
+// doc comments and positions don't affect it.
+func (t *TypeInfo) ShapeHash() string {
+	return NewType(t).ShapeHash()
+}
+
+// ShapeHash returns stable hash of shape of type.
+// it is calculated from name, alias, type parameters, annotations, and names, types and tags of fields.
+func (t *Type) ShapeHash() string {
+	h := sha256.New()
+	t.writeShape(h)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (t *Type) writeShape(w io.Writer) {
+	fmt.Fprintf(w, "type %s alias=%v kind=%s\n", t.Name, t.Alias, t.Kind)
+	for _, tp := range t.TypeParams {
+		fmt.Fprintf(w, "typeparam %s %s\n", tp.Name, tp.Constraint)
+	}
+	for _, annotation := range t.Annotations {
+		fmt.Fprintf(w, "annotation %s\n", normalizeAnnotation(annotation))
+	}
+	for _, f := range t.Fields {
+		fmt.Fprintf(w, "field %s embedded=%v %s %q\n", f.Name, f.Embedded, f.Type, f.Tags.String())
+	}
+	if t.Underlying != nil {
+		fmt.Fprintf(w, "underlying %s\n", t.Underlying)
+	}
+}
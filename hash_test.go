@@ -0,0 +1,92 @@
+package genbase
+
+import (
+	"testing"
+)
+
+func TestTypeInfoShapeHash(t *testing.T) {
+	hash := func(src string) string {
+		p := &Parser{}
+		pInfo, err := p.ParseStringSource("main.go", src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pInfo.CollectTypeInfos([]string{"Sample"})[0].ShapeHash()
+	}
+
+	base := hash(`
+	package sample
+
+	// +jwg
+	type Sample struct {
+		A string ` + "`json:\"a\"`" + `
+		B int
+	}
+	`)
+	if len(base) != 64 {
+		t.Fatalf("unexpected: %s", base)
+	}
+
+	same := hash(`
+	package sample
+
+	// Sample is changed doc.
+	//   +jwg
+	type Sample struct {
+		// A is field.
+		A string ` + "`json:\"a\"`" + `
+
+		B int // B is field.
+	}
+	`)
+	if same != base {
+		t.Fatalf("unexpected: %s != %s", same, base)
+	}
+
+	for _, src := range []string{
+		"package sample\n// +jwg\ntype Sample struct {\nA string `json:\"aa\"`\nB int\n}\n",
+		"package sample\n// +jwg\ntype Sample struct {\nA string `json:\"a\"`\nB int64\n}\n",
+		"package sample\n// +jwg\ntype Sample struct {\nA string `json:\"a\"`\nC int\n}\n",
+		"package sample\n// +qbg\ntype Sample struct {\nA string `json:\"a\"`\nB int\n}\n",
+	} {
+		if v := hash(src); v == base {
+			t.Errorf("unexpected: same hash for %s", src)
+		}
+	}
+}
+
+func TestTypeShapeHash(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	// +jwg
+	// +qbg: output=a.go
+	type Sample[K comparable, V any] struct {
+		M map[K]V
+	}
+
+	// +jwg
+	// +qbg: output=a.go
+	type Other[K comparable, V ~int] struct {
+		M map[K]V
+	}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	typeInfos := pInfo.CollectTypeInfos([]string{"Sample", "Other"})
+	typ := NewType(typeInfos[0])
+	if len(typ.Annotations) != 2 || len(typ.TypeParams) != 2 || typ.TypeParams[1].Constraint != "any" {
+		t.Fatalf("unexpected: %#v", typ)
+	}
+	if v := typeInfos[0].ShapeHash(); v != typ.ShapeHash() {
+		t.Errorf("unexpected: %s != %s", v, typ.ShapeHash())
+	}
+
+	other := NewType(typeInfos[1])
+	other.Name = typ.Name
+	if other.ShapeHash() == typ.ShapeHash() {
+		t.Error("unexpected: type parameters don't affect hash")
+	}
+}
@@ -0,0 +1,121 @@
+package genbase
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// ImportInfo is import of file with resolved package name.
+type ImportInfo struct {
+	FileInfo *FileInfo
+	Spec     *ast.ImportSpec
+	Path     string // e.g. "github.com/favclip/genbase"
+	Name     string // package ident in file. "." for dot import, "_" for blank import.
+}
+
+// FindImportSpecByPath finds *ast.ImportSpec by import path.
+func (file *FileInfo) FindImportSpecByPath(path string) *ast.ImportSpec {
+	for _, imp := range file.Imports {
+		if p, err := strconv.Unquote(imp.Path.Value); err == nil && p == path {
+			return imp
+		}
+	}
+	return nil
+}
+
+// Imports returns all imports of files in package.
+func (pkg *PackageInfo) Imports() []*ImportInfo {
+	var imports []*ImportInfo
+	for _, file := range pkg.Files {
+		for _, imp := range file.Imports {
+			path, err := strconv.Unquote(imp.Path.Value)
+			if err != nil {
+				continue
+			}
+			imports = append(imports, &ImportInfo{
+				FileInfo: file,
+				Spec:     imp,
+				Path:     path,
+				Name:     pkg.importName(imp, path),
+			})
+		}
+	}
+	return imports
+}
+
+func (pkg *PackageInfo) importName(imp *ast.ImportSpec, path string) string {
+	if imp.Name != nil {
+		return imp.Name.Name
+	}
+	if pkg.TypesInfo != nil {
+		if pkgName, ok := pkg.TypesInfo.Implicits[imp].(*types.PkgName); ok {
+			return pkgName.Imported().Name()
+		}
+	}
+	return guessPackageName(path)
+}
+
+// packageName returns name of package that is imported by pkg directly or indirectly.
+// name is guessed from path if package is not found in types.
+func (pkg *PackageInfo) packageName(path string) string {
+	if pkg != nil && pkg.Types != nil {
+		if p := findImportedPackage(pkg.Types, path, make(map[*types.Package]bool)); p != nil {
+			return p.Name()
+		}
+	}
+	return guessPackageName(path)
+}
+
+// EnsureImport adds import of path to file if it is not imported yet,
+// and returns package ident to refer the package in file.
+// name is used as alias if it is not empty. alias is generated when ident collides with other imports.
+// package names are guessed from import paths, use PackageInfo.EnsureImport to resolve them by type information.
+func (file *FileInfo) EnsureImport(fs *token.FileSet, path string, name string) string {
+	return file.ensureImport(fs, path, name, guessPackageName)
+}
+
+// EnsureImport adds import of path to file in package if it is not imported yet, see FileInfo.EnsureImport.
+// package names are resolved by type information if it is available, so returned ident is real name
+// of imported package or alias that is added.
+func (pkg *PackageInfo) EnsureImport(file *FileInfo, path string, name string) string {
+	return file.ensureImport(pkg.FileSet, path, name, pkg.packageName)
+}
+
+// ensureImport is EnsureImport that packageName returns name of package of path.
+func (file *FileInfo) ensureImport(fs *token.FileSet, path string, name string, packageName func(path string) string) string {
+	if imp := file.FindImportSpecByPath(path); imp != nil && imp.Name == nil && (name == "" || name == packageName(path)) {
+		return packageName(path)
+	} else if imp != nil && imp.Name != nil && (name == "" || name == imp.Name.Name) && imp.Name.Name != "_" && imp.Name.Name != "." {
+		return imp.Name.Name
+	}
+
+	used := make(map[string]bool)
+	for _, imp := range file.Imports {
+		if imp.Name != nil {
+			used[imp.Name.Name] = true
+		} else if p, err := strconv.Unquote(imp.Path.Value); err == nil {
+			used[packageName(p)] = true
+		}
+	}
+
+	ident := name
+	if ident == "" {
+		ident = packageName(path)
+	}
+	base := ident
+	for i := 2; used[ident]; i++ {
+		ident = base + strconv.Itoa(i)
+	}
+
+	alias := ident
+	if name == "" && ident == path[strings.LastIndex(path, "/")+1:] {
+		alias = ""
+	}
+	astutil.AddNamedImport(fs, file.AstFile(), alias, path)
+	return ident
+}
@@ -0,0 +1,159 @@
+package genbase
+
+import (
+	"bytes"
+	"go/format"
+	"strings"
+	"testing"
+)
+
+func TestPackageInfoImports(t *testing.T) {
+	p := &Parser{UseExportData: true}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	import (
+		. "time"
+		str "strings"
+		_ "fmt"
+		"math/rand/v2"
+
+		"github.com/favclip/genbase/misc/fixture/pkgname"
+	)
+
+	type Sample struct {
+		At    Time
+		B     str.Builder
+		N     rand.Rand
+		Model renamed.Model
+	}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if imp := pInfo.Files[0].FindImportSpecByPath("strings"); imp == nil || imp.Name.Name != "str" {
+		t.Errorf("unexpected: %v", imp)
+	}
+	if imp := pInfo.Files[0].FindImportSpecByPath("bytes"); imp != nil {
+		t.Errorf("unexpected: %v", imp)
+	}
+
+	imports := pInfo.Imports()
+	expects := []struct {
+		path string
+		name string
+	}{
+		{"time", "."},
+		{"strings", "str"},
+		{"fmt", "_"},
+		{"math/rand/v2", "rand"},
+		{"github.com/favclip/genbase/misc/fixture/pkgname", "renamed"},
+	}
+	if len(imports) != len(expects) {
+		t.Fatalf("unexpected: %d", len(imports))
+	}
+	for i, expect := range expects {
+		if imports[i].Path != expect.path || imports[i].Name != expect.name {
+			t.Errorf("unexpected: %s %s", imports[i].Path, imports[i].Name)
+		}
+	}
+}
+
+func TestFileInfoEnsureImport(t *testing.T) {
+	p := &Parser{SkipSemanticsCheck: true}
+	pInfo, err := p.ParseStringSource("main.go", `package sample
+
+import (
+	"fmt"
+	str "strings"
+
+	"github.com/favclip/genbase"
+)
+
+var _ = fmt.Sprint
+var _ = str.Join
+var _ = genbase.NewGenerator
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := pInfo.Files[0]
+
+	expects := []struct {
+		path  string
+		name  string
+		ident string
+	}{
+		{"fmt", "", "fmt"},
+		{"strings", "", "str"},
+		{"bytes", "", "bytes"},
+		{"bytes", "", "bytes"},
+		{"example.com/fmt", "", "fmt2"},
+		{"gopkg.in/yaml.v2", "", "yaml"},
+		{"encoding/json", "js", "js"},
+	}
+	for _, expect := range expects {
+		if ident := file.EnsureImport(pInfo.FileSet, expect.path, expect.name); ident != expect.ident {
+			t.Errorf("unexpected: %s %s", expect.path, ident)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, pInfo.FileSet, file.AstFile()); err != nil {
+		t.Fatal(err)
+	}
+	expected := `package sample
+
+import (
+	"bytes"
+	js "encoding/json"
+	"fmt"
+	str "strings"
+
+	fmt2 "example.com/fmt"
+	"github.com/favclip/genbase"
+	yaml "gopkg.in/yaml.v2"
+)
+`
+	if !strings.HasPrefix(buf.String(), expected) {
+		t.Fatalf("unexpected: %s", buf.String())
+	}
+}
+
+func TestPackageInfoEnsureImport(t *testing.T) {
+	p := &Parser{UseExportData: true}
+	pInfo, err := p.ParseStringSource("main.go", `package sample
+
+import (
+	"github.com/favclip/genbase/misc/fixture/pkgname"
+)
+
+var _ renamed.Model
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := pInfo.Files[0]
+
+	expects := []struct {
+		path  string
+		ident string
+	}{
+		{"github.com/favclip/genbase/misc/fixture/pkgname", "renamed"},
+		{"example.com/renamed", "renamed2"},
+	}
+	for _, expect := range expects {
+		if ident := pInfo.EnsureImport(file, expect.path, ""); ident != expect.ident {
+			t.Errorf("unexpected: %s %s", expect.path, ident)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, pInfo.FileSet, file.AstFile()); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `renamed2 "example.com/renamed"`) {
+		t.Fatalf("unexpected: %s", buf.String())
+	}
+}
@@ -0,0 +1,204 @@
+package genbase
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Inflector converts words between singular and plural forms with override table.
+type Inflector struct {
+	plurals   map[string]string // singular to plural, in lower case
+	singulars map[string]string // plural to singular, in lower case
+}
+
+var defaultIrregulars = map[string]string{
+	"person": "people",
+	"man":    "men",
+	"woman":  "women",
+	"child":  "children",
+	"tooth":  "teeth",
+	"foot":   "feet",
+	"mouse":  "mice",
+	"goose":  "geese",
+	"ox":     "oxen",
+	"index":  "indices",
+	"matrix": "matrices",
+	"vertex": "vertices",
+	"axis":   "axes",
+	"leaf":   "leaves",
+	"life":   "lives",
+	"knife":  "knives",
+	"wife":   "wives",
+	"half":   "halves",
+	// exceptions of suffix rules.
+	"movie":     "movies",
+	"cookie":    "cookies",
+	"zombie":    "zombies",
+	"rookie":    "rookies",
+	"pie":       "pies",
+	"tie":       "ties",
+	"niche":     "niches",
+	"quiz":      "quizzes",
+	"hero":      "heroes",
+	"echo":      "echoes",
+	"potato":    "potatoes",
+	"tomato":    "tomatoes",
+	"use":       "uses",
+	"abuse":     "abuses",
+	"excuse":    "excuses",
+	"gas":       "gases",
+	"analysis":  "analyses",
+	"crisis":    "crises",
+	"thesis":    "theses",
+	"diagnosis": "diagnoses",
+}
+
+var defaultUncountables = []string{
+	"data", "metadata", "information", "equipment", "news", "series", "species", "sheep", "fish", "deer", "money", "rice",
+}
+
+// DefaultInflector is Inflector with common irregular and uncountable words.
+var DefaultInflector = NewInflector(nil)
+
+// NewInflector creates new Inflector. overrides maps singular to plural, e.g. "cactus" to "cacti".
+// uncountable word is mapped to itself.
+func NewInflector(overrides map[string]string) *Inflector {
+	inf := &Inflector{
+		plurals:   make(map[string]string),
+		singulars: make(map[string]string),
+	}
+	for _, word := range defaultUncountables {
+		inf.add(word, word)
+	}
+	for singular, plural := range defaultIrregulars {
+		inf.add(singular, plural)
+	}
+	for singular, plural := range overrides {
+		inf.add(singular, plural)
+	}
+	return inf
+}
+
+func (inf *Inflector) add(singular, plural string) {
+	singular, plural = strings.ToLower(singular), strings.ToLower(plural)
+	inf.plurals[singular] = plural
+	inf.singulars[plural] = singular
+}
+
+// Pluralize returns plural form of s by DefaultInflector. e.g. "User" to "Users", "UserCategory" to "UserCategories"
+func Pluralize(s string) string {
+	return DefaultInflector.Pluralize(s)
+}
+
+// Singularize returns singular form of s by DefaultInflector. e.g. "Users" to "User", "People" to "Person"
+func Singularize(s string) string {
+	return DefaultInflector.Singularize(s)
+}
+
+// Pluralize returns plural form of s. last word of CamelCase or snake_case name is converted, and its case is kept.
+// "s" is appended to initialism of CommonInitialisms in CamelCase name. e.g. "UserID" to "UserIDs", "USER_ID" to "USER_IDS"
+func (inf *Inflector) Pluralize(s string) string {
+	words := SplitWords(s)
+	if len(words) == 0 {
+		return s
+	}
+	if last := words[len(words)-1]; DefaultCaseConverter.initialisms[last] && (len(words) == 1 || s != strings.ToUpper(s)) {
+		return s + "s"
+	}
+	return inflectLastWord(s, words, func(word string) string {
+		if plural, ok := inf.plurals[word]; ok {
+			return plural
+		}
+		if _, ok := inf.singulars[word]; ok {
+			return word
+		}
+		return pluralize(word)
+	})
+}
+
+// Singularize returns singular form of s. last word of CamelCase or snake_case name is converted, and its case is kept.
+// "s" is removed from initialism. e.g. "UserIDs" to "UserID"
+func (inf *Inflector) Singularize(s string) string {
+	if rs := []rune(s); len(rs) > 2 && rs[len(rs)-1] == 's' && unicode.IsUpper(rs[len(rs)-2]) && unicode.IsUpper(rs[len(rs)-3]) {
+		return string(rs[:len(rs)-1])
+	}
+	words := SplitWords(s)
+	if len(words) == 0 {
+		return s
+	}
+	return inflectLastWord(s, words, func(word string) string {
+		if singular, ok := inf.singulars[word]; ok {
+			return singular
+		}
+		if _, ok := inf.plurals[word]; ok {
+			return word
+		}
+		return singularize(word)
+	})
+}
+
+// inflectLastWord applies f to last word of s in lower case, and restores case of the word.
+func inflectLastWord(s string, words []string, f func(word string) string) string {
+	last := words[len(words)-1]
+	idx := strings.LastIndex(s, last)
+	inflected := f(strings.ToLower(last))
+	if last == strings.ToUpper(last) {
+		inflected = strings.ToUpper(inflected)
+	} else if unicode.IsUpper([]rune(last)[0]) {
+		inflected = upperFirst(inflected)
+	}
+	return s[:idx] + inflected + s[idx+len(last):]
+}
+
+func pluralize(word string) string {
+	switch {
+	case hasAnySuffix(word, "s", "x", "z", "ch", "sh"):
+		return word + "es"
+	case strings.HasSuffix(word, "y") && len(word) > 1 && !isVowel(word[len(word)-2]):
+		return word[:len(word)-1] + "ies"
+	}
+	return word + "s"
+}
+
+func singularize(word string) string {
+	switch {
+	case strings.HasSuffix(word, "ies") && len(word) > 4:
+		return word[:len(word)-3] + "y"
+	case hasAnySuffix(word, "sses", "xes", "shes", "zzes"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(word, "ches"):
+		// "matches" and "beaches" are from "-ch", "caches" is from "-che".
+		if stem := word[:len(word)-4]; stem != "" && isVowel(stem[len(stem)-1]) && (len(stem) < 2 || !isVowel(stem[len(stem)-2])) && stem[len(stem)-1] != 'i' {
+			return word[:len(word)-1]
+		}
+		return word[:len(word)-2]
+	case strings.HasSuffix(word, "uses"):
+		// "statuses" is from "-us", "causes" and "houses" are from "-use".
+		if stem := word[:len(word)-4]; stem != "" && isVowel(stem[len(stem)-1]) {
+			return word[:len(word)-1]
+		}
+		return word[:len(word)-2]
+	case strings.HasSuffix(word, "iases"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(word, "zes") && len(word) > 3 && !isVowel(word[len(word)-4]):
+		return word[:len(word)-2]
+	case strings.HasSuffix(word, "ss"), strings.HasSuffix(word, "us"):
+		return word
+	case strings.HasSuffix(word, "s"):
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+func hasAnySuffix(s string, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isVowel(b byte) bool {
+	return strings.IndexByte("aeiou", b) != -1
+}
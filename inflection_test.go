@@ -0,0 +1,94 @@
+package genbase
+
+import (
+	"testing"
+)
+
+func TestPluralize(t *testing.T) {
+	expects := map[string]string{
+		"User":         "Users",
+		"UserCategory": "UserCategories",
+		"Box":          "Boxes",
+		"Status":       "Statuses",
+		"Key":          "Keys",
+		"Person":       "People",
+		"child":        "children",
+		"user_address": "user_addresses",
+		"ItemData":     "ItemData",
+		"UserID":       "UserIDs",
+		"ID":           "IDs",
+		"USER_ID":      "USER_IDS",
+		"MAX_VALUE":    "MAX_VALUES",
+		"HTTP_SERVER":  "HTTP_SERVERS",
+		"user_api":     "user_apis",
+		"Case":         "Cases",
+		"Cache":        "Caches",
+		"Movie":        "Movies",
+		"Quiz":         "Quizzes",
+		"Hero":         "Heroes",
+		"Match":        "Matches",
+		"":             "",
+	}
+	for s, expect := range expects {
+		if v := Pluralize(s); v != expect {
+			t.Errorf("unexpected: %s %s", s, v)
+		}
+	}
+}
+
+func TestSingularize(t *testing.T) {
+	expects := map[string]string{
+		"Users":          "User",
+		"UserCategories": "UserCategory",
+		"Boxes":          "Box",
+		"Statuses":       "Status",
+		"Status":         "Status",
+		"People":         "Person",
+		"children":       "child",
+		"ItemData":       "ItemData",
+		"UserIDs":        "UserID",
+		"USER_IDS":       "USER_ID",
+		"MAX_VALUES":     "MAX_VALUE",
+		"User":           "User",
+		"Cases":          "Case",
+		"Responses":      "Response",
+		"Databases":      "Database",
+		"Caches":         "Cache",
+		"Movies":         "Movie",
+		"Matches":        "Match",
+		"Beaches":        "Beach",
+		"Sandwiches":     "Sandwich",
+		"Niches":         "Niche",
+		"Addresses":      "Address",
+		"Buses":          "Bus",
+		"Causes":         "Cause",
+		"Houses":         "House",
+		"Aliases":        "Alias",
+		"Sizes":          "Size",
+		"Waltzes":        "Waltz",
+		"Quizzes":        "Quiz",
+		"Wishes":         "Wish",
+		"Heroes":         "Hero",
+		"Analyses":       "Analysis",
+		"Cookies":        "Cookie",
+		"Licenses":       "License",
+		"Phases":         "Phase",
+		"Axis":           "Axis",
+	}
+	for s, expect := range expects {
+		if v := Singularize(s); v != expect {
+			t.Errorf("unexpected: %s %s", s, v)
+		}
+	}
+
+	inf := NewInflector(map[string]string{"cactus": "cacti", "staff": "staff"})
+	if v := inf.Pluralize("Cactus"); v != "Cacti" {
+		t.Fatalf("unexpected: %s", v)
+	}
+	if v := inf.Singularize("Cacti"); v != "Cactus" {
+		t.Fatalf("unexpected: %s", v)
+	}
+	if v := inf.Pluralize("Staff"); v != "Staff" {
+		t.Fatalf("unexpected: %s", v)
+	}
+}
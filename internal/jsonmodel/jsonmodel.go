@@ -0,0 +1,146 @@
+// Package jsonmodel maps genbase.Model to JSON encoding, it is shared by jsonschema and openapi.
+//
+// json struct tags are honored the same way as encoding/json does.
+package jsonmodel
+
+import (
+	"strings"
+
+	"github.com/favclip/genbase"
+)
+
+// Property is field encoded as property of JSON object.
+type Property struct {
+	Name     string // name in JSON
+	Field    *genbase.Field
+	Required bool // field is not pointer and has no omitempty option
+	String   bool // field has string option
+}
+
+// Scalar returns JSON type and format of ref if it is encoded as scalar.
+// typ is empty for any value, e.g. error and interface{}. returns false if ref is not scalar.
+func Scalar(ref *genbase.TypeRef) (typ, format string, ok bool) {
+	switch ref.Kind {
+	case genbase.KindBasic:
+		return basic(ref.Name)
+	case genbase.KindNamed:
+		switch {
+		case ref.Package == "time" && ref.Name == "Time":
+			return "string", "date-time", true
+		case ref.Package == "time" && ref.Name == "Duration":
+			return "integer", "int64", true
+		}
+	case genbase.KindSlice:
+		if ref.Elem.Kind == genbase.KindBasic && (ref.Elem.Name == "byte" || ref.Elem.Name == "uint8") {
+			return "string", "byte", true
+		}
+	}
+	return "", "", false
+}
+
+func basic(name string) (typ, format string, ok bool) {
+	switch name {
+	case "string":
+		return "string", "", true
+	case "bool":
+		return "boolean", "", true
+	case "int", "int8", "int16", "uint", "uint8", "uint16", "byte", "uintptr":
+		return "integer", "", true
+	case "int32", "uint32", "rune":
+		return "integer", "int32", true
+	case "int64", "uint64":
+		return "integer", "int64", true
+	case "float32":
+		return "number", "float", true
+	case "float64":
+		return "number", "double", true
+	case "error", "any":
+		return "", "", true
+	}
+	return "", "", false
+}
+
+// Scope is named types of package, keyed by type name.
+type Scope map[string]*genbase.Type
+
+// NewScope creates Scope from types.
+func NewScope(types []*genbase.Type) Scope {
+	s := make(Scope, len(types))
+	for _, t := range types {
+		s[t.Name] = t
+	}
+	return s
+}
+
+// Promoted reports whether fields of embedded field of ref are promoted, i.e. ref is struct or pointer to struct.
+// named type that is not in s, e.g. of other package, is assumed as struct.
+func (s Scope) Promoted(ref *genbase.TypeRef) bool {
+	if ref.Kind == genbase.KindPointer {
+		ref = ref.Elem
+	}
+	seen := make(map[string]bool)
+	for ref.Kind == genbase.KindNamed && ref.Package == "" && !seen[ref.Name] {
+		seen[ref.Name] = true
+		t, ok := s[ref.Name]
+		if !ok {
+			return true
+		}
+		if t.Kind == genbase.KindStruct {
+			return true
+		}
+		ref = t.Underlying
+	}
+	return ref.Kind == genbase.KindStruct || ref.Kind == genbase.KindNamed
+}
+
+// Properties returns properties of fields, and embedded fields that have no json name and are promoted.
+// fields of promoted fields are properties of them, so they are not properties.
+func (s Scope) Properties(fields []*genbase.Field) ([]*Property, []*genbase.Field) {
+	var props []*Property
+	var embedded []*genbase.Field
+	for _, f := range fields {
+		tag, _ := f.Tags.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts := genbase.SplitTagValue(tag)
+		if f.Embedded && name == "" && s.Promoted(f.Type) {
+			embedded = append(embedded, f)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props = append(props, &Property{
+			Name:     name,
+			Field:    f,
+			Required: f.Type.Kind != genbase.KindPointer && !hasOption(opts, "omitempty"),
+			String:   hasOption(opts, "string"),
+		})
+	}
+	return props, embedded
+}
+
+// Description returns doc without annotation lines.
+func Description(doc string) string {
+	return strings.TrimSpace(genbase.StripDirectives(doc))
+}
+
+// Describe returns description of doc for schema that has ref.
+// wrap is true if schema must be wrapped by allOf to have description, because siblings of $ref are ignored.
+func Describe(ref, doc string) (desc string, wrap bool) {
+	desc = Description(doc)
+	return desc, desc != "" && ref != ""
+}
+
+func hasOption(opts []string, opt string) bool {
+	for _, o := range opts {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
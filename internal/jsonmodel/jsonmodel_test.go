@@ -0,0 +1,108 @@
+package jsonmodel
+
+import (
+	"testing"
+
+	"github.com/favclip/genbase"
+)
+
+func TestScalar(t *testing.T) {
+	for _, c := range []struct {
+		ref    *genbase.TypeRef
+		typ    string
+		format string
+		ok     bool
+	}{
+		{&genbase.TypeRef{Kind: genbase.KindBasic, Name: "int64"}, "integer", "int64", true},
+		{&genbase.TypeRef{Kind: genbase.KindBasic, Name: "any"}, "", "", true},
+		{&genbase.TypeRef{Kind: genbase.KindBasic, Name: "complex64"}, "", "", false},
+		{&genbase.TypeRef{Kind: genbase.KindNamed, Package: "time", Name: "Time"}, "string", "date-time", true},
+		{&genbase.TypeRef{Kind: genbase.KindSlice, Elem: &genbase.TypeRef{Kind: genbase.KindBasic, Name: "byte"}}, "string", "byte", true},
+		{&genbase.TypeRef{Kind: genbase.KindSlice, Elem: &genbase.TypeRef{Kind: genbase.KindBasic, Name: "int"}}, "", "", false},
+	} {
+		typ, format, ok := Scalar(c.ref)
+		if typ != c.typ || format != c.format || ok != c.ok {
+			t.Errorf("unexpected: %+v %s %s %v", c.ref, typ, format, ok)
+		}
+	}
+}
+
+func TestProperties(t *testing.T) {
+	str := &genbase.TypeRef{Kind: genbase.KindBasic, Name: "string"}
+	fields := []*genbase.Field{
+		{Name: "Base", Embedded: true, Type: &genbase.TypeRef{Kind: genbase.KindNamed, Name: "Base"}},
+		{Name: "Name", Type: str, Tags: genbase.Tags{{Key: "json", Value: "name"}}},
+		{Name: "Nick", Type: &genbase.TypeRef{Kind: genbase.KindPointer, Elem: str}},
+		{Name: "ID", Type: str, Tags: genbase.Tags{{Key: "json", Value: ",omitempty,string"}}},
+		{Name: "Ignored", Type: str, Tags: genbase.Tags{{Key: "json", Value: "-"}}},
+		{Name: "private", Type: str},
+		{Name: "Tags", Embedded: true, Type: &genbase.TypeRef{Kind: genbase.KindNamed, Name: "Tags"}},
+	}
+	scope := NewScope([]*genbase.Type{
+		{Name: "Base", Kind: genbase.KindStruct},
+		{Name: "Tags", Kind: genbase.KindSlice, Underlying: &genbase.TypeRef{Kind: genbase.KindSlice, Elem: str}},
+	})
+	props, embedded := scope.Properties(fields)
+	if len(embedded) != 1 || embedded[0].Name != "Base" {
+		t.Errorf("unexpected: %v", embedded)
+	}
+	if len(props) != 4 {
+		t.Fatalf("unexpected: %d", len(props))
+	}
+	if p := props[0]; p.Name != "name" || !p.Required || p.String {
+		t.Errorf("unexpected: %+v", p)
+	}
+	if p := props[1]; p.Name != "Nick" || p.Required {
+		t.Errorf("unexpected: %+v", p)
+	}
+	if p := props[2]; p.Name != "ID" || p.Required || !p.String {
+		t.Errorf("unexpected: %+v", p)
+	}
+	if p := props[3]; p.Name != "Tags" || !p.Required {
+		t.Errorf("unexpected: %+v", p)
+	}
+}
+
+func TestScopePromoted(t *testing.T) {
+	named := func(pkg, name string) *genbase.TypeRef {
+		return &genbase.TypeRef{Kind: genbase.KindNamed, Package: pkg, Name: name}
+	}
+	scope := NewScope([]*genbase.Type{
+		{Name: "Base", Kind: genbase.KindStruct},
+		{Name: "Derived", Kind: genbase.KindNamed, Underlying: named("", "Base")},
+		{Name: "Tags", Kind: genbase.KindSlice, Underlying: &genbase.TypeRef{Kind: genbase.KindSlice, Elem: named("", "string")}},
+	})
+	for _, c := range []struct {
+		ref      *genbase.TypeRef
+		promoted bool
+	}{
+		{named("", "Base"), true},
+		{&genbase.TypeRef{Kind: genbase.KindPointer, Elem: named("", "Base")}, true},
+		{named("", "Derived"), true},
+		{named("", "Tags"), false},
+		{&genbase.TypeRef{Kind: genbase.KindPointer, Elem: named("", "Tags")}, false},
+		{named("", "Unknown"), true},
+		{named("sql", "NullString"), true},
+	} {
+		if promoted := scope.Promoted(c.ref); promoted != c.promoted {
+			t.Errorf("unexpected: %+v %v", c.ref, promoted)
+		}
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	for _, c := range []struct {
+		ref  string
+		doc  string
+		desc string
+		wrap bool
+	}{
+		{"", "Name is name.\n", "Name is name.", false},
+		{"#/definitions/Base", "Base is base.\n", "Base is base.", true},
+		{"#/definitions/Base", "+sample\n", "", false},
+	} {
+		if desc, wrap := Describe(c.ref, c.doc); desc != c.desc || wrap != c.wrap {
+			t.Errorf("unexpected: %+v %q %v", c, desc, wrap)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package genbase
+
+// TypeInfoSeq is iterator of TypeInfo. it is compatible with iter.Seq[*TypeInfo].
+type TypeInfoSeq func(yield func(*TypeInfo) bool)
+
+// FieldInfoSeq is iterator of FieldInfo. it is compatible with iter.Seq[*FieldInfo].
+type FieldInfoSeq func(yield func(*FieldInfo) bool)
+
+// AllTypes returns iterator of all types in package.
+// TypeInfos are created lazily, so iteration can be stopped without walking rest of files.
+func (pkg *PackageInfo) AllTypes() TypeInfoSeq {
+	return func(yield func(*TypeInfo) bool) {
+		pkg.eachTypeInfo(yield)
+	}
+}
+
+// AllTaggedTypes returns iterator of types tagged by tag. see CollectTaggedTypeInfos.
+func (pkg *PackageInfo) AllTaggedTypes(tag string) TypeInfoSeq {
+	return pkg.AllTypes().Filter(func(t *TypeInfo) bool {
+		for _, doc := range t.Comments() {
+			if c := findAnnotation(doc, tag); c != nil {
+				t.AnnotatedComment = c
+				t.setAnnotations(tag)
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Filter returns iterator of types that f returns true.
+func (seq TypeInfoSeq) Filter(f func(*TypeInfo) bool) TypeInfoSeq {
+	return func(yield func(*TypeInfo) bool) {
+		seq(func(t *TypeInfo) bool {
+			return !f(t) || yield(t)
+		})
+	}
+}
+
+// Collect returns all types of iterator.
+func (seq TypeInfoSeq) Collect() TypeInfos {
+	ret := TypeInfos{}
+	seq(func(t *TypeInfo) bool {
+		ret = append(ret, t)
+		return true
+	})
+	return ret
+}
+
+// AllFields returns iterator of fields of struct.
+func (st *StructTypeInfo) AllFields() FieldInfoSeq {
+	return func(yield func(*FieldInfo) bool) {
+		for _, field := range st.AstStructType().Fields.List {
+			if !yield((*FieldInfo)(field)) {
+				return
+			}
+		}
+	}
+}
+
+// Filter returns iterator of fields that f returns true.
+func (seq FieldInfoSeq) Filter(f func(*FieldInfo) bool) FieldInfoSeq {
+	return func(yield func(*FieldInfo) bool) {
+		seq(func(field *FieldInfo) bool {
+			return !f(field) || yield(field)
+		})
+	}
+}
+
+// Collect returns all fields of iterator.
+func (seq FieldInfoSeq) Collect() FieldInfos {
+	var ret FieldInfos
+	seq(func(field *FieldInfo) bool {
+		ret = append(ret, field)
+		return true
+	})
+	return ret
+}
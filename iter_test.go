@@ -0,0 +1,53 @@
+package genbase
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPackageInfoAllTypes(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	// +jwg
+	type Sample struct {
+		A string
+		B int
+		C string
+	}
+
+	type Other int
+
+	// +jwg
+	type Last struct{}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	pInfo.AllTypes()(func(t *TypeInfo) bool {
+		names = append(names, t.Name())
+		return len(names) < 2
+	})
+	if v := strings.Join(names, ","); v != "Sample,Other" {
+		t.Errorf("unexpected: %s", v)
+	}
+
+	tagged := pInfo.AllTaggedTypes("+jwg").Collect()
+	if len(tagged) != 2 || tagged[0].Name() != "Sample" || tagged[1].Name() != "Last" || tagged[1].AnnotatedComment == nil {
+		t.Errorf("unexpected: %v", tagged)
+	}
+
+	st, err := tagged[0].StructType()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := st.AllFields().Filter(func(f *FieldInfo) bool {
+		return f.IsString()
+	}).Collect()
+	if len(fields) != 2 || fields[0].Names[0].Name != "A" || fields[1].Names[0].Name != "C" {
+		t.Errorf("unexpected: %v", fields)
+	}
+}
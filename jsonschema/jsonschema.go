@@ -0,0 +1,146 @@
+// Package jsonschema derives JSON Schema documents from structs parsed by genbase.
+//
+// json struct tags are honored the same way as encoding/json does.
+// named types of the same package are referenced as "#/definitions/Name".
+package jsonschema
+
+import (
+	"github.com/favclip/genbase"
+	"github.com/favclip/genbase/internal/jsonmodel"
+)
+
+// Draft is JSON Schema version of generated document.
+const Draft = "http://json-schema.org/draft-07/schema#"
+
+// Schema is JSON Schema.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	Definitions          map[string]*Schema `json:"definitions,omitempty"`
+}
+
+// FromStructType creates object Schema from StructTypeInfo.
+func FromStructType(st *genbase.StructTypeInfo) *Schema {
+	return fromFields(genbase.NewFields(st), nil)
+}
+
+// FromTypeInfos creates Schema document that has definitions of each TypeInfos.
+func FromTypeInfos(typeInfos genbase.TypeInfos) *Schema {
+	doc := &Schema{
+		Schema:      Draft,
+		Definitions: make(map[string]*Schema),
+	}
+	ts := make([]*genbase.Type, 0, len(typeInfos))
+	for _, t := range typeInfos {
+		ts = append(ts, genbase.NewType(t))
+	}
+	scope := jsonmodel.NewScope(ts)
+	for _, t := range ts {
+		doc.Definitions[t.Name] = fromType(t, scope)
+	}
+	return doc
+}
+
+// FromType creates Schema from genbase.Type.
+func FromType(t *genbase.Type) *Schema {
+	return fromType(t, nil)
+}
+
+func fromType(t *genbase.Type, scope jsonmodel.Scope) *Schema {
+	var s *Schema
+	if t.Kind == genbase.KindStruct {
+		s = fromFields(t.Fields, scope)
+	} else {
+		s = fromTypeRef(t.Underlying, scope)
+		if s == nil {
+			s = &Schema{}
+		}
+	}
+	return describe(s, t.Doc)
+}
+
+// FromTypeRef creates Schema from genbase.TypeRef.
+// returns nil if type can't be encoded to JSON.
+func FromTypeRef(ref *genbase.TypeRef) *Schema {
+	return fromTypeRef(ref, nil)
+}
+
+func fromTypeRef(ref *genbase.TypeRef, scope jsonmodel.Scope) *Schema {
+	if typ, format, ok := jsonmodel.Scalar(ref); ok {
+		return &Schema{Type: typ, Format: format}
+	}
+	switch ref.Kind {
+	case genbase.KindNamed:
+		if ref.Package == "" {
+			return &Schema{Ref: "#/definitions/" + ref.Name}
+		}
+		return &Schema{}
+	case genbase.KindPointer:
+		return fromTypeRef(ref.Elem, scope)
+	case genbase.KindSlice, genbase.KindArray:
+		items := fromTypeRef(ref.Elem, scope)
+		if items == nil {
+			return nil
+		}
+		return &Schema{Type: "array", Items: items}
+	case genbase.KindMap:
+		elem := fromTypeRef(ref.Elem, scope)
+		if elem == nil {
+			return nil
+		}
+		return &Schema{Type: "object", AdditionalProperties: elem}
+	case genbase.KindStruct:
+		return fromFields(ref.Fields, scope)
+	case genbase.KindInterface:
+		return &Schema{}
+	}
+	return nil
+}
+
+func fromFields(fields []*genbase.Field, scope jsonmodel.Scope) *Schema {
+	s := &Schema{
+		Type:       "object",
+		Properties: make(map[string]*Schema),
+	}
+	props, embedded := scope.Properties(fields)
+	for _, f := range embedded {
+		if e := fromTypeRef(f.Type, scope); e != nil && e.Ref != "" {
+			s.AllOf = append(s.AllOf, e)
+		}
+	}
+	for _, p := range props {
+		prop := fromTypeRef(p.Field.Type, scope)
+		if prop == nil {
+			continue
+		}
+		if p.String {
+			prop = &Schema{Type: "string"}
+		}
+		s.Properties[p.Name] = describe(prop, p.Field.Doc)
+		if p.Required {
+			s.Required = append(s.Required, p.Name)
+		}
+	}
+	return s
+}
+
+// describe sets description of doc to s. $ref is wrapped by allOf, because siblings of $ref are ignored.
+func describe(s *Schema, doc string) *Schema {
+	desc, wrap := jsonmodel.Describe(s.Ref, doc)
+	if desc == "" {
+		return s
+	}
+	if wrap {
+		s = &Schema{AllOf: []*Schema{s}}
+	}
+	s.Description = desc
+	return s
+}
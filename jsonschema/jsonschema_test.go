@@ -0,0 +1,57 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/favclip/genbase"
+)
+
+func TestFromTypeInfos(t *testing.T) {
+	p := &genbase.Parser{}
+	pInfo, err := p.ParseStringSource("main.go", "package sample\n"+
+		"import \"time\"\n"+
+		"// User is user.\n"+
+		"// +sample\n"+
+		"type User struct {\n"+
+		"	Base\n"+
+		"	Name      string `json:\"name\"`\n"+
+		"	Nick      *string `json:\"nick\"`\n"+
+		"	Age       int64 `json:\",omitempty\"`\n"+
+		"	Tags      []string\n"+
+		"	// Parent is parent of user.\n"+
+		"	Parent    *Base\n"+
+		"	Raw       []byte `json:\"raw,omitempty\"`\n"+
+		"	CreatedAt time.Time `json:\"createdAt\"`\n"+
+		"	Ignored   string `json:\"-\"`\n"+
+		"	private   string\n"+
+		"}\n"+
+		"// +sample\n"+
+		"type Base struct {\n"+
+		"	ID int `json:\"id,string\"`\n"+
+		"}\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc := FromTypeInfos(pInfo.CollectTaggedTypeInfos("+sample"))
+	b, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"$schema":"http://json-schema.org/draft-07/schema#","definitions":{` +
+		`"Base":{"type":"object","properties":{"id":{"type":"string"}},"required":["id"]},` +
+		`"User":{"type":"object","description":"User is user.","properties":{` +
+		`"Age":{"type":"integer","format":"int64"},` +
+		`"Parent":{"description":"Parent is parent of user.","allOf":[{"$ref":"#/definitions/Base"}]},` +
+		`"Tags":{"type":"array","items":{"type":"string"}},` +
+		`"createdAt":{"type":"string","format":"date-time"},` +
+		`"name":{"type":"string"},` +
+		`"nick":{"type":"string"},` +
+		`"raw":{"type":"string","format":"byte"}},` +
+		`"required":["name","Tags","createdAt"],"allOf":[{"$ref":"#/definitions/Base"}]}}}`
+	if string(b) != expected {
+		t.Fatalf("unexpected: %s", string(b))
+	}
+}
@@ -0,0 +1,76 @@
+package genbase
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// LicenseHeader is license or copyright header of generated code, it is printed before generated-code marker.
+// Text is text/template with Year and Owner. e.g. "Copyright {{.Year}} {{.Owner}}. All rights reserved."
+// lines of Text are commented by "// " unless Text is already comment.
+type LicenseHeader struct {
+	Text  string
+	Owner string
+	Year  int // current year is used if it is 0
+}
+
+// LoadLicenseHeader reads Text of LicenseHeader from file.
+func LoadLicenseHeader(fileName string, owner string) (*LicenseHeader, error) {
+	b, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("reading license header: %s", err)
+	}
+	return &LicenseHeader{Text: string(b), Owner: owner}, nil
+}
+
+// Render returns comment lines of header that end with newline.
+func (h *LicenseHeader) Render() (string, error) {
+	data := *h
+	if data.Year == 0 {
+		data.Year = time.Now().Year()
+	}
+	tmpl, err := template.New("license").Option("missingkey=error").Parse(h.Text)
+	if err != nil {
+		return "", fmt.Errorf("parsing license header: %s", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, &data); err != nil {
+		return "", fmt.Errorf("rendering license header: %s", err)
+	}
+	text := strings.TrimRight(buf.String(), " \t\r\n")
+	if text == "" {
+		return "", nil
+	}
+	if trimmed := strings.TrimLeft(text, " \t\r\n"); strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "/*") {
+		return trimmed + "\n", nil
+	}
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			lines = append(lines, "//")
+		} else {
+			lines = append(lines, "// "+line)
+		}
+	}
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// SetLicenseHeader sets license header that PrintHeader prints before generated-code marker.
+// nil header removes license header.
+func (g *Generator) SetLicenseHeader(h *LicenseHeader) error {
+	if h == nil {
+		g.licenseHeader = ""
+		return nil
+	}
+	header, err := h.Render()
+	if err != nil {
+		return err
+	}
+	g.licenseHeader = header
+	return nil
+}
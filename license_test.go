@@ -0,0 +1,75 @@
+package genbase
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLicenseHeaderRender(t *testing.T) {
+	h := &LicenseHeader{Text: "Copyright {{.Year}} {{.Owner}}.\n\nLicensed under the MIT License.\n", Owner: "favclip", Year: 2020}
+	v, err := h.Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "// Copyright 2020 favclip.\n//\n// Licensed under the MIT License.\n" {
+		t.Errorf("unexpected: %q", v)
+	}
+
+	h = &LicenseHeader{Text: "/*\nCopyright {{.Year}} {{.Owner}}\n*/\n", Owner: "favclip", Year: 2021}
+	v, err = h.Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "/*\nCopyright 2021 favclip\n*/\n" {
+		t.Errorf("unexpected: %q", v)
+	}
+
+	h = &LicenseHeader{Text: "Copyright {{.Author}}"}
+	if _, err := h.Render(); err == nil {
+		t.Errorf("unexpected: %v", err)
+	}
+}
+
+func TestGeneratorSetLicenseHeader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "license")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileName := filepath.Join(dir, "LICENSE_HEADER")
+	if err := ioutil.WriteFile(fileName, []byte("Copyright {{.Year}} {{.Owner}}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	h, err := LoadLicenseHeader(fileName, "favclip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Year = 2020
+
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", "package sample\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGenerator(pInfo)
+	if err := g.SetLicenseHeader(h); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.SetBuildConstraint("linux", false); err != nil {
+		t.Fatal(err)
+	}
+	g.PrintHeader("sample", &[]string{"-type", "Sample"})
+	src, err := g.Format()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := "// Copyright 2020 favclip\n\n// Code generated by sample -type Sample; DO NOT EDIT\n\n//go:build linux\n\npackage sample\n"
+	if !strings.HasPrefix(string(src), expect) {
+		t.Errorf("unexpected: %s", src)
+	}
+
+	if _, err := LoadLicenseHeader(filepath.Join(dir, "missing"), "favclip"); err == nil {
+		t.Errorf("unexpected: %v", err)
+	}
+}
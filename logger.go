@@ -0,0 +1,78 @@
+package genbase
+
+import (
+	"log"
+)
+
+// Logger receives diagnostic messages of Parser and Generator.
+// messages are formatted by fmt.Sprintf and not terminated by newline.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+}
+
+// NopLogger discards all messages, it is used when Logger is not specified.
+var NopLogger Logger = nopLogger{}
+
+type nopLogger struct{}
+
+func (nopLogger) Debugf(format string, args ...interface{}) {}
+func (nopLogger) Infof(format string, args ...interface{})  {}
+func (nopLogger) Warnf(format string, args ...interface{})  {}
+
+// NewStdLogger returns Logger that writes messages to l with level prefix.
+// verbosity is number of -v flags: 0 shows warnings only, 1 shows infos too, and 2 or more shows debug messages too.
+func NewStdLogger(l *log.Logger, verbosity int) Logger {
+	if l == nil {
+		l = log.New(log.Writer(), log.Prefix(), log.Flags())
+	}
+	return &stdLogger{l: l, verbosity: verbosity}
+}
+
+type stdLogger struct {
+	l         *log.Logger
+	verbosity int
+}
+
+func (s *stdLogger) Debugf(format string, args ...interface{}) {
+	if s.verbosity >= 2 {
+		s.l.Printf("debug: "+format, args...)
+	}
+}
+
+func (s *stdLogger) Infof(format string, args ...interface{}) {
+	if s.verbosity >= 1 {
+		s.l.Printf("info: "+format, args...)
+	}
+}
+
+func (s *stdLogger) Warnf(format string, args ...interface{}) {
+	s.l.Printf("warn: "+format, args...)
+}
+
+// logger returns Logger of Parser, or NopLogger.
+func (p *Parser) logger() Logger {
+	if p.Logger == nil {
+		return NopLogger
+	}
+	return p.Logger
+}
+
+// logSkippedFiles reports skipped files.
+func (p *Parser) logSkippedFiles(skipped []*SkippedFile) {
+	for _, f := range skipped {
+		p.logger().Debugf("skipped %s: %s", f.Name, f.Reason)
+	}
+}
+
+// logger returns Logger of Generator, Logger of Parser that parsed Package is used if it is nil.
+func (g *Generator) logger() Logger {
+	switch {
+	case g.Logger != nil:
+		return g.Logger
+	case g.Package != nil && g.Package.logger != nil:
+		return g.Package.logger
+	}
+	return NopLogger
+}
@@ -0,0 +1,80 @@
+package genbase
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+)
+
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) Debugf(format string, args ...interface{}) {
+	l.messages = append(l.messages, "debug: "+fmt.Sprintf(format, args...))
+}
+
+func (l *recordingLogger) Infof(format string, args ...interface{}) {
+	l.messages = append(l.messages, "info: "+fmt.Sprintf(format, args...))
+}
+
+func (l *recordingLogger) Warnf(format string, args ...interface{}) {
+	l.messages = append(l.messages, "warn: "+fmt.Sprintf(format, args...))
+}
+
+func TestParserLogger(t *testing.T) {
+	logger := &recordingLogger{}
+	p := &Parser{SkipSemanticsCheck: true, Logger: logger}
+	pInfo, err := p.ParseStringSource("main.go", "package sample\n"+
+		"type Sample struct {\n"+
+		"	A Unknown\n"+
+		"}\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "debug: parsing main.go\n" +
+		"warn: types of . are not resolved: main.go:3:4: undefined: Unknown\n" +
+		"info: parsed package sample in .: 1 files"
+	if v := strings.Join(logger.messages, "\n"); v != expected {
+		t.Errorf("unexpected: %s", v)
+	}
+
+	logger.messages = nil
+	g := NewGenerator(pInfo)
+	g.AddImport("time", "")
+	g.Printf("package sample\nfunc {")
+	if _, err := g.Format(); err == nil {
+		t.Fatal("unexpected: no error")
+	}
+	if len(logger.messages) != 2 || logger.messages[0] != `debug: required import "time"` || !strings.HasPrefix(logger.messages[1], "warn: formatting generated code of sample: ") {
+		t.Errorf("unexpected: %v", logger.messages)
+	}
+
+	own := &recordingLogger{}
+	g = NewGenerator(pInfo)
+	g.Logger = own
+	g.AddImport("time", "t")
+	if len(own.messages) != 1 || len(logger.messages) != 2 {
+		t.Errorf("unexpected: %v %v", own.messages, logger.messages)
+	}
+}
+
+func TestNewStdLogger(t *testing.T) {
+	for verbosity, expected := range []string{
+		"warn: c\n",
+		"info: b\nwarn: c\n",
+		"debug: a\ninfo: b\nwarn: c\n",
+	} {
+		var buf bytes.Buffer
+		logger := NewStdLogger(log.New(&buf, "", 0), verbosity)
+		logger.Debugf("%s", "a")
+		logger.Infof("%s", "b")
+		logger.Warnf("%s", "c")
+		if v := buf.String(); v != expected {
+			t.Errorf("unexpected: %d %s", verbosity, v)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package genbase
+
+// ByName returns map of type name to TypeInfo.
+// if types have same name, first one is used.
+func (types TypeInfos) ByName() map[string]*TypeInfo {
+	m := make(map[string]*TypeInfo, len(types))
+	for _, t := range types {
+		if _, ok := m[t.Name()]; !ok {
+			m[t.Name()] = t
+		}
+	}
+	return m
+}
+
+// ByName returns map of field name to FieldInfo.
+// field that has multiple names is mapped by each name, and embedded field is mapped by its type name.
+// if fields have same name, first one is used.
+func (fields FieldInfos) ByName() map[string]*FieldInfo {
+	m := make(map[string]*FieldInfo, len(fields))
+	for _, f := range fields {
+		names := []string{f.Name()}
+		if len(f.Names) > 1 {
+			names = names[:0]
+			for _, name := range f.Names {
+				names = append(names, name.Name)
+			}
+		}
+		for _, name := range names {
+			if _, ok := m[name]; !ok {
+				m[name] = f
+			}
+		}
+	}
+	return m
+}
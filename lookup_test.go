@@ -0,0 +1,45 @@
+package genbase
+
+import (
+	"testing"
+)
+
+func TestTypeInfosByName(t *testing.T) {
+	p := &Parser{SkipSemanticsCheck: true}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	type Sample struct {
+		A, B string
+		C    int
+		*Base
+		C    string
+	}
+
+	type Base struct{}
+
+	type Base int
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	types := pInfo.TypeInfos()
+	byName := types.ByName()
+	if len(byName) != 2 || byName["Base"] != types[1] {
+		t.Errorf("unexpected: %v", byName)
+	}
+
+	st, err := byName["Sample"].StructType()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := st.FieldInfos()
+	fieldsByName := fields.ByName()
+	if len(fieldsByName) != 4 {
+		t.Errorf("unexpected: %v", fieldsByName)
+	}
+	if fieldsByName["A"] != fields[0] || fieldsByName["B"] != fields[0] || fieldsByName["C"] != fields[1] || fieldsByName["Base"] != fields[2] {
+		t.Errorf("unexpected: %v", fieldsByName)
+	}
+}
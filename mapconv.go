@@ -0,0 +1,278 @@
+package genbase
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strconv"
+)
+
+// MapOptions is options of map conversions, see Generator.PrintToMap and Generator.PrintFromMap.
+type MapOptions struct {
+	TagKey string    // struct tag key for map keys, "-" and omitempty like encoding/json. e.g. "json", "datastore"
+	Nested TypeInfos // struct types that have conversions too, fields of them are converted to nested maps
+}
+
+// mapField is struct field that is converted to map entry.
+type mapField struct {
+	name      string // field name
+	key       string // map key, empty for embedded nested struct that is flattened
+	omitEmpty bool
+	typ       types.Type
+}
+
+// mapConverter prints map conversions of struct type.
+type mapConverter struct {
+	g         *Generator
+	t         *TypeInfo
+	qualifier func(pkgPath string) string
+	nested    map[*types.TypeName]bool
+	fields    []*mapField
+}
+
+func (g *Generator) newMapConverter(t *TypeInfo, opts *MapOptions) (*mapConverter, error) {
+	st, err := t.StructType()
+	if err != nil {
+		return nil, err
+	}
+	if t.PackageInfo.TypesInfo == nil {
+		return nil, errors.New("types are not resolved")
+	}
+	if opts == nil {
+		opts = &MapOptions{}
+	}
+
+	c := &mapConverter{
+		g:         g,
+		t:         t,
+		qualifier: g.Qualifier(),
+		nested:    make(map[*types.TypeName]bool),
+	}
+	for _, nested := range opts.Nested {
+		if obj := nested.TypeObject(); obj != nil {
+			c.nested[obj] = true
+		}
+	}
+
+	for _, f := range st.FieldInfos() {
+		if f.IsSkipped(opts.TagKey) {
+			continue
+		}
+		typ := t.PackageInfo.TypeOf(f.Type)
+		if typ == nil {
+			return nil, fmt.Errorf("%s: type of field is not resolved", t.PackageInfo.position(f.Type.Pos()))
+		}
+		name, tagOpts := "", []string(nil)
+		if opts.TagKey != "" {
+			name, tagOpts = SplitTagValue(f.tagValue(opts.TagKey))
+		}
+		omitEmpty := containsString(tagOpts, "omitempty")
+
+		if len(f.Names) == 0 {
+			fieldName := f.Name()
+			if !ast.IsExported(fieldName) {
+				continue
+			}
+			if name == "" && c.nestedType(typ) != nil {
+				c.fields = append(c.fields, &mapField{name: fieldName, typ: typ})
+				continue
+			}
+			if name == "" {
+				name = fieldName
+			}
+			c.fields = append(c.fields, &mapField{name: fieldName, key: name, omitEmpty: omitEmpty, typ: typ})
+			continue
+		}
+		for _, ident := range f.Names {
+			if !ident.IsExported() {
+				continue
+			}
+			key := name
+			if key == "" {
+				key = ident.Name
+			}
+			c.fields = append(c.fields, &mapField{name: ident.Name, key: key, omitEmpty: omitEmpty, typ: typ})
+		}
+	}
+	return c, nil
+}
+
+// nestedType returns name of nested struct type of typ or pointer to it, or nil.
+func (c *mapConverter) nestedType(typ types.Type) *types.TypeName {
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	if named, ok := typ.(*types.Named); ok && c.nested[named.Obj()] {
+		return named.Obj()
+	}
+	return nil
+}
+
+func (c *mapConverter) typeName(typ types.Type) string {
+	return c.t.PackageInfo.renderType(typ, c.qualifier)
+}
+
+// PrintToMap prints ToMap method that converts struct to map[string]interface{}, to buffer.
+// map keys are named by tag like encoding/json, field value is stored as it is,
+// and nested struct, pointer to it and slice of them are converted to map[string]interface{} and []map[string]interface{}.
+// fields of embedded nested struct without tag name are flattened.
+func (g *Generator) PrintToMap(t *TypeInfo, opts *MapOptions) error {
+	c, err := g.newMapConverter(t, opts)
+	if err != nil {
+		return err
+	}
+
+	g.Printf("// ToMap returns map representation of %s.\n", t.Name())
+	g.Printf("func (x *%s) ToMap() map[string]interface{} {\n", t.Name())
+	g.Printf("if x == nil {\nreturn nil\n}\n")
+	g.Printf("m := make(map[string]interface{})\n")
+	for _, f := range c.fields {
+		c.printToMapField(f)
+	}
+	g.Printf("return m\n}\n\n")
+	return nil
+}
+
+func (c *mapConverter) printToMapField(f *mapField) {
+	g := c.g
+	value := "x." + f.name
+	_, isPointer := f.typ.(*types.Pointer)
+
+	if f.key == "" {
+		if isPointer {
+			g.Printf("if %s != nil {\n", value)
+		}
+		g.Printf("for k, v := range %s.ToMap() {\nm[k] = v\n}\n", value)
+		if isPointer {
+			g.Printf("}\n")
+		}
+		return
+	}
+
+	if c.nestedType(f.typ) != nil {
+		if isPointer && f.omitEmpty {
+			g.Printf("if %s != nil {\nm[%q] = %s.ToMap()\n}\n", value, f.key, value)
+		} else {
+			g.Printf("m[%q] = %s.ToMap()\n", f.key, value)
+		}
+		return
+	}
+	if c.isNestedSlice(f.typ) {
+		// block scopes vs for each field
+		if f.omitEmpty {
+			g.Printf("if len(%s) != 0 {\n", value)
+		} else {
+			g.Printf("{\n")
+		}
+		g.Printf("vs := make([]map[string]interface{}, 0, len(%s))\n", value)
+		g.Printf("for i := range %s {\nvs = append(vs, %s[i].ToMap())\n}\n", value, value)
+		g.Printf("m[%q] = vs\n}\n", f.key)
+		return
+	}
+
+	if cond := nonEmptyCond(f.typ, value); f.omitEmpty && cond != "" {
+		g.Printf("if %s {\nm[%q] = %s\n}\n", cond, f.key, value)
+		return
+	}
+	g.Printf("m[%q] = %s\n", f.key, value)
+}
+
+// nonEmptyCond returns condition that value is not empty like omitempty of encoding/json.
+// returns empty string for struct types, they are never empty.
+func nonEmptyCond(typ types.Type, value string) string {
+	switch t := typ.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case t.Info()&types.IsBoolean != 0:
+			return value
+		case t.Info()&types.IsString != 0:
+			return value + ` != ""`
+		case t.Info()&types.IsNumeric != 0:
+			return value + " != 0"
+		}
+	case *types.Slice, *types.Map, *types.Array:
+		return "len(" + value + ") != 0"
+	case *types.Pointer, *types.Interface, *types.Chan, *types.Signature:
+		return value + " != nil"
+	}
+	return ""
+}
+
+// PrintFromMap prints FromMap method that populates struct by map[string]interface{}, to buffer.
+// it accepts values that ToMap produces, missing keys are ignored and error is returned if value has unexpected type.
+// fmt package is imported for errors.
+func (g *Generator) PrintFromMap(t *TypeInfo, opts *MapOptions) error {
+	c, err := g.newMapConverter(t, opts)
+	if err != nil {
+		return err
+	}
+
+	g.Printf("// FromMap populates %s by map representation.\n", t.Name())
+	g.Printf("func (x *%s) FromMap(m map[string]interface{}) error {\n", t.Name())
+	for _, f := range c.fields {
+		c.printFromMapField(f)
+	}
+	g.Printf("return nil\n}\n\n")
+	return nil
+}
+
+func (c *mapConverter) printFromMapField(f *mapField) {
+	g := c.g
+	value := "x." + f.name
+	ptr, isPointer := f.typ.(*types.Pointer)
+
+	if f.key == "" {
+		if isPointer {
+			g.Printf("if %s == nil {\n%s = new(%s)\n}\n", value, value, c.typeName(ptr.Elem()))
+		}
+		g.Printf("if err := %s.FromMap(m); err != nil {\nreturn err\n}\n", value)
+		return
+	}
+
+	g.Printf("if v, ok := m[%q]; ok {\n", f.key)
+	switch {
+	case c.nestedType(f.typ) != nil:
+		c.printAssert("mv", "map[string]interface{}", f.key)
+		if isPointer {
+			g.Printf("if mv == nil {\n%s = nil\n} else {\n%s = new(%s)\n", value, value, c.typeName(ptr.Elem()))
+		}
+		g.Printf("if err := %s.FromMap(mv); err != nil {\nreturn err\n}\n", value)
+		if isPointer {
+			g.Printf("}\n")
+		}
+	case c.isNestedSlice(f.typ):
+		elem := f.typ.Underlying().(*types.Slice).Elem()
+		c.printAssert("mvs", "[]map[string]interface{}", f.key)
+		g.Printf("%s = make(%s, len(mvs))\n", value, c.typeName(f.typ))
+		g.Printf("for i, mv := range mvs {\n")
+		if elemPtr, ok := elem.(*types.Pointer); ok {
+			g.Printf("if mv == nil {\ncontinue\n}\n")
+			g.Printf("%s[i] = new(%s)\n", value, c.typeName(elemPtr.Elem()))
+		}
+		g.Printf("if err := %s[i].FromMap(mv); err != nil {\nreturn err\n}\n}\n", value)
+	case isEmptyInterface(f.typ):
+		g.Printf("%s = v\n", value)
+	default:
+		c.printAssert("tv", c.typeName(f.typ), f.key)
+		g.Printf("%s = tv\n", value)
+	}
+	g.Printf("}\n")
+}
+
+func (c *mapConverter) isNestedSlice(typ types.Type) bool {
+	slice, ok := typ.Underlying().(*types.Slice)
+	return ok && c.nestedType(slice.Elem()) != nil
+}
+
+// printAssert prints type assertion of v to variable name.
+func (c *mapConverter) printAssert(name, typeName, key string) {
+	c.g.Printf("%s, ok := v.(%s)\n", name, typeName)
+	format := strconv.Quote(fmt.Sprintf("%s.%s: unexpected type %%T", c.t.Name(), key))
+	c.g.Printf("if !ok {\nreturn %s.Errorf(%s, v)\n}\n", c.qualifier("fmt"), format)
+}
+
+func isEmptyInterface(typ types.Type) bool {
+	iface, ok := typ.(*types.Interface)
+	return ok && iface.NumMethods() == 0
+}
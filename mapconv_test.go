@@ -0,0 +1,84 @@
+package genbase
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGeneratorPrintMapConversions(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", "package sample\n"+
+		"import \"time\"\n"+
+		"type User struct {\n"+
+		"	Base\n"+
+		"	Name      string `datastore:\"name\"`\n"+
+		"	Nick      string `datastore:\",omitempty\"`\n"+
+		"	Parent    *User  `datastore:\"parent,omitempty\"`\n"+
+		"	Friends   []*User `datastore:\"friends\"`\n"+
+		"	Address   Address `datastore:\"address\"`\n"+
+		"	Extra     interface{}\n"+
+		"	CreatedAt time.Time `datastore:\"createdAt\"`\n"+
+		"	Cache     string `datastore:\"-\"`\n"+
+		"	private   string\n"+
+		"}\n"+
+		"type Base struct {\n"+
+		"	ID int64 `datastore:\"id\"`\n"+
+		"}\n"+
+		"type Address struct {\n"+
+		"	City string `datastore:\"city\"`\n"+
+		"}\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nested := pInfo.CollectTypeInfos([]string{"User", "Base", "Address"})
+	opts := &MapOptions{TagKey: "datastore", Nested: nested}
+	g := NewGenerator(pInfo)
+	for _, typeInfo := range nested {
+		if err := g.PrintToMap(typeInfo, opts); err != nil {
+			t.Fatal(err)
+		}
+		if err := g.PrintFromMap(typeInfo, opts); err != nil {
+			t.Fatal(err)
+		}
+	}
+	body := g.Buf.String()
+	g.Buf.Reset()
+	g.PrintHeader("sample", &[]string{})
+	g.Printf("%s", body)
+	src, err := g.Format()
+	if err != nil {
+		t.Fatalf("unexpected: %s\n%s", err, string(src))
+	}
+	if _, err := pInfo.CheckGenerated("sample_map.go", src); err != nil {
+		t.Fatalf("unexpected: %s\n%s", err, string(src))
+	}
+
+	output := string(src)
+	for _, expected := range []string{
+		"func (x *User) ToMap() map[string]interface{} {",
+		"for k, v := range x.Base.ToMap() {\n\t\tm[k] = v\n\t}",
+		"m[\"name\"] = x.Name",
+		"if x.Nick != \"\" {\n\t\tm[\"Nick\"] = x.Nick\n\t}",
+		"if x.Parent != nil {\n\t\tm[\"parent\"] = x.Parent.ToMap()\n\t}",
+		"vs = append(vs, x.Friends[i].ToMap())",
+		"m[\"address\"] = x.Address.ToMap()",
+		"m[\"Extra\"] = x.Extra",
+		"m[\"createdAt\"] = x.CreatedAt",
+		"func (x *User) FromMap(m map[string]interface{}) error {",
+		"if err := x.Base.FromMap(m); err != nil {",
+		"tv, ok := v.(time.Time)\n\t\tif !ok {\n\t\t\treturn fmt.Errorf(\"User.createdAt: unexpected type %T\", v)\n\t\t}\n\t\tx.CreatedAt = tv",
+		"x.Parent = new(User)",
+		"x.Friends = make([]*User, len(mvs))",
+		"x.Extra = v",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("unexpected: %s is not contained\n%s", expected, output)
+		}
+	}
+	for _, unexpected := range []string{"Cache", "private"} {
+		if strings.Contains(output, unexpected) {
+			t.Errorf("unexpected: %s is contained\n%s", unexpected, output)
+		}
+	}
+}
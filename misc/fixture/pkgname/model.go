@@ -0,0 +1,4 @@
+package renamed
+
+// Model is struct in package that name is not same as directory.
+type Model struct{}
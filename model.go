@@ -0,0 +1,410 @@
+package genbase
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+// Kind is kind of type expression in Model.
+type Kind string
+
+const (
+	// KindInvalid shows type expression is not supported.
+	KindInvalid Kind = "invalid"
+	// KindBasic shows predeclared type. e.g. string, int64, error
+	KindBasic Kind = "basic"
+	// KindNamed shows named type. e.g. Foo, time.Time
+	KindNamed Kind = "named"
+	// KindPointer shows pointer type.
+	KindPointer Kind = "pointer"
+	// KindSlice shows slice type.
+	KindSlice Kind = "slice"
+	// KindArray shows array type.
+	KindArray Kind = "array"
+	// KindMap shows map type.
+	KindMap Kind = "map"
+	// KindChan shows channel type.
+	KindChan Kind = "chan"
+	// KindFunc shows function type.
+	KindFunc Kind = "func"
+	// KindInterface shows interface type.
+	KindInterface Kind = "interface"
+	// KindStruct shows struct type.
+	KindStruct Kind = "struct"
+)
+
+// ChanDir is direction of channel type.
+type ChanDir string
+
+const (
+	// ChanBoth shows bidirectional channel. e.g. chan int
+	ChanBoth ChanDir = "both"
+	// ChanSend shows send-only channel. e.g. chan<- int
+	ChanSend ChanDir = "send"
+	// ChanRecv shows receive-only channel. e.g. <-chan int
+	ChanRecv ChanDir = "recv"
+)
+
+// ModelVersion is version of serialized Model.
+// it is incremented when serialized Model becomes incompatible with older one.
+const ModelVersion = 1
+
+// ModelVersionError shows that serialized Model has incompatible version.
+type ModelVersionError struct {
+	Version int // version of serialized Model, 0 if version is missing
+}
+
+func (err *ModelVersionError) Error() string {
+	return fmt.Sprintf("model version %d is not compatible with version %d, regenerate it", err.Version, ModelVersion)
+}
+
+// Model is plain representation of package, decoupled from go/ast.
+type Model struct {
+	Version int     `json:"version"` // ModelVersion when Model is created
+	Package string  `json:"package"`
+	Dir     string  `json:"dir,omitempty"`
+	Types   []*Type `json:"types"`
+}
+
+// Type is plain representation of type declaration.
+type Type struct {
+	Name        string           `json:"name"`
+	Doc         string           `json:"doc,omitempty"`
+	Annotations []string         `json:"annotations,omitempty"` // comment lines that start with "+"
+	TypeParams  []*TypeParameter `json:"typeParams,omitempty"`
+	Alias       bool             `json:"alias,omitempty"`
+	Kind        Kind             `json:"kind"`
+	Fields      []*Field         `json:"fields,omitempty"`     // for KindStruct
+	Underlying  *TypeRef         `json:"underlying,omitempty"` // for other kinds
+}
+
+// TypeParameter is plain representation of type parameter.
+type TypeParameter struct {
+	Name       string `json:"name"`
+	Constraint string `json:"constraint"` // source of constraint. e.g. "~int | ~string"
+}
+
+// Field is plain representation of struct field.
+type Field struct {
+	Name     string   `json:"name"`
+	Embedded bool     `json:"embedded,omitempty"`
+	Type     *TypeRef `json:"type"`
+	Tags     Tags     `json:"tags,omitempty"`
+	Doc      string   `json:"doc,omitempty"`
+	Comment  string   `json:"comment,omitempty"`
+}
+
+// TypeRef is plain representation of type expression.
+type TypeRef struct {
+	Kind    Kind     `json:"kind"`
+	Package string   `json:"package,omitempty"` // package ident of KindNamed. e.g. "time"
+	Name    string   `json:"name,omitempty"`    // for KindBasic and KindNamed
+	Len     string   `json:"len,omitempty"`     // for KindArray
+	Dir     ChanDir  `json:"dir,omitempty"`     // for KindChan
+	Key     *TypeRef `json:"key,omitempty"`     // for KindMap
+	Elem    *TypeRef `json:"elem,omitempty"`    // for KindPointer, KindSlice, KindArray, KindMap and KindChan
+	Fields  []*Field `json:"fields,omitempty"`  // for KindStruct
+	Expr    string   `json:"expr,omitempty"`    // source of KindFunc, KindInterface and KindInvalid
+}
+
+// Tag is key-value pair of struct tag.
+type Tag struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Tags is []*Tag synonym.
+type Tags []*Tag
+
+// NewModel creates Model from TypeInfos of package.
+func NewModel(pkg *PackageInfo, typeInfos TypeInfos) *Model {
+	m := &Model{
+		Version: ModelVersion,
+		Package: pkg.Name(),
+		Dir:     pkg.Dir,
+		Types:   make([]*Type, 0, len(typeInfos)),
+	}
+	for _, t := range typeInfos {
+		m.Types = append(m.Types, NewType(t))
+	}
+	return m
+}
+
+// LoadModel decodes serialized Model from JSON.
+// returns *ModelVersionError if it is serialized by incompatible version.
+func LoadModel(b []byte) (*Model, error) {
+	m := &Model{}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, err
+	}
+	if m.Version != ModelVersion {
+		return nil, &ModelVersionError{Version: m.Version}
+	}
+	return m, nil
+}
+
+// NewType creates Type from TypeInfo.
+func NewType(t *TypeInfo) *Type {
+	ret := &Type{
+		Name:  t.Name(),
+		Doc:   t.Doc().Text(),
+		Alias: t.TypeSpec.Assign.IsValid(),
+	}
+	for _, doc := range t.Comments() {
+		for _, c := range doc.List {
+			if strings.HasPrefix(normalizeAnnotation(c.Text), "+") {
+				ret.Annotations = append(ret.Annotations, c.Text)
+			}
+		}
+	}
+	for _, tp := range t.TypeParams() {
+		ret.TypeParams = append(ret.TypeParams, &TypeParameter{Name: tp.Name, Constraint: types.ExprString(tp.Constraint)})
+	}
+	ref := NewTypeRef(t.TypeSpec.Type)
+	ret.Kind = ref.Kind
+	if ref.Kind == KindStruct {
+		ret.Fields = ref.Fields
+	} else {
+		ret.Underlying = ref
+	}
+	return ret
+}
+
+// NewFields creates Fields from StructTypeInfo.
+// FieldInfo that has multiple names is expanded to multiple Fields.
+func NewFields(st *StructTypeInfo) []*Field {
+	return newFields(st.AstStructType().Fields)
+}
+
+func newFields(list *ast.FieldList) []*Field {
+	var fields []*Field
+	if list == nil {
+		return fields
+	}
+	for _, f := range list.List {
+		var tags Tags
+		if f.Tag != nil {
+			if tag, err := strconv.Unquote(f.Tag.Value); err == nil {
+				tags = ParseTags(tag)
+			}
+		}
+		newField := func(name string, embedded bool) *Field {
+			return &Field{
+				Name:     name,
+				Embedded: embedded,
+				Type:     NewTypeRef(f.Type),
+				Tags:     tags,
+				Doc:      f.Doc.Text(),
+				Comment:  f.Comment.Text(),
+			}
+		}
+		if len(f.Names) == 0 {
+			name, _ := ExprToBaseTypeName(f.Type)
+			if idx := strings.LastIndex(name, "."); idx != -1 {
+				name = name[idx+1:]
+			}
+			fields = append(fields, newField(name, true))
+			continue
+		}
+		for _, name := range f.Names {
+			fields = append(fields, newField(name.Name, false))
+		}
+	}
+	return fields
+}
+
+// NewTypeRef creates TypeRef from ast.Expr.
+func NewTypeRef(expr ast.Expr) *TypeRef {
+	switch t := expr.(type) {
+	case *ast.ParenExpr:
+		return NewTypeRef(t.X)
+	case *ast.Ident:
+		if obj, ok := types.Universe.Lookup(t.Name).(*types.TypeName); ok && obj != nil {
+			return &TypeRef{Kind: KindBasic, Name: t.Name}
+		}
+		return &TypeRef{Kind: KindNamed, Name: t.Name}
+	case *ast.SelectorExpr:
+		if x, ok := t.X.(*ast.Ident); ok {
+			return &TypeRef{Kind: KindNamed, Package: x.Name, Name: t.Sel.Name}
+		}
+	case *ast.StarExpr:
+		return &TypeRef{Kind: KindPointer, Elem: NewTypeRef(t.X)}
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return &TypeRef{Kind: KindSlice, Elem: NewTypeRef(t.Elt)}
+		}
+		return &TypeRef{Kind: KindArray, Len: types.ExprString(t.Len), Elem: NewTypeRef(t.Elt)}
+	case *ast.MapType:
+		return &TypeRef{Kind: KindMap, Key: NewTypeRef(t.Key), Elem: NewTypeRef(t.Value)}
+	case *ast.ChanType:
+		return &TypeRef{Kind: KindChan, Dir: NewChanDir(t.Dir), Elem: NewTypeRef(t.Value)}
+	case *ast.FuncType:
+		return &TypeRef{Kind: KindFunc, Expr: types.ExprString(t)}
+	case *ast.InterfaceType:
+		return &TypeRef{Kind: KindInterface, Expr: types.ExprString(t)}
+	case *ast.StructType:
+		return &TypeRef{Kind: KindStruct, Fields: newFields(t.Fields)}
+	}
+	return &TypeRef{Kind: KindInvalid, Expr: types.ExprString(expr)}
+}
+
+// NewChanDir creates ChanDir from ast.ChanDir.
+func NewChanDir(dir ast.ChanDir) ChanDir {
+	switch dir {
+	case ast.SEND:
+		return ChanSend
+	case ast.RECV:
+		return ChanRecv
+	}
+	return ChanBoth
+}
+
+// ParseTypeExpr parses type expression string to TypeRef. e.g. "map[string]*foo.Bar"
+func ParseTypeExpr(expr string) (*TypeRef, error) {
+	x, err := parser.ParseExpr(expr)
+	if err != nil {
+		return nil, fmt.Errorf("parsing type expression %q: %s", expr, err)
+	}
+	ref := NewTypeRef(x)
+	if ref.Kind == KindInvalid {
+		return nil, fmt.Errorf("%q is not type expression", expr)
+	}
+	return ref, nil
+}
+
+// String returns Go source of type expression.
+func (ref *TypeRef) String() string {
+	switch ref.Kind {
+	case KindBasic:
+		return ref.Name
+	case KindNamed:
+		if ref.Package != "" {
+			return ref.Package + "." + ref.Name
+		}
+		return ref.Name
+	case KindPointer:
+		return "*" + ref.Elem.String()
+	case KindSlice:
+		return "[]" + ref.Elem.String()
+	case KindArray:
+		return "[" + ref.Len + "]" + ref.Elem.String()
+	case KindMap:
+		return "map[" + ref.Key.String() + "]" + ref.Elem.String()
+	case KindChan:
+		elem := ref.Elem.String()
+		switch ref.Dir {
+		case ChanSend:
+			return "chan<- " + elem
+		case ChanRecv:
+			return "<-chan " + elem
+		}
+		if ref.Elem.Kind == KindChan && ref.Elem.Dir == ChanRecv {
+			// "chan <-chan int" is parsed as "chan<- chan int"
+			return "chan (" + elem + ")"
+		}
+		return "chan " + elem
+	case KindStruct:
+		var ss []string
+		for _, f := range ref.Fields {
+			s := f.Type.String()
+			if !f.Embedded {
+				s = f.Name + " " + s
+			}
+			if len(f.Tags) != 0 {
+				s += " " + strconv.Quote(f.Tags.String())
+			}
+			ss = append(ss, s)
+		}
+		return "struct{" + strings.Join(ss, "; ") + "}"
+	}
+	return ref.Expr
+}
+
+// IsExported returns true if field name is exported, otherwise returns false.
+func (f *Field) IsExported() bool {
+	return ast.IsExported(f.Name)
+}
+
+// ParseTags parses struct tag string to Tags.
+// likes reflect.StructTag.Lookup(string)
+func ParseTags(tag string) Tags {
+	var tags Tags
+
+	// from reflect.StructTag.Lookup(string)
+
+	for tag != "" {
+		// skip leading space
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+
+		// scan to colon.
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		name := tag[:i]
+		tag = tag[i+1:]
+
+		// scan quoted string to find value
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		qvalue := tag[:i+1]
+		tag = tag[i+1:]
+
+		value, err := strconv.Unquote(qvalue)
+		if err != nil {
+			break
+		}
+		tags = append(tags, &Tag{Key: name, Value: value})
+	}
+	return tags
+}
+
+// Get returns value of tag by key.
+// likes reflect.StructTag.Lookup(string)
+func (tags Tags) Get(key string) (string, bool) {
+	for _, tag := range tags {
+		if tag.Key == key {
+			return tag.Value, true
+		}
+	}
+	return "", false
+}
+
+// SplitTagValue splits value of struct tag to name and options.
+// e.g. "a,omitempty" to "a" and ["omitempty"].
+func SplitTagValue(value string) (string, []string) {
+	ss := strings.Split(value, ",")
+	return ss[0], ss[1:]
+}
+
+// String returns struct tag string of Tags.
+func (tags Tags) String() string {
+	ss := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		ss = append(ss, tag.Key+":"+strconv.Quote(tag.Value))
+	}
+	return strings.Join(ss, " ")
+}
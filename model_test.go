@@ -0,0 +1,192 @@
+package genbase
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewModel(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	import "time"
+
+	// Sample is sample!
+	// +sample
+	type Sample struct {
+		A    string `+"`json:\"a,omitempty\" datastore:\"-\"`"+`
+		B, C []*int
+		D    map[string]time.Time
+		E    [3]byte
+		Inner
+		*time.Location
+	}
+
+	type Inner struct{}
+
+	type Names []string
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewModel(pInfo, pInfo.CollectTaggedTypeInfos("+sample"))
+	if m.Package != "sample" || len(m.Types) != 1 {
+		t.Fatalf("unexpected: %#v", m)
+	}
+	st := m.Types[0]
+	if st.Name != "Sample" || st.Kind != KindStruct || st.Doc != "Sample is sample!\n+sample\n" {
+		t.Fatalf("unexpected: %#v", st)
+	}
+	if len(st.Annotations) != 1 || st.Annotations[0] != "// +sample" {
+		t.Fatalf("unexpected: %#v", st.Annotations)
+	}
+
+	expects := []struct {
+		name     string
+		typeName string
+		embedded bool
+	}{
+		{"A", "string", false},
+		{"B", "[]*int", false},
+		{"C", "[]*int", false},
+		{"D", "map[string]time.Time", false},
+		{"E", "[3]byte", false},
+		{"Inner", "Inner", true},
+		{"Location", "*time.Location", true},
+	}
+	if len(st.Fields) != len(expects) {
+		t.Fatalf("unexpected: %d", len(st.Fields))
+	}
+	for i, expect := range expects {
+		f := st.Fields[i]
+		if f.Name != expect.name || f.Type.String() != expect.typeName || f.Embedded != expect.embedded {
+			t.Errorf("unexpected: %s %s %v", f.Name, f.Type.String(), f.Embedded)
+		}
+	}
+
+	if v, ok := st.Fields[0].Tags.Get("json"); !ok || v != "a,omitempty" {
+		t.Errorf("unexpected: %s", v)
+	}
+	if v := st.Fields[0].Tags.String(); v != `json:"a,omitempty" datastore:"-"` {
+		t.Errorf("unexpected: %s", v)
+	}
+
+	names := NewType(pInfo.CollectTypeInfos([]string{"Names"})[0])
+	if names.Kind != KindSlice || names.Underlying.Elem.Kind != KindBasic {
+		t.Errorf("unexpected: %#v", names)
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadModel(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Version != ModelVersion || len(loaded.Types) != len(m.Types) {
+		t.Errorf("unexpected: %#v", loaded)
+	}
+}
+
+func TestLoadModelVersion(t *testing.T) {
+	_, err := LoadModel([]byte(`{"package":"sample","types":[]}`))
+	if err, ok := err.(*ModelVersionError); !ok || err.Version != 0 {
+		t.Errorf("unexpected: %v", err)
+	}
+	_, err = LoadModel([]byte(`{"version":999,"package":"sample","types":[]}`))
+	if err == nil || err.Error() != "model version 999 is not compatible with version 1, regenerate it" {
+		t.Errorf("unexpected: %v", err)
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	tags := ParseTags(`a:"foo" b:"b\"ar"  c:"`)
+	if len(tags) != 2 {
+		t.Fatalf("unexpected: %d", len(tags))
+	}
+	if tags[0].Key != "a" || tags[0].Value != "foo" {
+		t.Errorf("unexpected: %#v", tags[0])
+	}
+	if tags[1].Key != "b" || tags[1].Value != `b"ar` {
+		t.Errorf("unexpected: %#v", tags[1])
+	}
+}
+
+func TestSplitTagValue(t *testing.T) {
+	name, opts := SplitTagValue("a,omitempty,string")
+	if name != "a" || len(opts) != 2 || opts[0] != "omitempty" || opts[1] != "string" {
+		t.Fatalf("unexpected: %s %v", name, opts)
+	}
+	name, opts = SplitTagValue("")
+	if name != "" || len(opts) != 0 {
+		t.Fatalf("unexpected: %s %v", name, opts)
+	}
+}
+
+func TestParseTypeExpr(t *testing.T) {
+	ref, err := ParseTypeExpr("map[string]*foo.Bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref.Kind != KindMap || ref.Key.Kind != KindBasic || ref.Elem.Kind != KindPointer {
+		t.Fatalf("unexpected: %#v", ref)
+	}
+	if ref.Elem.Elem.Package != "foo" || ref.Elem.Elem.Name != "Bar" {
+		t.Fatalf("unexpected: %#v", ref.Elem.Elem)
+	}
+	if v := ref.String(); v != "map[string]*foo.Bar" {
+		t.Fatalf("unexpected: %s", v)
+	}
+
+	for _, expr := range []string{"1 + 2", "map[string", ""} {
+		if _, err := ParseTypeExpr(expr); err == nil {
+			t.Errorf("unexpected: %s is parsed", expr)
+		}
+	}
+}
+
+func TestTypeRefChanDir(t *testing.T) {
+	expects := map[string]ChanDir{
+		"chan int":          ChanBoth,
+		"chan<- int":        ChanSend,
+		"<-chan int":        ChanRecv,
+		"chan (<-chan int)": ChanBoth,
+		"chan<- chan int":   ChanSend,
+	}
+	for expr, dir := range expects {
+		ref, err := ParseTypeExpr(expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ref.Kind != KindChan || ref.Dir != dir || ref.Elem == nil {
+			t.Errorf("unexpected: %s %#v", expr, ref)
+		}
+		if v := ref.String(); v != expr {
+			t.Errorf("unexpected: %s", v)
+		}
+	}
+}
+
+func FuzzParseTags(f *testing.F) {
+	for _, seed := range []string{`a:"foo" b:"b\"ar"`, `json:"a,omitempty"`, `a:"`, `:"x"`, `a:"\q"`, "ü:\"ß\"", "a:\"\x00\"\x7f"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, tag string) {
+		tags := ParseTags(tag)
+		for _, tag := range tags {
+			if tag.Key == "" {
+				t.Errorf("unexpected: empty key in %q", tag)
+			}
+		}
+		if v := ParseTags(tags.String()); len(v) != len(tags) {
+			t.Errorf("unexpected: %q %q", tags.String(), v.String())
+		}
+		// GetKeys also returns keys of malformed tail, e.g. ["json"] of `json:"a`.
+		if v := GetKeys(tag); len(v) < len(tags) {
+			t.Errorf("unexpected: %q %v", tag, v)
+		}
+	})
+}
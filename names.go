@@ -0,0 +1,62 @@
+package genbase
+
+import (
+	"go/token"
+	"strings"
+	"unicode"
+)
+
+// SanitizeIdent returns valid Go identifier made from s, case of s is kept.
+// invalid runes are replaced by "_", "_" is prepended to leading digit, and "_" is appended to keyword.
+// e.g. "user-id" to "user_id", "1st" to "_1st", "type" to "type_"
+func SanitizeIdent(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch {
+		case i == 0 && unicode.IsDigit(r):
+			b.WriteRune('_')
+			b.WriteRune(r)
+		case isIdentRune(r):
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	ident := b.String()
+	switch {
+	case ident == "":
+		return "_"
+	case token.IsKeyword(ident):
+		return ident + "_"
+	}
+	return ident
+}
+
+// ExportedIdent returns exported Go identifier made from s by CamelCase.
+// "X" is prepended if s starts with digit. e.g. "user_id" to "UserID", "1st" to "X1st"
+func ExportedIdent(s string) string {
+	ident := CamelCase(s)
+	if ident == "" || !unicode.IsUpper([]rune(ident)[0]) {
+		return "X" + ident
+	}
+	return ident
+}
+
+// UnexportedIdent returns unexported Go identifier made from s by LowerCamelCase.
+// "_" is appended to keyword. e.g. "UserName" to "userName", "URLPath" to "urlPath", "type" to "type_"
+func UnexportedIdent(s string) string {
+	return SanitizeIdent(LowerCamelCase(s))
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func upperFirst(s string) string {
+	rs := []rune(s)
+	if len(rs) == 0 {
+		return s
+	}
+	rs[0] = unicode.ToUpper(rs[0])
+	return string(rs)
+}
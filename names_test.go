@@ -0,0 +1,64 @@
+package genbase
+
+import (
+	"go/token"
+	"testing"
+)
+
+func TestSanitizeIdent(t *testing.T) {
+	expects := map[string]string{
+		"user_id": "user_id",
+		"user-id": "user_id",
+		"1st":     "_1st",
+		"type":    "type_",
+		"":        "_",
+		"名前":      "名前",
+		"a.b c":   "a_b_c",
+	}
+	for s, expect := range expects {
+		if v := SanitizeIdent(s); v != expect {
+			t.Errorf("unexpected: %s %s", s, v)
+		}
+		if v := SanitizeIdent(s); !token.IsIdentifier(v) {
+			t.Errorf("unexpected: %s is not identifier", v)
+		}
+	}
+}
+
+func TestExportedIdent(t *testing.T) {
+	expects := map[string]string{
+		"user_name":  "UserName",
+		"user_id":    "UserID",
+		"user-name":  "UserName",
+		"userName":   "UserName",
+		"1st place":  "X1stPlace",
+		"":           "X",
+		"type":       "Type",
+		"@timestamp": "Timestamp",
+		"APIs":       "APIs",
+		"user_ids":   "UserIDs",
+	}
+	for s, expect := range expects {
+		if v := ExportedIdent(s); v != expect {
+			t.Errorf("unexpected: %s %s", s, v)
+		}
+	}
+}
+
+func TestUnexportedIdent(t *testing.T) {
+	expects := map[string]string{
+		"UserName":  "userName",
+		"URLPath":   "urlPath",
+		"ID":        "id",
+		"user_name": "userName",
+		"type":      "type_",
+		"Func":      "func_",
+		"1st":       "_1st",
+		"":          "_",
+	}
+	for s, expect := range expects {
+		if v := UnexportedIdent(s); v != expect {
+			t.Errorf("unexpected: %s %s", s, v)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+// Package openapi emits OpenAPI 3 component schemas from genbase.Model.
+//
+// json struct tags are honored the same way as encoding/json does.
+// named types of the same package are referenced as "#/components/schemas/Name".
+package openapi
+
+import (
+	"github.com/favclip/genbase"
+	"github.com/favclip/genbase/internal/jsonmodel"
+)
+
+// RefPrefix is prefix of reference to component schema.
+const RefPrefix = "#/components/schemas/"
+
+// Components is OpenAPI components object.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Schema is OpenAPI schema object.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+}
+
+// FromModel creates Components that has schemas of each types in Model.
+func FromModel(m *genbase.Model) *Components {
+	c := &Components{
+		Schemas: make(map[string]*Schema),
+	}
+	scope := jsonmodel.NewScope(m.Types)
+	for _, t := range m.Types {
+		c.Schemas[t.Name] = fromType(t, scope)
+	}
+	return c
+}
+
+// FromType creates Schema from genbase.Type.
+func FromType(t *genbase.Type) *Schema {
+	return fromType(t, nil)
+}
+
+func fromType(t *genbase.Type, scope jsonmodel.Scope) *Schema {
+	var s *Schema
+	if t.Kind == genbase.KindStruct {
+		s = fromFields(t.Fields, scope)
+	} else {
+		s = fromTypeRef(t.Underlying, scope)
+		if s == nil {
+			s = &Schema{}
+		}
+	}
+	return describe(s, t.Doc)
+}
+
+// FromTypeRef creates Schema from genbase.TypeRef.
+// returns nil if type can't be encoded to JSON.
+func FromTypeRef(ref *genbase.TypeRef) *Schema {
+	return fromTypeRef(ref, nil)
+}
+
+func fromTypeRef(ref *genbase.TypeRef, scope jsonmodel.Scope) *Schema {
+	if typ, format, ok := jsonmodel.Scalar(ref); ok {
+		return &Schema{Type: typ, Format: format}
+	}
+	switch ref.Kind {
+	case genbase.KindNamed:
+		if ref.Package == "" {
+			return &Schema{Ref: RefPrefix + ref.Name}
+		}
+		return &Schema{}
+	case genbase.KindPointer:
+		s := fromTypeRef(ref.Elem, scope)
+		if s == nil {
+			return nil
+		}
+		if s.Ref != "" {
+			// $ref can't have sibling properties.
+			s = &Schema{AllOf: []*Schema{s}}
+		}
+		s.Nullable = true
+		return s
+	case genbase.KindSlice, genbase.KindArray:
+		items := fromTypeRef(ref.Elem, scope)
+		if items == nil {
+			return nil
+		}
+		return &Schema{Type: "array", Items: items}
+	case genbase.KindMap:
+		elem := fromTypeRef(ref.Elem, scope)
+		if elem == nil {
+			return nil
+		}
+		return &Schema{Type: "object", AdditionalProperties: elem}
+	case genbase.KindStruct:
+		return fromFields(ref.Fields, scope)
+	case genbase.KindInterface:
+		return &Schema{}
+	}
+	return nil
+}
+
+func fromFields(fields []*genbase.Field, scope jsonmodel.Scope) *Schema {
+	s := &Schema{
+		Type:       "object",
+		Properties: make(map[string]*Schema),
+	}
+	props, embedded := scope.Properties(fields)
+	for _, f := range embedded {
+		if e := fromTypeRef(f.Type, scope); e != nil && (e.Ref != "" || len(e.AllOf) != 0) {
+			s.AllOf = append(s.AllOf, e)
+		}
+	}
+	for _, p := range props {
+		prop := fromTypeRef(p.Field.Type, scope)
+		if prop == nil {
+			continue
+		}
+		if p.String {
+			prop = &Schema{Type: "string", Nullable: prop.Nullable}
+		}
+		s.Properties[p.Name] = describe(prop, p.Field.Doc)
+		if p.Required {
+			s.Required = append(s.Required, p.Name)
+		}
+	}
+	return s
+}
+
+// describe sets description of doc to s. $ref is wrapped by allOf, because siblings of $ref are ignored.
+func describe(s *Schema, doc string) *Schema {
+	desc, wrap := jsonmodel.Describe(s.Ref, doc)
+	if desc == "" {
+		return s
+	}
+	if wrap {
+		s = &Schema{AllOf: []*Schema{s}}
+	}
+	s.Description = desc
+	return s
+}
@@ -0,0 +1,54 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/favclip/genbase"
+)
+
+func TestFromModel(t *testing.T) {
+	p := &genbase.Parser{}
+	pInfo, err := p.ParseStringSource("main.go", "package sample\n"+
+		"// User is user.\n"+
+		"// +sample\n"+
+		"type User struct {\n"+
+		"	Name    string `json:\"name\"`\n"+
+		"	Age     *int   `json:\"age\"`\n"+
+		"	Tags\n"+
+		"	// Address is address of user.\n"+
+		"	Address *Address\n"+
+		"	Friends []*User `json:\"friends,omitempty\"`\n"+
+		"	ch      chan int\n"+
+		"}\n"+
+		"// +sample\n"+
+		"type Tags []string\n"+
+		"// +sample\n"+
+		"type Address struct {\n"+
+		"	// Zip is zip code.\n"+
+		"	Zip string `json:\"zip\"`\n"+
+		"}\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := FromModel(genbase.NewModel(pInfo, pInfo.CollectTaggedTypeInfos("+sample")))
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"schemas":{` +
+		`"Address":{"type":"object","properties":{"zip":{"type":"string","description":"Zip is zip code."}},"required":["zip"]},` +
+		`"Tags":{"type":"array","items":{"type":"string"}},` +
+		`"User":{"type":"object","description":"User is user.","properties":{` +
+		`"Address":{"description":"Address is address of user.","nullable":true,"allOf":[{"$ref":"#/components/schemas/Address"}]},` +
+		`"Tags":{"$ref":"#/components/schemas/Tags"},` +
+		`"age":{"type":"integer","nullable":true},` +
+		`"friends":{"type":"array","items":{"nullable":true,"allOf":[{"$ref":"#/components/schemas/User"}]}},` +
+		`"name":{"type":"string"}},` +
+		`"required":["name","Tags"]}}}`
+	if string(b) != expected {
+		t.Fatalf("unexpected: %s", string(b))
+	}
+}
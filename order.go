@@ -0,0 +1,126 @@
+package genbase
+
+import (
+	"go/ast"
+	"go/token"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SortPolicy is ordering policy of fields. it returns true if a should be placed before b.
+type SortPolicy func(a, b *FieldInfo) bool
+
+var (
+	// SortBySource orders fields by position in source.
+	SortBySource SortPolicy = func(a, b *FieldInfo) bool {
+		return a.Pos() < b.Pos()
+	}
+	// SortByName orders fields alphabetically. embedded field is named by its type name.
+	SortByName SortPolicy = func(a, b *FieldInfo) bool {
+		return a.Name() < b.Name()
+	}
+	// SortExportedFirst orders exported fields before unexported fields, and keeps source order in each group.
+	SortExportedFirst SortPolicy = func(a, b *FieldInfo) bool {
+		return ast.IsExported(a.Name()) && !ast.IsExported(b.Name())
+	}
+)
+
+// SortByTag orders fields by number in tag value of key. e.g. `order:"1"`, `protobuf:"bytes,2,opt"`
+// first number in comma separated tag value is used, and fields without number are placed last.
+func SortByTag(key string) SortPolicy {
+	return func(a, b *FieldInfo) bool {
+		x, xok := a.tagNumber(key)
+		y, yok := b.tagNumber(key)
+		switch {
+		case xok && yok:
+			return x < y
+		case xok:
+			return true
+		}
+		return false
+	}
+}
+
+// SortBy returns copy of fields ordered by policy. it is stable sort, so fields that are equal keep their order.
+func (fields FieldInfos) SortBy(policy SortPolicy) FieldInfos {
+	sorted := make(FieldInfos, len(fields))
+	copy(sorted, fields)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return policy(sorted[i], sorted[j])
+	})
+	return sorted
+}
+
+// FieldGroup is fields that have same group key.
+type FieldGroup struct {
+	Key    string
+	Fields FieldInfos
+}
+
+// GroupBy groups fields by key that f returns.
+// groups are ordered by first appearance of key, and fields keep their order in each group.
+func (fields FieldInfos) GroupBy(f func(*FieldInfo) string) []*FieldGroup {
+	var groups []*FieldGroup
+	indexes := make(map[string]int)
+	for _, field := range fields {
+		key := f(field)
+		idx, ok := indexes[key]
+		if !ok {
+			idx = len(groups)
+			indexes[key] = idx
+			groups = append(groups, &FieldGroup{Key: key})
+		}
+		groups[idx].Fields = append(groups[idx].Fields, field)
+	}
+	return groups
+}
+
+// GroupByTag groups fields by first value in comma separated tag value of key. e.g. "a" for `group:"a,opt"`
+// fields without tag are grouped by "".
+func (fields FieldInfos) GroupByTag(key string) []*FieldGroup {
+	return fields.GroupBy(func(f *FieldInfo) string {
+		value, _ := SplitTagValue(f.tagValue(key))
+		return value
+	})
+}
+
+// Pos returns position of field.
+func (f *FieldInfo) Pos() token.Pos {
+	return (*ast.Field)(f).Pos()
+}
+
+// Name returns first name of field. type name is returned if field is embedded. e.g. "Time" for time.Time
+func (f *FieldInfo) Name() string {
+	if len(f.Names) != 0 {
+		return f.Names[0].Name
+	}
+	name, _ := ExprToBaseTypeName(f.Type)
+	if idx := strings.LastIndex(name, "."); idx != -1 {
+		name = name[idx+1:]
+	}
+	return name
+}
+
+// tagValue returns tag value of key.
+func (f *FieldInfo) tagValue(key string) string {
+	if f.Tag == nil {
+		return ""
+	}
+	tag, err := strconv.Unquote(f.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	return reflect.StructTag(tag).Get(key)
+}
+
+// tagNumber returns first number in comma separated tag value of key.
+func (f *FieldInfo) tagNumber(key string) (int, bool) {
+	for _, s := range strings.Split(f.tagValue(key), ",") {
+		if n, err := strconv.Atoi(s); err == nil {
+			return n, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,90 @@
+package genbase
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFieldInfosSortBy(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	import "time"
+
+	type Sample struct {
+		name    string `+"`order:\"2\"`"+`
+		Zip     string `+"`protobuf:\"bytes,3,opt\" order:\"1\"`"+`
+		Address string `+"`protobuf:\"bytes,1,opt\"`"+`
+		time.Time
+		Age int `+"`protobuf:\"varint,2,opt\"`"+`
+	}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := pInfo.CollectTypeInfos([]string{"Sample"})[0].StructType()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := st.FieldInfos()
+
+	names := func(fields FieldInfos) string {
+		var ss []string
+		for _, f := range fields {
+			ss = append(ss, f.Name())
+		}
+		return strings.Join(ss, ",")
+	}
+	expects := []struct {
+		policy SortPolicy
+		expect string
+	}{
+		{SortBySource, "name,Zip,Address,Time,Age"},
+		{SortByName, "Address,Age,Time,Zip,name"},
+		{SortExportedFirst, "Zip,Address,Time,Age,name"},
+		{SortByTag("protobuf"), "Address,Age,Zip,name,Time"},
+		{SortByTag("order"), "Zip,name,Address,Time,Age"},
+	}
+	for i, expect := range expects {
+		if v := names(fields.SortBy(expect.policy)); v != expect.expect {
+			t.Errorf("unexpected: %d %s", i, v)
+		}
+	}
+	if v := names(fields); v != "name,Zip,Address,Time,Age" {
+		t.Fatalf("unexpected: %s", v)
+	}
+}
+
+func TestFieldInfosGroupByTag(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	type Sample struct {
+		A string `+"`index:\"b,desc\"`"+`
+		B string
+		C string `+"`index:\"a\"`"+`
+		D string `+"`index:\"b\"`"+`
+	}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := pInfo.CollectTypeInfos([]string{"Sample"})[0].StructType()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ss []string
+	for _, group := range st.FieldInfos().GroupByTag("index") {
+		var names []string
+		for _, f := range group.Fields {
+			names = append(names, f.Name())
+		}
+		ss = append(ss, group.Key+":"+strings.Join(names, ","))
+	}
+	if v := strings.Join(ss, " "); v != "b:A,D :B a:C" {
+		t.Errorf("unexpected: %s", v)
+	}
+}
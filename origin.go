@@ -0,0 +1,118 @@
+package genbase
+
+import (
+	"go/types"
+	"strings"
+)
+
+// OriginKind is where type is declared.
+type OriginKind string
+
+const (
+	// OriginUnknown shows origin is unknown because types are not resolved.
+	OriginUnknown OriginKind = "unknown"
+	// OriginBuiltin shows predeclared type like int and error, or type literal of them like map[string]int.
+	OriginBuiltin OriginKind = "builtin"
+	// OriginStdlib shows type of standard library. e.g. time.Time
+	OriginStdlib OriginKind = "stdlib"
+	// OriginSamePackage shows type is declared in parsed package.
+	OriginSamePackage OriginKind = "same_package"
+	// OriginSameModule shows type is declared in other package of main module.
+	OriginSameModule OriginKind = "same_module"
+	// OriginExternal shows type is declared in package of other module.
+	OriginExternal OriginKind = "external"
+)
+
+// TypeOrigin is origin of type.
+type TypeOrigin struct {
+	Kind    OriginKind
+	Package string // import path of package that declares named type
+	Module  string // module path for OriginSameModule and OriginExternal, or "" if go.mod doesn't know it
+	Replace string // replacement of Module by replace directive of go.mod. e.g. "../uuid", "example.com/fork v1.0.0"
+}
+
+// TypeOrigin returns origin of field type. see PackageInfo.TypeOriginOf.
+func (f *FieldInfo) TypeOrigin(pkg *PackageInfo) *TypeOrigin {
+	typ := pkg.TypeOf(f.Type)
+	if typ == nil {
+		return &TypeOrigin{Kind: OriginUnknown}
+	}
+	return pkg.TypeOriginOf(typ)
+}
+
+// TypeOriginOf returns origin of typ. element type of pointer, slice, array and chan is used.
+// value type of map is used, or key type is used if value type is builtin.
+// modules are resolved by go.mod that contains directory of package.
+func (pkg *PackageInfo) TypeOriginOf(typ types.Type) *TypeOrigin {
+	for {
+		switch t := typ.(type) {
+		case *types.Map:
+			if origin := pkg.TypeOriginOf(t.Elem()); origin.Kind != OriginBuiltin {
+				return origin
+			}
+			typ = t.Key()
+			continue
+		case *types.Pointer:
+			typ = t.Elem()
+			continue
+		case *types.Slice:
+			typ = t.Elem()
+			continue
+		case *types.Array:
+			typ = t.Elem()
+			continue
+		case *types.Chan:
+			typ = t.Elem()
+			continue
+		}
+		break
+	}
+
+	named, ok := typ.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return &TypeOrigin{Kind: OriginBuiltin}
+	}
+	typesPkg := named.Obj().Pkg()
+	origin := &TypeOrigin{Package: typesPkg.Path()}
+	if typesPkg == pkg.Types {
+		origin.Kind = OriginSamePackage
+		origin.Package = pkg.ImportPath()
+		return origin
+	}
+
+	mod := pkg.modFile()
+	switch {
+	case hasPathPrefix(origin.Package, mod.module):
+		origin.Kind = OriginSameModule
+		origin.Module = mod.module
+	case isStdlibPath(origin.Package):
+		origin.Kind = OriginStdlib
+	default:
+		origin.Kind = OriginExternal
+		origin.Module = mod.moduleOf(origin.Package)
+		origin.Replace = mod.replaces[origin.Module]
+	}
+	return origin
+}
+
+// isStdlibPath returns true if first element of import path has no dot, otherwise returns false.
+func isStdlibPath(path string) bool {
+	elem := path
+	if idx := strings.Index(path, "/"); idx != -1 {
+		elem = path[:idx]
+	}
+	return !strings.Contains(elem, ".")
+}
+
+// modFile returns go.mod of package, it is read once. empty modFile is returned if go.mod is not found.
+func (pkg *PackageInfo) modFile() *modFile {
+	pkg.cacheMu.Lock()
+	defer pkg.cacheMu.Unlock()
+	if pkg.mod == nil {
+		pkg.mod = &modFile{}
+		if b, err := readGoMod(pkg.Dir); err == nil && b != nil {
+			pkg.mod = parseModFile(b)
+		}
+	}
+	return pkg.mod
+}
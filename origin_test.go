@@ -0,0 +1,66 @@
+package genbase
+
+import (
+	"testing"
+)
+
+func TestFieldInfoTypeOrigin(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSources(map[string]string{
+		"main.go": "package sample\n" +
+			"import (\n" +
+			"	\"time\"\n" +
+			"	\"example.com/app/model\"\n" +
+			"	\"github.com/google/uuid\"\n" +
+			"	\"example.org/unknown\"\n" +
+			")\n" +
+			"type Sample struct {\n" +
+			"	Count   int\n" +
+			"	Err     error\n" +
+			"	Attrs   map[string]int\n" +
+			"	At      *time.Time\n" +
+			"	Self    []*Sample\n" +
+			"	User    model.User\n" +
+			"	ID      uuid.UUID\n" +
+			"	Unknown unknown.Thing\n" +
+			"	ByID    map[uuid.UUID]int\n" +
+			"	Users   map[string][]model.User\n" +
+			"}\n",
+	}, map[string]map[string]string{
+		"example.com/app/model":  {"model.go": "package model\ntype User struct{}\n"},
+		"github.com/google/uuid": {"uuid.go": "package uuid\ntype UUID [16]byte\n"},
+		"example.org/unknown":    {"unknown.go": "package unknown\ntype Thing struct{}\n"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pInfo.mod = parseModFile([]byte("module example.com/app\n\nrequire github.com/google/uuid v1.3.0\n\nreplace github.com/google/uuid => ../uuid\n"))
+
+	st, err := pInfo.CollectTypeInfos([]string{"Sample"})[0].StructType()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []TypeOrigin{
+		{Kind: OriginBuiltin},
+		{Kind: OriginBuiltin},
+		{Kind: OriginBuiltin},
+		{Kind: OriginStdlib, Package: "time"},
+		{Kind: OriginSamePackage, Package: pInfo.ImportPath()},
+		{Kind: OriginSameModule, Package: "example.com/app/model", Module: "example.com/app"},
+		{Kind: OriginExternal, Package: "github.com/google/uuid", Module: "github.com/google/uuid", Replace: "../uuid"},
+		{Kind: OriginExternal, Package: "example.org/unknown"},
+		{Kind: OriginExternal, Package: "github.com/google/uuid", Module: "github.com/google/uuid", Replace: "../uuid"},
+		{Kind: OriginSameModule, Package: "example.com/app/model", Module: "example.com/app"},
+	}
+	for i, f := range st.FieldInfos() {
+		if origin := f.TypeOrigin(pInfo); *origin != expected[i] {
+			t.Errorf("unexpected: %s %+v", f.Name(), origin)
+		}
+	}
+
+	pInfo.Types = nil
+	pInfo.TypesInfo = nil
+	if origin := st.FieldInfos()[0].TypeOrigin(pInfo); origin.Kind != OriginUnknown {
+		t.Errorf("unexpected: %+v", origin)
+	}
+}
@@ -0,0 +1,270 @@
+package genbase
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// Output writes generated files, or previews diffs of them without writing if PreviewDiff is true.
+// e.g. generator CLI sets PreviewDiff by --diff flag, and CI fails if Stale returns true.
+type Output struct {
+	PreviewDiff bool
+	Diffs       []*FileDiff // diffs of files that differ from existing ones, in order of WriteFile
+}
+
+// FileDiff is unified diff between existing file and generated one.
+type FileDiff struct {
+	FileName string
+	Unified  string
+}
+
+// WriteFile writes src to fileName, or records its FileDiff if PreviewDiff is true.
+// existing file that has same content is not written either way.
+func (o *Output) WriteFile(fileName string, src []byte) error {
+	existing, err := ioutil.ReadFile(fileName)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err == nil && bytes.Equal(existing, src) {
+		return nil
+	}
+	if o.PreviewDiff {
+		o.Diffs = append(o.Diffs, &FileDiff{FileName: fileName, Unified: UnifiedDiff(fileName, existing, src)})
+		return nil
+	}
+	return ioutil.WriteFile(fileName, src, 0644)
+}
+
+// Stale returns true if previewed files differ from existing ones, otherwise returns false.
+func (o *Output) Stale() bool {
+	return len(o.Diffs) != 0
+}
+
+// String returns unified diffs of all files.
+func (o *Output) String() string {
+	var buf strings.Builder
+	for _, d := range o.Diffs {
+		buf.WriteString(d.Unified)
+	}
+	return buf.String()
+}
+
+// WriteFile formats accumulated output and writes it to fileName by Output.
+// returns error without writing if output can't be formatted, so existing file is kept.
+func (g *Generator) WriteFile(o *Output, fileName string) error {
+	src, err := g.Format()
+	if err != nil {
+		return err
+	}
+	return o.WriteFile(fileName, src)
+}
+
+// diffContext is number of context lines around changes in unified diff.
+const diffContext = 3
+
+// diffOp is operation of line diff.
+type diffOp struct {
+	kind byte // ' ', '-' or '+'
+	line string
+	a, b int // indices of line in old and new
+}
+
+// UnifiedDiff returns unified diff from old to new of fileName, or empty string if they are same.
+func UnifiedDiff(fileName string, old, new []byte) string {
+	if bytes.Equal(old, new) {
+		return ""
+	}
+	ops := diffLines(splitLines(old), splitLines(new))
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "--- a/%s\n+++ b/%s\n", fileName, fileName)
+	for start := 0; start < len(ops); {
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+		// hunk ends when changes are separated by more than twice of context lines.
+		end := start
+		for i := start; i < len(ops); i++ {
+			if ops[i].kind != ' ' {
+				end = i + 1
+			} else if i-end >= 2*diffContext {
+				break
+			}
+		}
+		from := start - diffContext
+		if from < 0 {
+			from = 0
+		}
+		to := end + diffContext
+		if to > len(ops) {
+			to = len(ops)
+		}
+
+		aStart, bStart := ops[from].a, ops[from].b
+		aCount, bCount := 0, 0
+		for _, op := range ops[from:to] {
+			if op.kind != '+' {
+				aCount++
+			}
+			if op.kind != '-' {
+				bCount++
+			}
+		}
+		fmt.Fprintf(&buf, "@@ -%s +%s @@\n", hunkRange(aStart, aCount), hunkRange(bStart, bCount))
+		for _, op := range ops[from:to] {
+			buf.WriteByte(op.kind)
+			buf.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				buf.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		start = to
+	}
+	return buf.String()
+}
+
+// hunkRange returns range of hunk header, start is 0-based index of first line.
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// splitLines splits b into lines that keep newlines.
+func splitLines(b []byte) []string {
+	var lines []string
+	s := string(b)
+	for s != "" {
+		idx := strings.IndexByte(s, '\n')
+		if idx == -1 {
+			lines = append(lines, s)
+			break
+		}
+		lines = append(lines, s[:idx+1])
+		s = s[idx+1:]
+	}
+	return lines
+}
+
+// diffLines returns shortest edit script from a to b by linear space variant of Myers' algorithm.
+// indices of insertion in a, and of deletion in b, are positions where they are applied.
+func diffLines(a, b []string) []diffOp {
+	d := &differ{a: a, b: b, ops: make([]diffOp, 0, len(a)+len(b))}
+	max := (len(a)+len(b)+1)/2 + 1
+	d.vf = make([]int, 2*max+3)
+	d.vb = make([]int, 2*max+3)
+	d.compare(0, len(a), 0, len(b))
+	return d.ops
+}
+
+// differ computes edit script between a and b, vf and vb are furthest reaching paths of forward and backward search.
+type differ struct {
+	a, b   []string
+	vf, vb []int
+	ops    []diffOp
+}
+
+// compare appends edit script from a[aLo:aHi] to b[bLo:bHi] to ops.
+func (d *differ) compare(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		d.ops = append(d.ops, diffOp{kind: ' ', line: d.a[aLo], a: aLo, b: bLo})
+		aLo++
+		bLo++
+	}
+	suffix := 0
+	for aLo < aHi-suffix && bLo < bHi-suffix && d.a[aHi-1-suffix] == d.b[bHi-1-suffix] {
+		suffix++
+	}
+	aHi -= suffix
+	bHi -= suffix
+
+	switch {
+	case aLo == aHi || bLo == bHi:
+		d.replace(aLo, aHi, bLo, bHi)
+	default:
+		x, y, u, v, ok := d.middleSnake(aLo, aHi, bLo, bHi)
+		if !ok {
+			d.replace(aLo, aHi, bLo, bHi)
+			break
+		}
+		d.compare(aLo, x, bLo, y)
+		for ; x < u; x, y = x+1, y+1 {
+			d.ops = append(d.ops, diffOp{kind: ' ', line: d.a[x], a: x, b: y})
+		}
+		d.compare(u, aHi, v, bHi)
+	}
+
+	for i := 0; i < suffix; i++ {
+		d.ops = append(d.ops, diffOp{kind: ' ', line: d.a[aHi+i], a: aHi + i, b: bHi + i})
+	}
+}
+
+// replace appends deletions of a[aLo:aHi] and insertions of b[bLo:bHi] to ops.
+func (d *differ) replace(aLo, aHi, bLo, bHi int) {
+	for i := aLo; i < aHi; i++ {
+		d.ops = append(d.ops, diffOp{kind: '-', line: d.a[i], a: i, b: bLo})
+	}
+	for j := bLo; j < bHi; j++ {
+		d.ops = append(d.ops, diffOp{kind: '+', line: d.b[j], a: aHi, b: j})
+	}
+}
+
+// middleSnake returns snake from (x, y) to (u, v) in middle of shortest edit script of a[aLo:aHi] and b[bLo:bHi].
+// ranges must not be empty, and must not start nor end with same lines. returns false if snake is not found.
+func (d *differ) middleSnake(aLo, aHi, bLo, bHi int) (x, y, u, v int, ok bool) {
+	n, m := aHi-aLo, bHi-bLo
+	delta := n - m
+	odd := delta%2 != 0
+	off := (n+m+1)/2 + 1
+	d.vf[off+1] = 0
+	d.vb[off+1] = 0
+	for step := 0; step <= (n+m+1)/2; step++ {
+		for k := -step; k <= step; k += 2 {
+			var px int
+			if k == -step || k != step && d.vf[off+k-1] < d.vf[off+k+1] {
+				px = d.vf[off+k+1]
+			} else {
+				px = d.vf[off+k-1] + 1
+			}
+			py := px - k
+			sx, sy := px, py
+			for px < n && py < m && d.a[aLo+px] == d.b[bLo+py] {
+				px++
+				py++
+			}
+			d.vf[off+k] = px
+			if rk := delta - k; odd && -(step-1) <= rk && rk <= step-1 && px+d.vb[off+rk] >= n {
+				return aLo + sx, bLo + sy, aLo + px, bLo + py, true
+			}
+		}
+		for k := -step; k <= step; k += 2 {
+			var px int
+			if k == -step || k != step && d.vb[off+k-1] < d.vb[off+k+1] {
+				px = d.vb[off+k+1]
+			} else {
+				px = d.vb[off+k-1] + 1
+			}
+			py := px - k
+			sx, sy := px, py
+			for px < n && py < m && d.a[aHi-1-px] == d.b[bHi-1-py] {
+				px++
+				py++
+			}
+			d.vb[off+k] = px
+			if fk := delta - k; !odd && -step <= fk && fk <= step && px+d.vf[off+fk] >= n {
+				return aHi - px, bHi - py, aHi - sx, bHi - sy, true
+			}
+		}
+	}
+	return 0, 0, 0, 0, false
+}
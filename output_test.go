@@ -0,0 +1,165 @@
+package genbase
+
+import (
+	"io/ioutil"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	old := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\n"
+	new := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\nn"
+	expect := `--- a/x.go
++++ b/x.go
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -11,3 +11,4 @@
+ k
+ l
+ m
++n
+\ No newline at end of file
+`
+	if v := UnifiedDiff("x.go", []byte(old), []byte(new)); v != expect {
+		t.Errorf("unexpected: %s", v)
+	}
+
+	expect = "--- a/x.go\n+++ b/x.go\n@@ -0,0 +1,2 @@\n+a\n+b\n"
+	if v := UnifiedDiff("x.go", nil, []byte("a\nb\n")); v != expect {
+		t.Errorf("unexpected: %s", v)
+	}
+	expect = "--- a/x.go\n+++ b/x.go\n@@ -1,4 +1,4 @@\n a\n-b\n c\n+x\n d\n"
+	if v := UnifiedDiff("x.go", []byte("a\nb\nc\nd\n"), []byte("a\nc\nx\nd\n")); v != expect {
+		t.Errorf("unexpected: %s", v)
+	}
+	if v := UnifiedDiff("x.go", []byte(old), []byte(old)); v != "" {
+		t.Errorf("unexpected: %s", v)
+	}
+}
+
+func TestOutputPreviewDiff(t *testing.T) {
+	dir, err := ioutil.TempDir("", "output")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", "package sample\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGenerator(pInfo)
+	g.PrintHeader("sample", &[]string{})
+	g.Printf("var a = 1\n")
+
+	fileName := filepath.Join(dir, "sample_gen.go")
+	o := &Output{PreviewDiff: true}
+	if err := g.WriteFile(o, fileName); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(fileName); !os.IsNotExist(err) {
+		t.Fatalf("unexpected: %v", err)
+	}
+	if !o.Stale() || len(o.Diffs) != 1 || !strings.Contains(o.String(), "@@ -0,0 +1,7 @@\n") {
+		t.Fatalf("unexpected: %s", o)
+	}
+
+	o = &Output{}
+	if err := g.WriteFile(o, fileName); err != nil {
+		t.Fatal(err)
+	}
+	o = &Output{PreviewDiff: true}
+	if err := g.WriteFile(o, fileName); err != nil {
+		t.Fatal(err)
+	}
+	if o.Stale() {
+		t.Fatalf("unexpected: %s", o)
+	}
+
+	g.Printf("var b = 2\n")
+	if err := g.WriteFile(o, fileName); err != nil {
+		t.Fatal(err)
+	}
+	if !o.Stale() || !strings.HasSuffix(o.Diffs[0].Unified, " var a = 1\n+var b = 2\n") {
+		t.Errorf("unexpected: %s", o)
+	}
+
+	before, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Printf("var = broken\n")
+	if err := g.WriteFile(&Output{}, fileName); err == nil {
+		t.Errorf("unexpected: %v", err)
+	}
+	if after, err := ioutil.ReadFile(fileName); err != nil || string(after) != string(before) {
+		t.Errorf("unexpected: %s %v", after, err)
+	}
+}
+
+func TestDiffLinesShortest(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(1, 2))
+	randomLines := func() []string {
+		lines := make([]string, rnd.IntN(12))
+		for i := range lines {
+			lines[i] = string(rune('a' + rnd.IntN(3)))
+		}
+		return lines
+	}
+	for n := 0; n < 500; n++ {
+		a, b := randomLines(), randomLines()
+		ops := diffLines(a, b)
+
+		var gotA, gotB []string
+		edits := 0
+		for _, op := range ops {
+			if op.kind != '+' {
+				if op.a != len(gotA) {
+					t.Fatalf("unexpected: %q %q %v", a, b, ops)
+				}
+				gotA = append(gotA, op.line)
+			}
+			if op.kind != '-' {
+				if op.b != len(gotB) {
+					t.Fatalf("unexpected: %q %q %v", a, b, ops)
+				}
+				gotB = append(gotB, op.line)
+			}
+			if op.kind != ' ' {
+				edits++
+			}
+		}
+		if strings.Join(gotA, "") != strings.Join(a, "") || strings.Join(gotB, "") != strings.Join(b, "") {
+			t.Fatalf("unexpected: %q %q %v", a, b, ops)
+		}
+
+		// length of longest common subsequence by dynamic programming.
+		lcs := make([][]int, len(a)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(b)+1)
+		}
+		for i := len(a) - 1; i >= 0; i-- {
+			for j := len(b) - 1; j >= 0; j-- {
+				if a[i] == b[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else if lcs[i+1][j] > lcs[i][j+1] {
+					lcs[i][j] = lcs[i+1][j]
+				} else {
+					lcs[i][j] = lcs[i][j+1]
+				}
+			}
+		}
+		if expect := len(a) + len(b) - 2*lcs[0][0]; edits != expect {
+			t.Fatalf("unexpected: %q %q %d %d", a, b, edits, expect)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package genbase
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"go/ast"
@@ -9,24 +10,144 @@ import (
 	"go/parser"
 	"go/token"
 	"go/types"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 var (
 	// ErrNotStructType shows argument is not ast.StructType.
 	ErrNotStructType = errors.New("type is not ast.StructType")
+	// ErrCgoFile shows file uses cgo, reported by StrictFileCheck.
+	ErrCgoFile = errors.New("file uses cgo")
+	// ErrAssemblyFile shows file is assembly source, reported by StrictFileCheck.
+	ErrAssemblyFile = errors.New("file is assembly source")
+	// ErrCaseCollision shows file names differ only in case, they can't coexist on case-insensitive file system.
+	ErrCaseCollision = errors.New("case-insensitive file name collision")
 )
 
 // Parser is center of parsing strategy.
 type Parser struct {
 	SkipSemanticsCheck bool
+	// StrictFileCheck makes parsing fail when cgo or assembly files are found,
+	// because genbase can't see the declarations in them.
+	StrictFileCheck bool
+	// Importer resolves imported packages. importer.Default() is used if nil.
+	Importer types.Importer
+	// UseExportData makes dependencies loaded from compiled export data in build cache
+	// instead of importer.Default(). it is ignored when Importer is specified.
+	UseExportData bool
+	// ExportDataCacheDir is directory to cache locations of export data across runs, it is used with UseExportData.
+	// locations are cached only for standard and versioned module packages. cache is disabled if it is empty.
+	// see DefaultExportDataCacheDir.
+	ExportDataCacheDir string
+	// StatsCallback is called with elapsed time after each phase, if it is specified.
+	StatsCallback func(phase Phase, elapsed time.Duration)
+	// Context is parent of pprof labels of phases, so labels of caller are kept in profiles.
+	// context.Background() is used if nil.
+	Context context.Context
+	// CommentAssociation is strategy to associate comments with types for annotation discovery.
+	CommentAssociation CommentAssociation
+	// StrictDuplicateCheck makes FindTaggedTypeInfos and CheckGenerated fail
+	// when types or identifiers are declared twice, or a type is annotated twice with conflicting options.
+	StrictDuplicateCheck bool
+	// UnexportedPolicy is policy of FindTaggedTypeInfos for annotated types that are unexported or have no exported fields.
+	UnexportedPolicy UnexportedPolicy
+	// IncludeFiles and ExcludeFiles are patterns of filepath.Match for base names of files,
+	// ParsePackageDir and ParsePackageFiles parse only files that match one of IncludeFiles if it is not empty,
+	// and don't match any of ExcludeFiles. e.g. ExcludeFiles: []string{"*_mock.go"}
+	IncludeFiles []string
+	ExcludeFiles []string
+	// PrescanTags makes parsing skip Go files that contain none of tags in bytes, unless annotated files use them.
+	// skipped files are scanned for their top-level declarations without parsing, and types declared in them
+	// are not collected. e.g. []string{"+qbg"}
+	PrescanTags []string
+	// DeferTypeCheck makes parsing light and skip type checking, Types and TypesInfo of package are nil after parsing.
+	// files are parsed without bodies of funcs first, that is enough to find annotated types.
+	// CollectTaggedTypeInfos, CollectTypeInfos and FindTaggedTypeInfos fully parse and type check files
+	// that declare found types and files they use on first use, so unrelated files are not parsed nor type checked.
+	// types are cached, and checked again with former files when later collection needs other files.
+	// AfterTypeCheck is called after each type checking.
+	DeferTypeCheck bool
+	// Sizes computes sizes and alignments of types. gc sizes of build.Default.GOARCH is used if nil.
+	Sizes types.Sizes
+	// GoVersion is language version for type checking. e.g. "go1.18"
+	// if it is empty, ParsePackageDir reads go directive of go.mod.
+	GoVersion string
+	// BuildTags are additional build tags to evaluate build constraints of files.
+	BuildTags []string
+	// Logger receives diagnostic messages while parsing, and is inherited by Generator of parsed package.
+	// messages are discarded if it is nil.
+	Logger Logger
+	// BeforeParseFile is called with source of each file before parsing, if it is specified.
+	// returned bytes are parsed instead of src, so it can preprocess source. e.g. stripping directives.
+	BeforeParseFile func(fileName string, src []byte) ([]byte, error)
+	// AfterParseFile is called with each parsed file, if it is specified.
+	// it is called when file is fully parsed with DeferTypeCheck.
+	AfterParseFile func(fileName string, file *FileInfo) error
+	// AfterTypeCheck is called with package after type checking, if it is specified.
+	// Types and TypesInfo are nil when type checking fails with SkipSemanticsCheck.
+	AfterTypeCheck func(pkg *PackageInfo) error
 }
 
 // PackageInfo is specified package informations.
 type PackageInfo struct {
-	Dir   string
-	Files FileInfos
-	Types *types.Package
+	Dir          string
+	FileSet      *token.FileSet
+	Files        FileInfos
+	Types        *types.Package
+	TypesInfo    *types.Info
+	SkippedFiles []*SkippedFile
+	// GoVersion is language version used for type checking. "" means latest.
+	GoVersion string
+	// BuildPackage is result of build.ImportDir, set by ParsePackageDir only.
+	BuildPackage *build.Package
+	// CommentAssociation is strategy to associate comments with types for annotation discovery.
+	CommentAssociation CommentAssociation
+	// StrictDuplicateCheck is copied from Parser.
+	StrictDuplicateCheck bool
+	// UnexportedPolicy is copied from Parser.
+	UnexportedPolicy UnexportedPolicy
+
+	typesConfig   types.Config
+	statsMu       sync.Mutex
+	stats         Stats
+	statsCallback func(phase Phase, elapsed time.Duration)
+	ctx           context.Context
+	logger        Logger
+	cacheMu       sync.Mutex
+	commentMaps   map[*FileInfo]ast.CommentMap
+	sources       map[string][]byte
+	mod           *modFile
+	typeDeclCache *typeDeclCache
+	deferred      *deferredCheck
+	config        *Config
+	importPath    string
+}
+
+// SkipReason is the reason why file was not parsed.
+type SkipReason string
+
+const (
+	// SkipNotGoFile shows file is not Go source.
+	SkipNotGoFile SkipReason = "not a Go source file"
+	// SkipAssemblyFile shows file is assembly source.
+	SkipAssemblyFile SkipReason = "assembly source file"
+	// SkipTestFile shows file is test file.
+	SkipTestFile SkipReason = "test file"
+	// SkipBuildConstraints shows file is excluded by build constraints.
+	SkipBuildConstraints SkipReason = "excluded by build constraints"
+	// SkipDuplicateFile shows file is same as other file through symlink or case-insensitive file system.
+	SkipDuplicateFile SkipReason = "same file as other file"
+)
+
+// SkippedFile is file that was found but not parsed.
+type SkippedFile struct {
+	Name   string
+	Reason SkipReason
 }
 
 // FileInfo is ast.File synonym.
@@ -38,10 +159,16 @@ type FileInfos []*FileInfo
 // TypeInfo is type information gathering.
 // try http://goast.yuroyoro.net/ with http://play.golang.org/p/ruqMMsbDaw
 type TypeInfo struct {
+	PackageInfo      *PackageInfo
 	FileInfo         *FileInfo
 	GenDecl          *ast.GenDecl
 	TypeSpec         *ast.TypeSpec
 	AnnotatedComment *ast.Comment
+	// AnnotationInfo is first annotation of tag, set by CollectTaggedTypeInfos alongside AnnotatedComment.
+	AnnotationInfo *AnnotationInfo
+	// Annotations are all annotations of tag, set by CollectTaggedTypeInfos.
+	// it is nil if annotations are malformed, errors are reported by TypeInfo.AllAnnotations.
+	Annotations []*AnnotationInfo
 }
 
 // TypeInfos is []*TypeInfo synonym.
@@ -57,76 +184,384 @@ type FieldInfo ast.Field
 type FieldInfos []*FieldInfo
 
 // ParsePackageDir parses specified directory.
+// symlinks in directory are resolved, so Dir of PackageInfo is same for all paths to the package.
 func (p *Parser) ParsePackageDir(directory string) (*PackageInfo, error) {
-	pkg, err := build.Default.ImportDir(directory, 0)
+	directory = realPath(normalizePath(directory))
+	ctxt := p.buildContext()
+	pkg, err := ctxt.ImportDir(directory, 0)
 	if err != nil {
-		return nil, fmt.Errorf("cannot process directory %s: %s", directory, err)
+		return nil, p.importDirError(directory, pkg, err)
 	}
 	var names []string
 	names = append(names, pkg.GoFiles...)
 	names = append(names, pkg.CgoFiles...)
 	names = append(names, pkg.SFiles...)
-	names = pathJoinAll(directory, names...)
-	return p.parsePackage(directory, names, nil)
+	names, skipped, err := dedupeFiles(pathJoinAll(directory, names...))
+	if err != nil {
+		return nil, err
+	}
+	names, filtered, err := p.filterFiles(names)
+	if err != nil {
+		return nil, err
+	}
+	skipped = append(skipped, filtered...)
+	skipped = append(skipped, ignoredFiles(directory, pkg)...)
+
+	goVersion, err := p.goVersion(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	pInfo, err := p.parsePackage(directory, names, nil, goVersion)
+	if noGoErr, ok := err.(*NoGoFilesError); ok {
+		noGoErr.Skipped = append(skipped, noGoErr.Skipped...)
+		return nil, noGoErr
+	} else if err != nil {
+		return nil, err
+	}
+	pInfo.SkippedFiles = append(skipped, pInfo.SkippedFiles...)
+	pInfo.BuildPackage = pkg
+	p.logSkippedFiles(pInfo.SkippedFiles)
+	return pInfo, nil
 }
 
 // ParsePackageFiles parses specified files.
+// Go files that are excluded by build constraints or file names for GOOS, GOARCH and BuildTags are skipped.
+// files that are same as former files through symlinks or case-insensitive file system are skipped too.
 func (p *Parser) ParsePackageFiles(fileNames []string) (*PackageInfo, error) {
-	return p.parsePackage(".", fileNames, nil)
+	ctxt := p.buildContext()
+	fileNames, skipped, err := dedupeFiles(fileNames)
+	if err != nil {
+		return nil, err
+	}
+	fileNames, filtered, err := p.filterFiles(fileNames)
+	if err != nil {
+		return nil, err
+	}
+	skipped = append(skipped, filtered...)
+	var names []string
+	for _, name := range fileNames {
+		if strings.HasSuffix(name, ".go") {
+			match, err := ctxt.MatchFile(filepath.Dir(name), filepath.Base(name))
+			if err != nil {
+				return nil, fmt.Errorf("parsing package: %s: %s", name, err)
+			}
+			if !match {
+				skipped = appendSkippedFiles(skipped, SkipBuildConstraints, name)
+				continue
+			}
+		}
+		names = append(names, name)
+	}
+
+	pInfo, err := p.parsePackage(".", names, nil, p.GoVersion)
+	if err != nil {
+		return nil, err
+	}
+	pInfo.SkippedFiles = append(skipped, pInfo.SkippedFiles...)
+	p.logSkippedFiles(pInfo.SkippedFiles)
+	return pInfo, nil
+}
+
+// ParseSingleFile parses a file without environment, GOPATH and go.mod are not needed.
+// imports are not resolved, so types are resolved only if file doesn't use imported packages.
+// TypeInfos and annotations are available regardless of types.
+func (p *Parser) ParseSingleFile(path string) (*PackageInfo, error) {
+	path = normalizePath(path)
+	quick := *p
+	quick.SkipSemanticsCheck = true
+	quick.UseExportData = false
+	quick.Importer = stubImporter{}
+	return quick.parsePackage(filepath.Dir(path), []string{path}, nil, p.GoVersion)
+}
+
+// stubImporter returns empty package for each import path.
+type stubImporter struct{}
+
+func (stubImporter) Import(path string) (*types.Package, error) {
+	pkg := types.NewPackage(path, guessPackageName(path))
+	pkg.MarkComplete()
+	return pkg, nil
+}
+
+// buildContext returns build.Context that has BuildTags.
+func (p *Parser) buildContext() *build.Context {
+	ctxt := build.Default
+	ctxt.BuildTags = append(append([]string(nil), ctxt.BuildTags...), p.BuildTags...)
+	return &ctxt
 }
 
 func (p *Parser) ParseStringSource(fileName string, code string) (*PackageInfo, error) {
-	return p.parsePackage(".", []string{fileName}, []string{code})
+	return p.parsePackage(".", []string{fileName}, []string{code}, p.GoVersion)
 }
 
-func (p *Parser) parsePackage(directory string, fileNames []string, codes []string) (*PackageInfo, error) {
+func (p *Parser) parsePackage(directory string, fileNames []string, codes []string, goVersion string) (*PackageInfo, error) {
+	return p.parsePackageWithFileSet(token.NewFileSet(), directory, "", fileNames, codes, goVersion)
+}
+
+// parsePackageWithFileSet parses files in fs, importPath is path of types.Package if it is not empty, otherwise directory is.
+func (p *Parser) parsePackageWithFileSet(fs *token.FileSet, directory string, importPath string, fileNames []string, codes []string, goVersion string) (*PackageInfo, error) {
+	var files FileInfos
+	pkg := &PackageInfo{
+		GoVersion:            goVersion,
+		importPath:           importPath,
+		CommentAssociation:   p.CommentAssociation,
+		StrictDuplicateCheck: p.StrictDuplicateCheck,
+		UnexportedPolicy:     p.UnexportedPolicy,
+		statsCallback:        p.StatsCallback,
+		ctx:                  p.Context,
+		logger:               p.Logger,
+	}
+	var err error
+	pkg.measure(PhaseParse, func() {
+		if len(p.PrescanTags) != 0 {
+			files, err = p.parsePrescannedFiles(pkg, fs, fileNames, codes)
+		} else {
+			files, err = p.parseFiles(pkg, fs, fileNames, codes)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, &NoGoFilesError{Dir: directory, Skipped: pkg.SkippedFiles}
+	}
+	pkg.Files = files
+	pkg.Dir = directory
+	pkg.FileSet = fs
+
+	if p.DeferTypeCheck {
+		pkg.deferred = &deferredCheck{parser: p, checked: make(map[*FileInfo]bool)}
+	} else if err := p.checkPackage(pkg); err != nil {
+		return nil, err
+	}
+	p.logger().Infof("parsed package %s in %s: %d files", pkg.Name(), directory, len(files))
+
+	return pkg, nil
+}
+
+func (p *Parser) parseFiles(pkg *PackageInfo, fs *token.FileSet, fileNames []string, codes []string) (FileInfos, error) {
 	var files FileInfos
-	pkg := &PackageInfo{}
-	fs := token.NewFileSet()
 	for idx, fileName := range fileNames {
+		if strings.HasSuffix(fileName, ".s") || strings.HasSuffix(fileName, ".S") {
+			if p.StrictFileCheck {
+				return nil, fmt.Errorf("parsing package: %s: %w", fileName, ErrAssemblyFile)
+			}
+			pkg.SkippedFiles = appendSkippedFiles(pkg.SkippedFiles, SkipAssemblyFile, fileName)
+			continue
+		}
 		if !strings.HasSuffix(fileName, ".go") {
+			pkg.SkippedFiles = appendSkippedFiles(pkg.SkippedFiles, SkipNotGoFile, fileName)
 			continue
 		}
 		var code interface{}
 		if idx < len(codes) {
 			code = codes[idx]
 		}
-		parsedFile, err := parser.ParseFile(fs, fileName, code, parser.ParseComments)
+		file, src, err := p.parseFile(fs, fileName, code)
 		if err != nil {
-			return nil, fmt.Errorf("parsing package: %s: %s", fileName, err)
+			return nil, err
 		}
-		files = append(files, (*FileInfo)(parsedFile))
+		pkg.countFile(fs, file)
+		pkg.setSource(fileName, src)
+		files = append(files, file)
 	}
-	if len(files) == 0 {
-		return nil, fmt.Errorf("%s: no buildable Go files", directory)
+	return files, nil
+}
+
+// parseFile parses file, code is used as content of file if it is not nil.
+// it returns source bytes of file too.
+func (p *Parser) parseFile(fs *token.FileSet, fileName string, code interface{}) (*FileInfo, []byte, error) {
+	p.logger().Debugf("parsing %s", fileName)
+	src, err := readSource(fileName, code)
+	if err != nil {
+		return nil, nil, err
 	}
-	pkg.Files = files
-	pkg.Dir = directory
+	if p.BeforeParseFile != nil {
+		src, err = p.BeforeParseFile(fileName, src)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parsing package: %s: %w", fileName, err)
+		}
+	}
+	if p.DeferTypeCheck {
+		// bodies of funcs are parsed by resolveTypesOf when types of file are needed.
+		file, err := p.parseSource(fs, fileName, lightSource(fileName, src))
+		return file, src, err
+	}
+	file, err := p.parseSource(fs, fileName, src)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := p.afterParseFile(fileName, file); err != nil {
+		return nil, nil, err
+	}
+	return file, src, nil
+}
 
-	// resolve types
-	config := types.Config{
-		FakeImportC:              true,
-		Importer:                 importer.Default(),
-		IgnoreFuncBodies:         true,
-		DisableUnusedImportCheck: true,
+// parseSource parses src of file with comments.
+func (p *Parser) parseSource(fs *token.FileSet, fileName string, src []byte) (*FileInfo, error) {
+	parsedFile, err := parser.ParseFile(fs, fileName, src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parsing package: %s: %s", fileName, err)
+	}
+	if p.StrictFileCheck && (*FileInfo)(parsedFile).FindImportSpecByPath("C") != nil {
+		return nil, fmt.Errorf("parsing package: %s: %w", fileName, ErrCgoFile)
+	}
+	return (*FileInfo)(parsedFile), nil
+}
+
+// afterParseFile calls AfterParseFile with fully parsed file.
+func (p *Parser) afterParseFile(fileName string, file *FileInfo) error {
+	if p.AfterParseFile != nil {
+		if err := p.AfterParseFile(fileName, file); err != nil {
+			return fmt.Errorf("parsing package: %s: %w", fileName, err)
+		}
 	}
+	return nil
+}
+
+// readSource returns code as bytes, or content of file if code is nil.
+func readSource(fileName string, code interface{}) ([]byte, error) {
+	switch code := code.(type) {
+	case string:
+		return []byte(code), nil
+	case []byte:
+		return code, nil
+	}
+	src, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("parsing package: %s: %s", fileName, err)
+	}
+	return src, nil
+}
+
+// checkPackage resolves types of pkg.
+func (p *Parser) checkPackage(pkg *PackageInfo) error {
+	return p.checkFiles(pkg, pkg.Files)
+}
+
+// checkFiles resolves types of pkg by files, they are part of Files.
+func (p *Parser) checkFiles(pkg *PackageInfo, files FileInfos) error {
+	config := p.typesConfig(pkg.FileSet, pkg.Dir)
+	config.GoVersion = pkg.GoVersion
+	pkg.typesConfig = config
 	info := &types.Info{
-		Defs: make(map[*ast.Ident]types.Object),
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+		Implicits:  make(map[ast.Node]types.Object),
 	}
-	typesPkg, err := config.Check(pkg.Dir, fs, files.AstFiles(), info)
-	if p.SkipSemanticsCheck && err != nil {
-		return pkg, nil
+	var typesPkg *types.Package
+	var err error
+	pkg.measure(PhaseTypeCheck, func() {
+		path := pkg.Dir
+		if pkg.importPath != "" {
+			path = pkg.importPath
+		}
+		typesPkg, err = config.Check(path, pkg.FileSet, files.AstFiles(), info)
+	})
+	if err != nil && !p.SkipSemanticsCheck {
+		return err
 	} else if err != nil {
-		return nil, err
+		p.logger().Warnf("types of %s are not resolved: %s", pkg.Dir, err)
+	} else {
+		pkg.Types = typesPkg
+		pkg.TypesInfo = info
 	}
-	pkg.Types = typesPkg
 
-	return pkg, nil
+	if p.AfterTypeCheck != nil {
+		return p.AfterTypeCheck(pkg)
+	}
+	return nil
+}
+
+func (p *Parser) typesConfig(fs *token.FileSet, directory string) types.Config {
+	imp := p.Importer
+	if imp == nil && p.UseExportData {
+		exportImp := NewExportDataImporter(fs, directory)
+		exportImp.CacheDir = p.ExportDataCacheDir
+		imp = exportImp
+	} else if imp == nil {
+		imp = importer.Default()
+	}
+	sizes := p.Sizes
+	if sizes == nil {
+		sizes = types.SizesFor("gc", build.Default.GOARCH)
+	}
+	return types.Config{
+		FakeImportC:              true,
+		Importer:                 imp,
+		Sizes:                    sizes,
+		IgnoreFuncBodies:         true,
+		DisableUnusedImportCheck: true,
+	}
 }
 
 // TypeInfos is gathering TypeInfos, it included in package.
 func (pkg *PackageInfo) TypeInfos() TypeInfos {
 	var types TypeInfos
+	pkg.measure(PhaseCollect, func() {
+		types = pkg.typeInfos()
+	})
+	return types
+}
+
+func (pkg *PackageInfo) typeInfos() TypeInfos {
+	decls := pkg.typeDecls()
+	// TypeInfos are fresh for each call because CollectTaggedTypeInfos sets annotations to them,
+	// but they share one backing array.
+	backing := make([]TypeInfo, len(decls))
+	types := make(TypeInfos, len(decls))
+	for i, decl := range decls {
+		backing[i] = TypeInfo{
+			PackageInfo: pkg,
+			FileInfo:    decl.file,
+			GenDecl:     decl.genDecl,
+			TypeSpec:    decl.typeSpec,
+		}
+		types[i] = &backing[i]
+	}
+	return types
+}
+
+// eachTypeInfo calls yield with each TypeInfo in package until yield returns false.
+func (pkg *PackageInfo) eachTypeInfo(yield func(*TypeInfo) bool) bool {
+	for _, decl := range pkg.typeDecls() {
+		if !yield(&TypeInfo{
+			PackageInfo: pkg,
+			FileInfo:    decl.file,
+			GenDecl:     decl.genDecl,
+			TypeSpec:    decl.typeSpec,
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// typeDecl is declaration of type in file.
+type typeDecl struct {
+	file     *FileInfo
+	genDecl  *ast.GenDecl
+	typeSpec *ast.TypeSpec
+}
+
+// typeDeclCache is type declarations that are found in files.
+type typeDeclCache struct {
+	files []*FileInfo
+	decls []typeDecl
+}
+
+// typeDecls returns type declarations in package, they are cached until Files are changed.
+func (pkg *PackageInfo) typeDecls() []typeDecl {
+	pkg.cacheMu.Lock()
+	defer pkg.cacheMu.Unlock()
+	if cache := pkg.typeDeclCache; cache != nil && sameFiles(cache.files, pkg.Files) {
+		return cache.decls
+	}
+
+	var decls []typeDecl
 	for _, file := range pkg.Files {
 		if file == nil {
 			continue
@@ -142,37 +577,71 @@ func (pkg *PackageInfo) TypeInfos() TypeInfos {
 				if !ok {
 					continue
 				}
-				types = append(types, &TypeInfo{
-					FileInfo: file,
-					GenDecl:  decl,
-					TypeSpec: ts,
-				})
 				found = true
+				decls = append(decls, typeDecl{file: file, genDecl: decl, typeSpec: ts})
 			}
 			return !found
 		})
 	}
-	return types
+	pkg.typeDeclCache = &typeDeclCache{
+		files: append([]*FileInfo(nil), pkg.Files...),
+		decls: decls,
+	}
+	return decls
+}
+
+// sameFiles returns true if a and b have same files in same order, otherwise returns false.
+func sameFiles(a, b []*FileInfo) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
 }
 
 // CollectTaggedTypeInfos collects tagged TypeInfos.
+// errors of deferred type checking are logged, use FindTaggedTypeInfos to receive them.
 func (pkg *PackageInfo) CollectTaggedTypeInfos(tag string) TypeInfos {
+	ret, _ := pkg.collectTaggedTypeInfos(tag)
+	return ret
+}
+
+// collectTaggedTypeInfos collects tagged TypeInfos, and returns error of deferred type checking.
+func (pkg *PackageInfo) collectTaggedTypeInfos(tag string) (TypeInfos, error) {
 	ret := TypeInfos{}
 
 	types := pkg.TypeInfos()
 
+outer:
 	for _, t := range types {
-		if c := findAnnotation(t.Doc(), tag); c != nil {
-			t.AnnotatedComment = c
-			ret = append(ret, t)
+		for _, doc := range t.Comments() {
+			if c := findAnnotation(doc, tag); c != nil {
+				t.AnnotatedComment = c
+				t.setAnnotations(tag)
+				ret = append(ret, t)
+				continue outer
+			}
 		}
 	}
+	err := pkg.resolveTypesOf(ret)
 
-	return ret
+	return ret, err
 }
 
 // CollectTypeInfos collects specified TypeInfos.
+// names that are not found are ignored, use FindTypeInfos to report them.
+// errors of deferred type checking are logged, use FindTypeInfos to receive them.
 func (pkg *PackageInfo) CollectTypeInfos(typeNames []string) TypeInfos {
+	ret, _ := pkg.collectTypeInfos(typeNames)
+	return ret
+}
+
+// collectTypeInfos collects specified TypeInfos, and returns error of deferred type checking.
+func (pkg *PackageInfo) collectTypeInfos(typeNames []string) (TypeInfos, error) {
 	ret := TypeInfos{}
 
 	types := pkg.TypeInfos()
@@ -186,8 +655,18 @@ outer:
 			}
 		}
 	}
+	err := pkg.resolveTypesOf(ret)
 
-	return ret
+	return ret, err
+}
+
+func (pkg *PackageInfo) findTypeInfo(name string) *TypeInfo {
+	for _, t := range pkg.typeInfos() {
+		if t.Name() == name {
+			return t
+		}
+	}
+	return nil
 }
 
 // Name returns package name.
@@ -195,6 +674,18 @@ func (pkg *PackageInfo) Name() string {
 	return pkg.Files[0].Name.Name
 }
 
+// ImportPath returns import path of package.
+// returns empty string if it is unknown.
+func (pkg *PackageInfo) ImportPath() string {
+	if pkg.importPath != "" {
+		return pkg.importPath
+	}
+	if pkg.BuildPackage == nil || pkg.BuildPackage.ImportPath == "." {
+		return ""
+	}
+	return pkg.BuildPackage.ImportPath
+}
+
 // AstFile returns *ast.File.
 func (file *FileInfo) AstFile() *ast.File {
 	return (*ast.File)(file)
@@ -210,22 +701,55 @@ func (files FileInfos) AstFiles() []*ast.File {
 }
 
 // FindImportSpecByIdent finds *ast.ImportSpec by package ident.
+// packageIdent "." finds dot import.
+// package name of import without explicit name is guessed from import path,
+// use PackageInfo.FindImportSpecByIdent to resolve it by type information.
 func (file *FileInfo) FindImportSpecByIdent(packageIdent string) *ast.ImportSpec {
 	for _, imp := range file.Imports {
 		if imp.Name != nil && imp.Name.Name == packageIdent {
 			// import foo "foobar"
+			// import . "foo"
 			return imp
-		} else if strings.HasSuffix(imp.Path.Value, fmt.Sprintf(`/%s"`, packageIdent)) {
+		}
+	}
+	for _, imp := range file.Imports {
+		if imp.Name != nil {
+			continue
+		}
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		if path == packageIdent {
+			// import "foo"
+			return imp
+		} else if strings.HasSuffix(path, "/"+packageIdent) {
 			// import "favclip/foo"
 			return imp
-		} else if imp.Path.Value == fmt.Sprintf(`"%s"`, packageIdent) {
-			// import "foo"
+		} else if guessPackageName(path) == packageIdent {
+			// import "gopkg.in/foo.v2"
 			return imp
 		}
 	}
 	return nil
 }
 
+// FindImportSpecByIdent finds *ast.ImportSpec of file by package ident.
+// package name is resolved by type information if it is available.
+func (pkg *PackageInfo) FindImportSpecByIdent(file *FileInfo, packageIdent string) *ast.ImportSpec {
+	if pkg.TypesInfo != nil {
+		for _, imp := range file.Imports {
+			if imp.Name != nil {
+				continue
+			}
+			if pkgName, ok := pkg.TypesInfo.Implicits[imp].(*types.PkgName); ok && pkgName.Imported().Name() == packageIdent {
+				return imp
+			}
+		}
+	}
+	return file.FindImportSpecByIdent(packageIdent)
+}
+
 // StructType returns *StructTypeInfo.
 func (t *TypeInfo) StructType() (*StructTypeInfo, error) {
 	structType, ok := interface{}(t.TypeSpec.Type).(*ast.StructType)
@@ -241,6 +765,45 @@ func (t *TypeInfo) Name() string {
 	return t.TypeSpec.Name.Name
 }
 
+// TypeObject returns *types.TypeName of TypeInfo.
+// returns nil if types are not resolved.
+func (t *TypeInfo) TypeObject() *types.TypeName {
+	if t.PackageInfo == nil || t.PackageInfo.TypesInfo == nil {
+		return nil
+	}
+	obj, _ := t.PackageInfo.TypesInfo.Defs[t.TypeSpec.Name].(*types.TypeName)
+	return obj
+}
+
+// MethodSet returns method set of type.
+// method set of pointer type is returned when includePointerReceiver is true.
+// returns nil if types are not resolved.
+func (t *TypeInfo) MethodSet(includePointerReceiver bool) *types.MethodSet {
+	obj := t.TypeObject()
+	if obj == nil {
+		return nil
+	}
+	typ := obj.Type()
+	if includePointerReceiver {
+		typ = types.NewPointer(typ)
+	}
+	return types.NewMethodSet(typ)
+}
+
+// HasMethod returns true if type has method of specified name, otherwise returns false.
+func (t *TypeInfo) HasMethod(name string, includePointerReceiver bool) bool {
+	mset := t.MethodSet(includePointerReceiver)
+	if mset == nil {
+		return false
+	}
+	for i := 0; i < mset.Len(); i++ {
+		if mset.At(i).Obj().Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
 // Doc returns *ast.CommentGroup of TypeInfo.
 func (t *TypeInfo) Doc() *ast.CommentGroup {
 	if t.TypeSpec.Doc != nil {
@@ -259,23 +822,71 @@ func (st *StructTypeInfo) AstStructType() *ast.StructType {
 
 // FieldInfos returns FieldInfos of struct.
 func (st *StructTypeInfo) FieldInfos() FieldInfos {
-	var fields FieldInfos
-	for _, field := range st.AstStructType().Fields.List {
-		fields = append(fields, (*FieldInfo)(field))
+	list := st.AstStructType().Fields.List
+	if len(list) == 0 {
+		return nil
+	}
+	fields := make(FieldInfos, len(list))
+	for i, field := range list {
+		fields[i] = (*FieldInfo)(field)
 	}
 
 	return fields
 }
 
+// invalidTypeName is TypeName of field that has no type name.
+var invalidTypeName = fmt.Sprintf("!!%s!!", errCannotDetectTypeName.Error())
+
 // TypeName returns type name of field.
 func (f *FieldInfo) TypeName() string {
 	typeName, err := ExprToTypeName(f.Type)
-	if err != nil {
+	if err == errCannotDetectTypeName {
+		return invalidTypeName
+	} else if err != nil {
 		return fmt.Sprintf("!!%s!!", err.Error())
 	}
 	return typeName
 }
 
+// ResolveTypeInfo returns TypeInfo of the named type of field, "*" and "[]" are ignored.
+// the type is searched from pkg that contains field, or parsed packages for qualified type name.
+// package of qualified type name is resolved by type information if it is available.
+// returns nil if the type is not found.
+func (f *FieldInfo) ResolveTypeInfo(pkg *PackageInfo, parsed ...*PackageInfo) *TypeInfo {
+	typeName, err := ExprToBaseTypeName(f.Type)
+	if err != nil || typeName == "" {
+		return nil
+	}
+
+	idx := strings.Index(typeName, ".")
+	if idx == -1 {
+		return pkg.findTypeInfo(typeName)
+	}
+
+	ident := typeName[:idx]
+	var path string
+	for _, file := range pkg.Files {
+		if astFile, field := file.AstFile(), (*ast.Field)(f); astFile.Pos() <= field.Pos() && field.End() <= astFile.End() {
+			if imp := pkg.FindImportSpecByIdent(file, ident); imp != nil {
+				path, _ = strconv.Unquote(imp.Path.Value)
+			}
+			break
+		}
+	}
+	for _, other := range parsed {
+		if other.ImportPath() != "" && other.ImportPath() != path {
+			continue
+		}
+		if other.ImportPath() == "" && other.Name() != ident {
+			continue
+		}
+		if t := other.findTypeInfo(typeName[idx+1:]); t != nil {
+			return t
+		}
+	}
+	return nil
+}
+
 // IsPtr returns true if FieldInfo is pointer, otherwise returns false.
 func (f *FieldInfo) IsPtr() bool {
 	_, ok := f.Type.(*ast.StarExpr)
@@ -324,47 +935,27 @@ func (f *FieldInfo) IsPtrArrayPtr() bool {
 
 // IsInt64 returns true if FieldInfo is int64, otherwise returns false.
 func (f *FieldInfo) IsInt64() bool {
-	typeName, err := ExprToBaseTypeName(f.Type)
-	if err != nil {
-		return false
-	}
-	return typeName == "int64"
+	return f.isBaseType("", "int64")
 }
 
 // IsInt returns true if FieldInfo is int, otherwise returns false.
 func (f *FieldInfo) IsInt() bool {
-	typeName, err := ExprToBaseTypeName(f.Type)
-	if err != nil {
-		return false
-	}
-	return typeName == "int"
+	return f.isBaseType("", "int")
 }
 
 // IsString returns true if FieldInfo is string, otherwise returns false.
 func (f *FieldInfo) IsString() bool {
-	typeName, err := ExprToBaseTypeName(f.Type)
-	if err != nil {
-		return false
-	}
-	return typeName == "string"
+	return f.isBaseType("", "string")
 }
 
 // IsFloat32 returns true if FieldInfo is float32, otherwise returns false.
 func (f *FieldInfo) IsFloat32() bool {
-	typeName, err := ExprToBaseTypeName(f.Type)
-	if err != nil {
-		return false
-	}
-	return typeName == "float32"
+	return f.isBaseType("", "float32")
 }
 
 // IsFloat64 returns true if FieldInfo is float64, otherwise returns false.
 func (f *FieldInfo) IsFloat64() bool {
-	typeName, err := ExprToBaseTypeName(f.Type)
-	if err != nil {
-		return false
-	}
-	return typeName == "float64"
+	return f.isBaseType("", "float64")
 }
 
 // IsNumber returns true if FieldInfo is int or int64 or float32 or float64, otherwise returns false.
@@ -374,18 +965,40 @@ func (f *FieldInfo) IsNumber() bool {
 
 // IsBool returns true if FieldInfo is bool, otherwise returns false.
 func (f *FieldInfo) IsBool() bool {
-	typeName, err := ExprToBaseTypeName(f.Type)
-	if err != nil {
-		return false
-	}
-	return typeName == "bool"
+	return f.isBaseType("", "bool")
 }
 
 // IsTime returns true if FieldInfo is time.Time, otherwise returns false.
 func (f *FieldInfo) IsTime() bool {
-	typeName, err := ExprToBaseTypeName(f.Type)
-	if err != nil {
-		return false
+	return f.isBaseType("time", "Time")
+}
+
+// isBaseType returns true if base type of field without "*" and "[]" is name in package of qualifier, otherwise returns false.
+func (f *FieldInfo) isBaseType(qualifier, name string) bool {
+	q, n, ok := baseTypeName(f.Type)
+	return ok && q == qualifier && n == name
+}
+
+// IsChan returns true if FieldInfo is channel, otherwise returns false.
+func (f *FieldInfo) IsChan() bool {
+	_, ok := f.Type.(*ast.ChanType)
+	return ok
+}
+
+// ChanDir returns direction of channel. returns "" if FieldInfo is not channel.
+func (f *FieldInfo) ChanDir() ChanDir {
+	ch, ok := f.Type.(*ast.ChanType)
+	if !ok {
+		return ""
+	}
+	return NewChanDir(ch.Dir)
+}
+
+// ChanElem returns element type of channel. returns nil if FieldInfo is not channel.
+func (f *FieldInfo) ChanElem() ast.Expr {
+	ch, ok := f.Type.(*ast.ChanType)
+	if !ok {
+		return nil
 	}
-	return typeName == "time.Time"
+	return ch.Value
 }
@@ -1,6 +1,16 @@
 package genbase
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 )
 
@@ -80,3 +90,574 @@ func TestPackageInfoCollectTypeInfos(t *testing.T) {
 		t.Fatalf("unexpected: %d", len(tis))
 	}
 }
+
+func TestParserParsePackageFilesSkippedFiles(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParsePackageFiles([]string{"./misc/fixture/a/model.go", "./misc/fixture/a/model_amd64.s", "./misc/fixture/a/README"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pInfo.SkippedFiles) != 2 {
+		t.Fatalf("unexpected: %d", len(pInfo.SkippedFiles))
+	}
+	if pInfo.SkippedFiles[0].Reason != SkipAssemblyFile {
+		t.Fatalf("unexpected: %s", pInfo.SkippedFiles[0].Reason)
+	}
+	if pInfo.SkippedFiles[1].Reason != SkipNotGoFile {
+		t.Fatalf("unexpected: %s", pInfo.SkippedFiles[1].Reason)
+	}
+
+	p = &Parser{StrictFileCheck: true}
+	_, err = p.ParsePackageFiles([]string{"./misc/fixture/a/model.go", "./misc/fixture/a/model_amd64.s"})
+	if !errors.Is(err, ErrAssemblyFile) {
+		t.Fatalf("unexpected: %v", err)
+	}
+}
+
+func TestParserStrictFileCheckCgo(t *testing.T) {
+	src := `
+	package sample
+
+	// #include <stdlib.h>
+	import "C"
+	`
+
+	p := &Parser{}
+	if _, err := p.ParseStringSource("main.go", src); err != nil {
+		t.Fatal(err)
+	}
+
+	p = &Parser{StrictFileCheck: true}
+	_, err := p.ParseStringSource("main.go", src)
+	if !errors.Is(err, ErrCgoFile) {
+		t.Fatalf("unexpected: %v", err)
+	}
+}
+
+func TestPackageInfoBuildPackage(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParsePackageDir("./misc/fixture/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pInfo.BuildPackage == nil {
+		t.Fatal("unexpected: BuildPackage is nil")
+	}
+	if len(pInfo.BuildPackage.GoFiles) != 1 {
+		t.Fatalf("unexpected: %v", pInfo.BuildPackage.GoFiles)
+	}
+	if pInfo.BuildPackage.Name != "a" {
+		t.Fatalf("unexpected: %s", pInfo.BuildPackage.Name)
+	}
+
+	pInfo, err = p.ParsePackageFiles([]string{"./misc/fixture/a/model.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pInfo.BuildPackage != nil || pInfo.ImportPath() != "" {
+		t.Fatal("unexpected: BuildPackage is set")
+	}
+}
+
+func TestTypeInfoMethodSet(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	type Sample struct {
+		Inner
+	}
+
+	func (s Sample) String() string { return "" }
+
+	func (s *Sample) MarshalJSON() ([]byte, error) { return nil, nil }
+
+	type Inner struct{}
+
+	func (i *Inner) Close() error { return nil }
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ti := pInfo.CollectTypeInfos([]string{"Sample"})[0]
+	if mset := ti.MethodSet(false); mset.Len() != 1 {
+		t.Fatalf("unexpected: %s", mset)
+	}
+	if mset := ti.MethodSet(true); mset.Len() != 3 {
+		t.Fatalf("unexpected: %s", mset)
+	}
+	if ti.HasMethod("MarshalJSON", false) {
+		t.Error("unexpected: MarshalJSON is found")
+	}
+	if !ti.HasMethod("MarshalJSON", true) {
+		t.Error("unexpected: MarshalJSON is not found")
+	}
+	if !ti.HasMethod("Close", true) {
+		t.Error("unexpected: Close is not found")
+	}
+}
+
+func TestFieldInfoResolveTypeInfo(t *testing.T) {
+	p := &Parser{}
+	other, err := p.ParsePackageDir("./misc/fixture/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	renamed, err := p.ParsePackageDir("./misc/fixture/pkgname")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// import path is needed to resolve package by import spec.
+	renamed.importPath = "github.com/favclip/genbase/misc/fixture/pkgname"
+	p = &Parser{UseExportData: true}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	import (
+		"time"
+
+		"github.com/favclip/genbase/misc/fixture/a"
+		"github.com/favclip/genbase/misc/fixture/pkgname"
+	)
+
+	type Sample struct {
+		Inner  *Inner
+		Inners []*Inner
+		A      a.A
+		At     time.Time
+		S      string
+		Model  renamed.Model
+	}
+
+	type Inner struct{}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	st, err := pInfo.CollectTypeInfos([]string{"Sample"})[0].StructType()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expects := []string{"Inner", "Inner", "A", "", "", "Model"}
+	for i, f := range st.FieldInfos() {
+		ti := f.ResolveTypeInfo(pInfo, other, renamed)
+		if expects[i] == "" && ti != nil {
+			t.Errorf("unexpected: %s", ti.Name())
+		} else if expects[i] != "" && (ti == nil || ti.Name() != expects[i]) {
+			t.Errorf("unexpected: %v", ti)
+		}
+	}
+}
+
+func TestFindImportSpecByIdent(t *testing.T) {
+	p := &Parser{UseExportData: true}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	import (
+		. "time"
+		str "strings"
+		rand "math/rand/v2"
+		_ "fmt"
+
+		"github.com/favclip/genbase/misc/fixture/pkgname"
+	)
+
+	type Sample struct {
+		At    Time
+		B     str.Builder
+		N     rand.Rand
+		Model renamed.Model
+	}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := pInfo.Files[0]
+
+	expects := map[string]string{
+		".":       `"time"`,
+		"str":     `"strings"`,
+		"rand":    `"math/rand/v2"`,
+		"strings": "",
+		"fmt":     "",
+		"pkgname": `"github.com/favclip/genbase/misc/fixture/pkgname"`,
+	}
+	for ident, expect := range expects {
+		imp := file.FindImportSpecByIdent(ident)
+		if expect == "" && imp != nil {
+			t.Errorf("unexpected: %s %s", ident, imp.Path.Value)
+		} else if expect != "" && (imp == nil || imp.Path.Value != expect) {
+			t.Errorf("unexpected: %s %v", ident, imp)
+		}
+	}
+
+	if imp := file.FindImportSpecByIdent("renamed"); imp != nil {
+		t.Errorf("unexpected: %s", imp.Path.Value)
+	}
+	if imp := pInfo.FindImportSpecByIdent(file, "renamed"); imp == nil || imp.Path.Value != `"github.com/favclip/genbase/misc/fixture/pkgname"` {
+		t.Errorf("unexpected: %v", imp)
+	}
+}
+
+func TestFieldInfoChanDir(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	type Sample struct {
+		A chan int
+		B chan<- string
+		C <-chan *Sample
+		D int
+	}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := pInfo.CollectTypeInfos([]string{"Sample"})[0].StructType()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := st.FieldInfos()
+	expects := []ChanDir{ChanBoth, ChanSend, ChanRecv, ""}
+	elems := []string{"int", "string", "*Sample", ""}
+	for i, f := range fields {
+		if v := f.ChanDir(); v != expects[i] {
+			t.Errorf("unexpected: %s %s", f.Names[0].Name, v)
+		}
+		if v := f.IsChan(); v != (expects[i] != "") {
+			t.Errorf("unexpected: %s %v", f.Names[0].Name, v)
+		}
+		if elem := f.ChanElem(); elem != nil && types.ExprString(elem) != elems[i] || elem == nil && elems[i] != "" {
+			t.Errorf("unexpected: %s %v", f.Names[0].Name, elem)
+		}
+	}
+}
+
+func TestParserParsePackageFilesBuildConstraints(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genbase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"sample.go":    "package sample\n\ntype Sample struct{}\n",
+		"appengine.go": "//go:build appengine\n\npackage sample\n\ntype AppEngine struct{}\n",
+		"ignore.go":    "//go:build ignore\n\npackage sample\n\ntype Ignore struct{}\n",
+		"plan9.go":     "package sample\n\ntype Plan9 struct{}\n",
+		"x_plan9.go":   "package sample\n\ntype Plan9Suffix struct{}\n",
+	}
+	var names []string
+	for name, content := range files {
+		name = filepath.Join(dir, name)
+		if err := ioutil.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, tags := range [][]string{nil, {"appengine"}} {
+		p := &Parser{BuildTags: tags}
+		pInfo, err := p.ParsePackageFiles(names)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var typeNames []string
+		for _, typeInfo := range pInfo.TypeInfos() {
+			typeNames = append(typeNames, typeInfo.Name())
+		}
+		expect := "Plan9,Sample"
+		if len(tags) != 0 {
+			expect = "AppEngine,Plan9,Sample"
+		}
+		if v := strings.Join(typeNames, ","); v != expect {
+			t.Errorf("unexpected: %v %s", tags, v)
+		}
+		for _, skipped := range pInfo.SkippedFiles {
+			if skipped.Reason != SkipBuildConstraints {
+				t.Errorf("unexpected: %v", skipped)
+			}
+		}
+		if v := len(pInfo.SkippedFiles); v != len(files)-len(typeNames) {
+			t.Errorf("unexpected: %v", pInfo.SkippedFiles)
+		}
+	}
+}
+
+func TestParserHooks(t *testing.T) {
+	var calls []string
+	p := &Parser{
+		BeforeParseFile: func(fileName string, src []byte) ([]byte, error) {
+			calls = append(calls, "before:"+fileName)
+			return []byte(strings.Replace(string(src), "//strip:", "", -1)), nil
+		},
+		AfterParseFile: func(fileName string, file *FileInfo) error {
+			calls = append(calls, "after:"+file.Name.Name)
+			return nil
+		},
+		AfterTypeCheck: func(pkg *PackageInfo) error {
+			calls = append(calls, "check:"+pkg.Types.Name())
+			return nil
+		},
+	}
+	pInfo, err := p.ParseStringSource("main.go", "package sample\n\n//strip:type Sample struct{}\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := len(pInfo.CollectTypeInfos([]string{"Sample"})); v != 1 {
+		t.Errorf("unexpected: %d", v)
+	}
+	if v := strings.Join(calls, ","); v != "before:main.go,after:sample,check:sample" {
+		t.Errorf("unexpected: %s", v)
+	}
+
+	hookErr := errors.New("hook error")
+	p = &Parser{
+		AfterParseFile: func(fileName string, file *FileInfo) error {
+			return hookErr
+		},
+	}
+	if _, err := p.ParseStringSource("main.go", "package sample\n"); !errors.Is(err, hookErr) {
+		t.Errorf("unexpected: %v", err)
+	}
+}
+
+func TestParserParsePackageSymlinks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genbase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	dir, err = filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkgDir := filepath.Join(dir, "sample")
+	if err := os.Mkdir(pkgDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	name := filepath.Join(pkgDir, "sample.go")
+	if err := ioutil.WriteFile(name, []byte("package sample\n\ntype Sample struct{}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink(pkgDir, link); err != nil {
+		t.Skipf("symlink is not supported: %s", err)
+	}
+
+	p := &Parser{}
+	pInfo, err := p.ParsePackageDir(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pInfo.Dir != pkgDir {
+		t.Errorf("unexpected: %s", pInfo.Dir)
+	}
+
+	pInfo, err = p.ParsePackageFiles([]string{name, filepath.Join(link, "sample.go")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pInfo.Files) != 1 || len(pInfo.SkippedFiles) != 1 || pInfo.SkippedFiles[0].Reason != SkipDuplicateFile {
+		t.Errorf("unexpected: %d %v", len(pInfo.Files), pInfo.SkippedFiles)
+	}
+}
+
+func TestParserParsePackageFilesCaseCollision(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genbase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	lower := filepath.Join(dir, "sample.go")
+	upper := filepath.Join(dir, "Sample.go")
+	if err := ioutil.WriteFile(lower, []byte("package sample\n\ntype Sample struct{}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(upper, []byte("package sample\n\ntype Other struct{}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &Parser{}
+	pInfo, err := p.ParsePackageFiles([]string{lower, upper})
+	if isSameFile(lower, upper) {
+		// case-insensitive file system, upper overwrote lower.
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pInfo.Files) != 1 || len(pInfo.SkippedFiles) != 1 || pInfo.SkippedFiles[0].Reason != SkipDuplicateFile {
+			t.Errorf("unexpected: %d %v", len(pInfo.Files), pInfo.SkippedFiles)
+		}
+	} else if !errors.Is(err, ErrCaseCollision) {
+		t.Errorf("unexpected: %v", err)
+	}
+}
+
+func TestParserParseSingleFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	src := "package sample\n" +
+		"import \"example.com/nowhere/clock\"\n" +
+		"// +sample: output=a.go\n" +
+		"type Sample struct {\n" +
+		"	CreatedAt clock.Time\n" +
+		"}\n"
+	if err := ioutil.WriteFile(path, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &Parser{}
+	pInfo, err := p.ParseSingleFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pInfo.Name() != "sample" || pInfo.Types != nil {
+		t.Errorf("unexpected: %s %v", pInfo.Name(), pInfo.Types)
+	}
+	typeInfos := pInfo.CollectTaggedTypeInfos("+sample")
+	if len(typeInfos) != 1 || typeInfos[0].Name() != "Sample" {
+		t.Fatalf("unexpected: %v", typeInfos)
+	}
+	info, err := typeInfos[0].Annotation("+sample")
+	if err != nil || len(info.Args) != 1 || info.Args[0].Value != "a.go" {
+		t.Errorf("unexpected: %v %v", err, info)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("package sample\ntype Sample struct {\n	ID int\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	pInfo, err = p.ParseSingleFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pInfo.Types == nil {
+		t.Error("unexpected: types are not resolved")
+	}
+}
+
+// wideStructFieldInfos returns fields of struct that has n fields of various types.
+func wideStructFieldInfos(b testing.TB, n int) FieldInfos {
+	typeNames := []string{"int", "*int64", "[]string", "time.Time", "*[]*time.Time", "[]*float64", "bool", "map[string]int"}
+	var buf bytes.Buffer
+	buf.WriteString("package sample\nimport \"time\"\ntype Wide struct {\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&buf, "F%d %s\n", i, typeNames[i%len(typeNames)])
+	}
+	buf.WriteString("}\nvar _ time.Time\n")
+
+	p := &Parser{SkipSemanticsCheck: true}
+	pInfo, err := p.ParseStringSource("wide.go", buf.String())
+	if err != nil {
+		b.Fatal(err)
+	}
+	st, err := pInfo.CollectTypeInfos([]string{"Wide"})[0].StructType()
+	if err != nil {
+		b.Fatal(err)
+	}
+	return st.FieldInfos()
+}
+
+func BenchmarkFieldInfoTypeName(b *testing.B) {
+	fields := wideStructFieldInfos(b, 200)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, f := range fields {
+			_ = f.TypeName()
+		}
+	}
+}
+
+func BenchmarkFieldInfoPredicates(b *testing.B) {
+	fields := wideStructFieldInfos(b, 200)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, f := range fields {
+			_ = f.IsNumber() || f.IsString() || f.IsBool() || f.IsTime()
+		}
+	}
+}
+
+// manyTypesPackageInfo returns package that has n struct types of 10 fields.
+func manyTypesPackageInfo(tb testing.TB, n int) *PackageInfo {
+	var buf bytes.Buffer
+	buf.WriteString("package sample\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&buf, "type T%d struct {\n", i)
+		for j := 0; j < 10; j++ {
+			fmt.Fprintf(&buf, "F%d int\n", j)
+		}
+		buf.WriteString("}\n")
+	}
+
+	p := &Parser{SkipSemanticsCheck: true}
+	pInfo, err := p.ParseStringSource("many.go", buf.String())
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return pInfo
+}
+
+func TestPackageInfoTypeInfosAllocs(t *testing.T) {
+	pInfo := manyTypesPackageInfo(t, 100)
+	if v := len(pInfo.TypeInfos()); v != 100 {
+		t.Fatalf("unexpected: %d", v)
+	}
+	st, err := pInfo.TypeInfos()[0].StructType()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// budget includes allocations of pprof labels in PhaseCollect measurement.
+	if v := testing.AllocsPerRun(100, func() { pInfo.TypeInfos() }); v > 10 {
+		t.Errorf("unexpected: %v allocs", v)
+	}
+	if v := testing.AllocsPerRun(100, func() { st.FieldInfos() }); v > 1 {
+		t.Errorf("unexpected: %v allocs", v)
+	}
+
+	tInfos := pInfo.TypeInfos()
+	tInfos[0].AnnotatedComment = &ast.Comment{Text: "// +test"}
+	if pInfo.TypeInfos()[0].AnnotatedComment != nil {
+		t.Error("unexpected: TypeInfo is shared between calls")
+	}
+
+	other := manyTypesPackageInfo(t, 1)
+	pInfo.Files = append(pInfo.Files, other.Files...)
+	if v := len(pInfo.TypeInfos()); v != 101 {
+		t.Errorf("unexpected: %d", v)
+	}
+}
+
+func BenchmarkPackageInfoTypeInfos(b *testing.B) {
+	pInfo := manyTypesPackageInfo(b, 500)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = pInfo.TypeInfos()
+	}
+}
+
+func BenchmarkStructTypeInfoFieldInfos(b *testing.B) {
+	fields := wideStructFieldInfos(b, 200)
+	st := (*StructTypeInfo)(&ast.StructType{Fields: &ast.FieldList{}})
+	for _, f := range fields {
+		st.Fields.List = append(st.Fields.List, (*ast.Field)(f))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = st.FieldInfos()
+	}
+}
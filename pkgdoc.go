@@ -0,0 +1,41 @@
+package genbase
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// Doc returns package comment of package.
+// like go/doc, package comments in multiple files are merged in order of file names.
+func (pkg *PackageInfo) Doc() string {
+	files := make(FileInfos, 0, len(pkg.Files))
+	for _, file := range pkg.Files {
+		if file != nil && file.Doc != nil {
+			files = append(files, file)
+		}
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return pkg.FileSet.Position(files[i].Package).Filename < pkg.FileSet.Position(files[j].Package).Filename
+	})
+
+	var doc string
+	for _, file := range files {
+		text := file.Doc.Text()
+		if doc == "" {
+			doc = text
+			continue
+		}
+		doc += "\n" + text
+	}
+	return doc
+}
+
+// DocFile returns doc.go file of package. returns nil if it is not found.
+func (pkg *PackageInfo) DocFile() *FileInfo {
+	for _, file := range pkg.Files {
+		if file != nil && filepath.Base(pkg.FileSet.Position(file.Package).Filename) == "doc.go" {
+			return file
+		}
+	}
+	return nil
+}
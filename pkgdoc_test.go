@@ -0,0 +1,51 @@
+package genbase
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPackageInfoDoc(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genbase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"doc.go":    "// Package sample is sample.\npackage sample\n",
+		"a.go":      "// Additional doc.\npackage sample\n",
+		"sample.go": "package sample\n\ntype Sample struct{}\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := &Parser{}
+	pInfo, err := p.ParsePackageDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := pInfo.Doc(); v != "Additional doc.\n\nPackage sample is sample.\n" {
+		t.Fatalf("unexpected: %q", v)
+	}
+	docFile := pInfo.DocFile()
+	if docFile == nil || docFile.Doc.Text() != "Package sample is sample.\n" {
+		t.Fatalf("unexpected: %v", docFile)
+	}
+
+	pInfo, err = p.ParseStringSource("main.go", "package sample\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := pInfo.Doc(); v != "" {
+		t.Fatalf("unexpected: %q", v)
+	}
+	if docFile := pInfo.DocFile(); docFile != nil {
+		t.Fatalf("unexpected: %v", docFile)
+	}
+}
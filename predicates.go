@@ -0,0 +1,100 @@
+package genbase
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// TypeOf returns types.Type of expr.
+// returns nil if types are not resolved.
+func (pkg *PackageInfo) TypeOf(expr ast.Expr) types.Type {
+	if pkg.TypesInfo == nil {
+		return nil
+	}
+	return pkg.TypesInfo.TypeOf(expr)
+}
+
+// IsComparable returns true if values of field type are comparable by == operator, otherwise returns false.
+// it is usable as map key.
+func (f *FieldInfo) IsComparable(pkg *PackageInfo) bool {
+	typ := pkg.TypeOf(f.Type)
+	if typ == nil {
+		return false
+	}
+	return types.Comparable(typ)
+}
+
+// IsOrdered returns true if values of field type are ordered by < operator, otherwise returns false.
+func (f *FieldInfo) IsOrdered(pkg *PackageInfo) bool {
+	typ := pkg.TypeOf(f.Type)
+	if typ == nil {
+		return false
+	}
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsOrdered != 0
+}
+
+// IsNillable returns true if field type can be nil, otherwise returns false.
+// pointers, slices, maps, chans, funcs and interfaces are nillable.
+// if types are not resolved, it is judged by syntax of field type.
+func (f *FieldInfo) IsNillable(pkg *PackageInfo) bool {
+	typ := pkg.TypeOf(f.Type)
+	if typ == nil {
+		switch ref := NewTypeRef(f.Type); ref.Kind {
+		case KindPointer, KindSlice, KindMap, KindChan, KindFunc, KindInterface:
+			return true
+		case KindBasic:
+			return ref.Name == "error" || ref.Name == "any"
+		}
+		return false
+	}
+	switch t := typ.Underlying().(type) {
+	case *types.Pointer, *types.Slice, *types.Map, *types.Chan, *types.Signature, *types.Interface:
+		return true
+	case *types.Basic:
+		return t.Kind() == types.UnsafePointer
+	}
+	return false
+}
+
+// Implements returns true if field type or pointer to it implements interface, otherwise returns false.
+// pointer is also checked because field is addressable through pointer to struct.
+// iface is name of interface like ImplementsCheck.Interface. e.g. "Stringer", "encoding/json.Marshaler"
+// returns false if types are not resolved or interface is not found.
+func (f *FieldInfo) Implements(pkg *PackageInfo, iface string) bool {
+	typ := pkg.TypeOf(f.Type)
+	if typ == nil {
+		return false
+	}
+	it, err := pkg.lookupInterface(pkg.Types, iface)
+	if err != nil {
+		return false
+	}
+	if types.Implements(typ, it) {
+		return true
+	}
+	if _, ok := typ.(*types.Pointer); ok {
+		return false
+	}
+	return types.Implements(types.NewPointer(typ), it)
+}
+
+// ImplementsJSONMarshaler returns true if field implements encoding/json.Marshaler, otherwise returns false.
+func (f *FieldInfo) ImplementsJSONMarshaler(pkg *PackageInfo) bool {
+	return f.Implements(pkg, "encoding/json.Marshaler")
+}
+
+// ImplementsTextMarshaler returns true if field implements encoding.TextMarshaler, otherwise returns false.
+func (f *FieldInfo) ImplementsTextMarshaler(pkg *PackageInfo) bool {
+	return f.Implements(pkg, "encoding.TextMarshaler")
+}
+
+// ImplementsStringer returns true if field implements fmt.Stringer, otherwise returns false.
+func (f *FieldInfo) ImplementsStringer(pkg *PackageInfo) bool {
+	return f.Implements(pkg, "fmt.Stringer")
+}
+
+// ImplementsSQLScanner returns true if field implements database/sql.Scanner, otherwise returns false.
+func (f *FieldInfo) ImplementsSQLScanner(pkg *PackageInfo) bool {
+	return f.Implements(pkg, "database/sql.Scanner")
+}
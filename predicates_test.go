@@ -0,0 +1,175 @@
+package genbase
+
+import (
+	"testing"
+)
+
+func TestFieldInfoIsComparable(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	import "time"
+
+	type Sample struct {
+		A int
+		B string
+		C *int
+		D []int
+		E map[string]int
+		F time.Time
+		G time.Duration
+		H func()
+		I [2]int
+		J struct{ S []string }
+		K interface{}
+		L Score
+	}
+
+	type Score float64
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := pInfo.CollectTypeInfos([]string{"Sample"})[0].StructType()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expects := []struct {
+		comparable bool
+		ordered    bool
+	}{
+		{true, true},
+		{true, true},
+		{true, false},
+		{false, false},
+		{false, false},
+		{true, false},
+		{true, true},
+		{false, false},
+		{true, false},
+		{false, false},
+		{true, false},
+		{true, true},
+	}
+	for i, f := range st.FieldInfos() {
+		if v := f.IsComparable(pInfo); v != expects[i].comparable {
+			t.Errorf("unexpected: %s IsComparable %v", f.Names[0].Name, v)
+		}
+		if v := f.IsOrdered(pInfo); v != expects[i].ordered {
+			t.Errorf("unexpected: %s IsOrdered %v", f.Names[0].Name, v)
+		}
+	}
+}
+
+func TestFieldInfoIsNillable(t *testing.T) {
+	src := `
+	package sample
+
+	type Sample struct {
+		A int
+		B *int
+		C []int
+		D map[string]int
+		E chan int
+		F func()
+		G error
+		H [2]int
+		I Names
+		J struct{}
+	}
+
+	type Names []string
+	`
+
+	expects := []bool{false, true, true, true, true, true, true, false, true, false}
+	for _, skip := range []bool{false, true} {
+		p := &Parser{SkipSemanticsCheck: skip}
+		pInfo, err := p.ParseStringSource("main.go", src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if skip {
+			// judged by syntax
+			pInfo.TypesInfo = nil
+			expects[8] = false
+		}
+		st, err := pInfo.CollectTypeInfos([]string{"Sample"})[0].StructType()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, f := range st.FieldInfos() {
+			if v := f.IsNillable(pInfo); v != expects[i] {
+				t.Errorf("unexpected: %s IsNillable %v", f.Names[0].Name, v)
+			}
+		}
+	}
+}
+
+func TestFieldInfoImplements(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	import (
+		"database/sql"
+		"encoding/json"
+		"time"
+	)
+
+	type Sample struct {
+		A time.Time
+		B *time.Time
+		C sql.NullString
+		D Raw
+		E Status
+		F int
+	}
+
+	type Status int
+
+	func (s *Status) String() string { return "" }
+
+	type Raw []byte
+
+	func (r Raw) MarshalJSON() ([]byte, error) { return json.Marshal([]byte(r)) }
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := pInfo.CollectTypeInfos([]string{"Sample"})[0].StructType()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expects := []struct {
+		json, text, stringer, scanner bool
+	}{
+		{true, true, true, false},
+		{true, true, true, false},
+		{false, false, false, true},
+		{true, false, false, false},
+		{false, false, true, false},
+		{false, false, false, false},
+	}
+	for i, f := range st.FieldInfos() {
+		expect := expects[i]
+		if v := f.ImplementsJSONMarshaler(pInfo); v != expect.json {
+			t.Errorf("unexpected: %s ImplementsJSONMarshaler %v", f.Name(), v)
+		}
+		if v := f.ImplementsTextMarshaler(pInfo); v != expect.text {
+			t.Errorf("unexpected: %s ImplementsTextMarshaler %v", f.Name(), v)
+		}
+		if v := f.ImplementsStringer(pInfo); v != expect.stringer {
+			t.Errorf("unexpected: %s ImplementsStringer %v", f.Name(), v)
+		}
+		if v := f.ImplementsSQLScanner(pInfo); v != expect.scanner {
+			t.Errorf("unexpected: %s ImplementsSQLScanner %v", f.Name(), v)
+		}
+	}
+
+	if st.FieldInfos()[0].Implements(pInfo, "example.com/none.Iface") {
+		t.Error("unexpected: unknown interface is implemented")
+	}
+}
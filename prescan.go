@@ -0,0 +1,243 @@
+package genbase
+
+import (
+	"bytes"
+	"go/ast"
+	"go/scanner"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// SkipNoAnnotation shows file is skipped by PrescanTags, because it has no annotation and annotated files don't use it.
+const SkipNoAnnotation SkipReason = "no annotation and not used by annotated files"
+
+// parsePrescannedFiles parses files that contain one of PrescanTags, and files that declare identifiers used by them.
+// other Go files are scanned for top-level declarations only, they are not parsed.
+func (p *Parser) parsePrescannedFiles(pkg *PackageInfo, fs *token.FileSet, fileNames []string, codes []string) (FileInfos, error) {
+	var seeds []int
+	srcs := make(map[int][]byte)
+	declared := make(map[string][]int)
+	for idx, fileName := range fileNames {
+		if !strings.HasSuffix(fileName, ".go") {
+			seeds = append(seeds, idx)
+			continue
+		}
+		var code interface{}
+		if idx < len(codes) {
+			code = codes[idx]
+		}
+		src, err := readSource(fileName, code)
+		if err != nil {
+			return nil, err
+		}
+		srcs[idx] = src
+		names, ok := topLevelNames(fileName, src)
+		if !ok || containsAnyTag(src, p.PrescanTags) {
+			// files that can't be scanned are parsed to report errors.
+			seeds = append(seeds, idx)
+			continue
+		}
+		for _, name := range names {
+			declared[name] = append(declared[name], idx)
+		}
+	}
+	if !hasGoFile(fileNames, seeds) {
+		// package needs a Go file for its name even if nothing is annotated.
+		for idx, fileName := range fileNames {
+			if strings.HasSuffix(fileName, ".go") {
+				seeds = append(seeds, idx)
+				break
+			}
+		}
+	}
+
+	parsed := make(map[int]*FileInfo)
+	selected := make(map[int]bool)
+	queue := seeds
+	for _, idx := range seeds {
+		selected[idx] = true
+	}
+	for len(queue) != 0 {
+		idx := queue[0]
+		queue = queue[1:]
+
+		fileName := fileNames[idx]
+		if !strings.HasSuffix(fileName, ".go") {
+			files, err := p.parseFiles(pkg, fs, []string{fileName}, nil)
+			if err != nil {
+				return nil, err
+			}
+			if len(files) != 0 {
+				parsed[idx] = files[0]
+			}
+			continue
+		}
+		file, src, err := p.parseFile(fs, fileName, srcs[idx])
+		if err != nil {
+			return nil, err
+		}
+		pkg.countFile(fs, file)
+		pkg.setSource(fileName, src)
+		parsed[idx] = file
+
+		for _, name := range identsOutsideFuncBodies(file) {
+			for _, dep := range declared[name] {
+				if !selected[dep] {
+					selected[dep] = true
+					queue = append(queue, dep)
+				}
+			}
+		}
+	}
+
+	indices := make([]int, 0, len(parsed))
+	for idx := range parsed {
+		indices = append(indices, idx)
+	}
+	sort.Ints(indices)
+	files := make(FileInfos, 0, len(indices))
+	for _, idx := range indices {
+		files = append(files, parsed[idx])
+	}
+	for idx, fileName := range fileNames {
+		if _, ok := srcs[idx]; ok && !selected[idx] {
+			pkg.SkippedFiles = appendSkippedFiles(pkg.SkippedFiles, SkipNoAnnotation, fileName)
+		}
+	}
+	return files, nil
+}
+
+// containsAnyTag returns true if src contains one of tags, otherwise returns false.
+// it may report tag in string literal or longer directive, then file is only parsed unnecessarily.
+func containsAnyTag(src []byte, tags []string) bool {
+	for _, tag := range tags {
+		if bytes.Contains(src, []byte(tag)) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasGoFile returns true if indices contain Go file, otherwise returns false.
+func hasGoFile(fileNames []string, indices []int) bool {
+	for _, idx := range indices {
+		if strings.HasSuffix(fileNames[idx], ".go") {
+			return true
+		}
+	}
+	return false
+}
+
+// topLevelNames returns names that are declared at top level of src by tokens, without parsing.
+// methods are returned as name of receiver type, so files of methods are parsed with their type.
+// returns false if src has syntax error of tokens.
+func topLevelNames(fileName string, src []byte) ([]string, bool) {
+	var s scanner.Scanner
+	failed := false
+	file := token.NewFileSet().AddFile(fileName, -1, len(src))
+	s.Init(file, src, func(token.Position, string) { failed = true }, 0)
+
+	var names []string
+	depth := 0               // depth of (, [ and {
+	keyword := token.ILLEGAL // keyword of declaration at top level
+	group := false           // in declaration group. e.g. "var ( ... )"
+	expectName := false      // next identifier is declared name
+	moreNames := false       // comma continues names. e.g. "var a, b int"
+	receiver := false        // in receiver of method
+	receiverName := ""
+	for {
+		_, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		switch tok {
+		case token.LPAREN, token.LBRACK, token.LBRACE:
+			depth++
+			if depth == 1 && expectName && tok == token.LPAREN {
+				if keyword == token.FUNC {
+					receiver = true
+					receiverName = ""
+					expectName = false
+				} else {
+					group = true
+				}
+				continue
+			}
+			if receiver && depth == 2 && tok == token.LBRACK {
+				// type parameters of receiver. e.g. "(m *Map[K, V])"
+				names = append(names, receiverName)
+				receiver = false
+			}
+		case token.RPAREN, token.RBRACK, token.RBRACE:
+			depth--
+			if receiver && depth == 0 {
+				names = append(names, receiverName)
+				receiver = false
+			}
+			if group && depth == 0 {
+				group = false
+			}
+		case token.SEMICOLON:
+			if depth == 0 {
+				keyword = token.ILLEGAL
+				group = false
+			}
+			expectName = group && depth == 1
+			moreNames = false
+			continue
+		case token.IDENT:
+			if receiver && depth == 1 {
+				receiverName = lit
+				continue
+			}
+			if expectName {
+				names = append(names, lit)
+				expectName = false
+				moreNames = keyword == token.VAR || keyword == token.CONST
+				continue
+			}
+		case token.COMMA:
+			if moreNames {
+				expectName = true
+				moreNames = false
+				continue
+			}
+		case token.FUNC, token.VAR, token.CONST, token.TYPE:
+			if depth == 0 && keyword == token.ILLEGAL {
+				keyword = tok
+				expectName = true
+				continue
+			}
+		}
+		expectName = false
+		moreNames = false
+	}
+	return names, !failed
+}
+
+// identsOutsideFuncBodies returns names of identifiers in file except bodies of funcs and selectors.
+// bodies are ignored because types are checked without them.
+func identsOutsideFuncBodies(file *FileInfo) []string {
+	var names []string
+	for _, decl := range file.Decls {
+		ast.Inspect(decl, func(node ast.Node) bool {
+			switch n := node.(type) {
+			case *ast.BlockStmt:
+				return false
+			case *ast.SelectorExpr:
+				ast.Inspect(n.X, func(node ast.Node) bool {
+					if ident, ok := node.(*ast.Ident); ok {
+						names = append(names, ident.Name)
+					}
+					return true
+				})
+				return false
+			case *ast.Ident:
+				names = append(names, n.Name)
+			}
+			return true
+		})
+	}
+	return names
+}
@@ -0,0 +1,107 @@
+package genbase
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestTopLevelNames(t *testing.T) {
+	src := `package sample
+
+import "time"
+
+type (
+	A struct{ T time.Time }
+	List[T any] []T
+)
+
+var x, y = f(func() int { var z int; return z }), 2
+
+const (
+	C1 = iota
+	C2, C3
+)
+
+func f(v int) int { type local int; return v }
+
+func (s *Sample) Name() string { return "" }
+
+func (m Map[K, V]) Len() int { return 0 }
+`
+	names, ok := topLevelNames("sample.go", []byte(src))
+	if !ok {
+		t.Fatal("unexpected: failed")
+	}
+	expected := []string{"A", "List", "x", "y", "C1", "C2", "C3", "f", "Sample", "Map"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("unexpected: %v", names)
+	}
+
+	if _, ok := topLevelNames("broken.go", []byte("package sample\nvar s = \"unterminated\n")); ok {
+		t.Error("unexpected: succeeded")
+	}
+}
+
+func TestParserPrescanTags(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genbase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"a.go": "package sample\n// +test\ntype A struct{ B B }\n",
+		"b.go": "package sample\ntype B struct{ Kind Kind }\n",
+		"c.go": "package sample\ntype Kind int\nfunc (b B) String() string { return helper() }\n",
+		"d.go": "package sample\nfunc helper() string { return \"\" }\n",
+		"e.go": "package sample\ntype Other struct{}\nfunc (o Other) String() string { return \"\" }\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := &Parser{PrescanTags: []string{"+test"}}
+	pInfo, err := p.ParsePackageDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pInfo.Types == nil {
+		t.Fatal("unexpected: types are not resolved")
+	}
+	var parsed []string
+	for _, file := range pInfo.Files {
+		parsed = append(parsed, filepath.Base(pInfo.FileSet.File(file.Package).Name()))
+	}
+	if !reflect.DeepEqual(parsed, []string{"a.go", "b.go", "c.go"}) {
+		t.Errorf("unexpected: %v", parsed)
+	}
+	var skipped []string
+	for _, f := range pInfo.SkippedFiles {
+		if f.Reason == SkipNoAnnotation {
+			skipped = append(skipped, filepath.Base(f.Name))
+		}
+	}
+	sort.Strings(skipped)
+	if !reflect.DeepEqual(skipped, []string{"d.go", "e.go"}) {
+		t.Errorf("unexpected: %v", skipped)
+	}
+	if v := len(pInfo.CollectTaggedTypeInfos("+test")); v != 1 {
+		t.Errorf("unexpected: %d", v)
+	}
+
+	p = &Parser{PrescanTags: []string{"+missing"}}
+	pInfo, err = p.ParsePackageDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// first file is parsed for package name, with files it uses.
+	if v := len(pInfo.Files); v != 3 {
+		t.Errorf("unexpected: %d", v)
+	}
+}
@@ -0,0 +1,31 @@
+package genbase
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// RenderTypeName renders type of expr with qualifier.
+// qualifier receives import path of package that declares named type, and returns ident to qualify it.
+// empty ident means no qualification. import path of pkg itself is pkg.ImportPath(), may be empty.
+// expr is rendered as it is written if types are not resolved.
+func (pkg *PackageInfo) RenderTypeName(expr ast.Expr, qualifier func(pkgPath string) string) string {
+	if pkg.TypesInfo == nil {
+		return types.ExprString(expr)
+	}
+	typ := pkg.TypesInfo.TypeOf(expr)
+	if typ == nil {
+		return types.ExprString(expr)
+	}
+	return pkg.renderType(typ, qualifier)
+}
+
+// renderType renders typ with qualifier, see RenderTypeName.
+func (pkg *PackageInfo) renderType(typ types.Type, qualifier func(pkgPath string) string) string {
+	return types.TypeString(typ, func(p *types.Package) string {
+		if p == pkg.Types {
+			return qualifier(pkg.ImportPath())
+		}
+		return qualifier(p.Path())
+	})
+}
@@ -0,0 +1,100 @@
+package genbase
+
+import (
+	"testing"
+)
+
+func TestPackageInfoRenderTypeName(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	import (
+		tm "time"
+		"net/http"
+	)
+
+	type Sample struct {
+		A map[string]*tm.Time
+		B []http.Header
+		C *Sample
+		D int
+	}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := pInfo.CollectTypeInfos([]string{"Sample"})[0].StructType()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := st.FieldInfos()
+
+	qualifier := func(pkgPath string) string {
+		switch pkgPath {
+		case "":
+			return "model"
+		case "time":
+			return "aliased"
+		}
+		return ""
+	}
+	expects := []string{"map[string]*aliased.Time", "[]Header", "*model.Sample", "int"}
+	for i, f := range fields {
+		if v := pInfo.RenderTypeName(f.Type, qualifier); v != expects[i] {
+			t.Errorf("unexpected: %s", v)
+		}
+	}
+
+	g := NewGenerator(pInfo)
+	g.AddImport("example.com/http", "")
+	expects = []string{"map[string]*time.Time", "[]http2.Header", "*Sample", "int"}
+	for i, f := range fields {
+		if v := pInfo.RenderTypeName(f.Type, g.Qualifier()); v != expects[i] {
+			t.Errorf("unexpected: %s", v)
+		}
+	}
+	if len(g.RequiredImports) != 3 {
+		t.Fatalf("unexpected: %d", len(g.RequiredImports))
+	}
+	if imp := g.RequiredImports[1]; imp.Path != "time" || imp.Ident != "" {
+		t.Errorf("unexpected: %#v", imp)
+	}
+	if imp := g.RequiredImports[2]; imp.Path != "net/http" || imp.Ident != "http2" {
+		t.Errorf("unexpected: %#v", imp)
+	}
+}
+
+func TestGeneratorQualifierPackageName(t *testing.T) {
+	p := &Parser{UseExportData: true}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	import "github.com/favclip/genbase/misc/fixture/pkgname"
+
+	type Sample struct {
+		Model renamed.Model
+	}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := pInfo.CollectTypeInfos([]string{"Sample"})[0].StructType()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewGenerator(pInfo)
+	if v := pInfo.RenderTypeName(st.FieldInfos()[0].Type, g.Qualifier()); v != "renamed.Model" {
+		t.Errorf("unexpected: %s", v)
+	}
+	if len(g.RequiredImports) != 1 {
+		t.Fatalf("unexpected: %d", len(g.RequiredImports))
+	}
+	if imp := g.RequiredImports[0]; imp.Path != "github.com/favclip/genbase/misc/fixture/pkgname" || imp.Ident != "renamed" {
+		t.Errorf("unexpected: %#v", imp)
+	}
+	if v := g.Qualifier()("github.com/favclip/genbase/misc/fixture/pkgname"); v != "renamed" || len(g.RequiredImports) != 1 {
+		t.Errorf("unexpected: %s %d", v, len(g.RequiredImports))
+	}
+}
@@ -0,0 +1,216 @@
+package genbase
+
+import (
+	"bytes"
+	"go/token"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Session holds parsed state of package directory, and re-parses only invalidated files.
+// it is designed for long running process like editor plugins and watch daemons.
+// files are selected like ParsePackageDir, but PrescanTags of Parser is not used.
+// files of invalidated state are removed from FileSet, so PackageInfo must not be used after next change.
+type Session struct {
+	Parser *Parser
+	Dir    string
+
+	mu        sync.Mutex
+	goVersion string
+	fs        *token.FileSet
+	files     map[string]*sessionFile
+	pkg       *PackageInfo
+}
+
+type sessionFile struct {
+	code interface{} // nil means read from file system
+	file *FileInfo   // nil means not parsed yet
+	src  []byte
+}
+
+// NewSession creates new Session for directory.
+// symlinks in directory are resolved likes ParsePackageDir.
+func NewSession(p *Parser, directory string) (*Session, error) {
+	if p == nil {
+		p = &Parser{}
+	}
+	directory = realPath(normalizePath(directory))
+	pkg, err := p.buildContext().ImportDir(directory, 0)
+	if err != nil {
+		return nil, p.importDirError(directory, pkg, err)
+	}
+	var names []string
+	names = append(names, pkg.GoFiles...)
+	names = append(names, pkg.CgoFiles...)
+	names, _, err = p.filterFiles(pathJoinAll(directory, names...))
+	if err != nil {
+		return nil, err
+	}
+
+	goVersion, err := p.goVersion(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Session{
+		Parser:    p,
+		Dir:       directory,
+		goVersion: goVersion,
+		fs:        token.NewFileSet(),
+		files:     make(map[string]*sessionFile),
+	}
+	for _, name := range names {
+		s.files[name] = &sessionFile{}
+	}
+	return s, nil
+}
+
+// FileChanged invalidates file. it is also used for added file.
+// code is new content of file, or nil to read it from file system.
+// test files, non Go files, files in other directories and files excluded by build constraints or
+// file patterns of Parser are ignored, and file is removed if it gets excluded.
+func (s *Session) FileChanged(fileName string, code []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := s.normalize(fileName)
+	if !s.accepts(name, code) {
+		if _, ok := s.files[name]; ok {
+			delete(s.files, name)
+			s.pkg = nil
+		}
+		return
+	}
+	f := &sessionFile{}
+	if code != nil {
+		f.code = code
+	}
+	s.files[name] = f
+	s.pkg = nil
+}
+
+// accepts returns true if file is part of package like ParsePackageDir.
+// file is accepted when build constraints can't be evaluated, so Package reports the error.
+func (s *Session) accepts(name string, code []byte) bool {
+	if filepath.Dir(name) != s.Dir || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+		return false
+	}
+	p := s.parser()
+	if kept, _, err := p.filterFiles([]string{name}); err == nil && len(kept) == 0 {
+		return false
+	}
+	ctxt := p.buildContext()
+	if code != nil {
+		ctxt.OpenFile = func(string) (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(code)), nil
+		}
+	}
+	match, err := ctxt.MatchFile(s.Dir, filepath.Base(name))
+	return match || err != nil
+}
+
+func (s *Session) parser() *Parser {
+	if s.Parser == nil {
+		return &Parser{}
+	}
+	return s.Parser
+}
+
+// FileRemoved invalidates removed file.
+func (s *Session) FileRemoved(fileName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.files, s.normalize(fileName))
+	s.pkg = nil
+}
+
+// Package returns PackageInfo of current state.
+// invalidated files are re-parsed and types are re-checked only when something is changed.
+func (s *Session) Package() (*PackageInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.pkg != nil {
+		return s.pkg, nil
+	}
+
+	s.removeStaleFiles()
+	names := make([]string, 0, len(s.files))
+	for name := range s.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	p := s.parser()
+	pkg := &PackageInfo{
+		Dir:                  s.Dir,
+		FileSet:              s.fs,
+		GoVersion:            s.goVersion,
+		CommentAssociation:   p.CommentAssociation,
+		StrictDuplicateCheck: p.StrictDuplicateCheck,
+		UnexportedPolicy:     p.UnexportedPolicy,
+		statsCallback:        p.StatsCallback,
+		ctx:                  p.Context,
+		logger:               p.Logger,
+	}
+	var err error
+	pkg.measure(PhaseParse, func() {
+		for _, name := range names {
+			f := s.files[name]
+			if f.file == nil {
+				f.file, f.src, err = p.parseFile(s.fs, name, f.code)
+				if err != nil {
+					return
+				}
+				pkg.countFile(s.fs, f.file)
+			}
+			pkg.Files = append(pkg.Files, f.file)
+			pkg.setSource(name, f.src)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(pkg.Files) == 0 {
+		return nil, &NoGoFilesError{Dir: s.Dir}
+	}
+	if p.DeferTypeCheck {
+		pkg.deferred = &deferredCheck{parser: p, checked: make(map[*FileInfo]bool)}
+	} else if err := p.checkPackage(pkg); err != nil {
+		return nil, err
+	}
+
+	s.pkg = pkg
+	return pkg, nil
+}
+
+// removeStaleFiles removes files from FileSet that are not cached, they are invalidated or fully parsed for former state.
+func (s *Session) removeStaleFiles() {
+	live := make(map[*token.File]bool)
+	for _, f := range s.files {
+		if f.file != nil {
+			live[s.fs.File(f.file.Package)] = true
+		}
+	}
+	var stale []*token.File
+	s.fs.Iterate(func(f *token.File) bool {
+		if !live[f] {
+			stale = append(stale, f)
+		}
+		return true
+	})
+	for _, f := range stale {
+		s.fs.RemoveFile(f)
+	}
+}
+
+func (s *Session) normalize(fileName string) string {
+	if isAbsPath(fileName) || filepath.Dir(normalizePath(fileName)) != "." {
+		return realPath(normalizePath(fileName))
+	}
+	return realPath(pathJoinAll(s.Dir, fileName)[0])
+}
@@ -0,0 +1,117 @@
+package genbase
+
+import (
+	"go/token"
+	"testing"
+)
+
+func TestSession(t *testing.T) {
+	s, err := NewSession(&Parser{}, "./misc/fixture/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pInfo, err := s.Package()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pInfo.TypeInfos()) != 3 {
+		t.Fatalf("unexpected: %d", len(pInfo.TypeInfos()))
+	}
+	if again, _ := s.Package(); again != pInfo {
+		t.Fatal("unexpected: package is parsed again")
+	}
+	modelFile := pInfo.Files[0]
+
+	s.FileChanged("./misc/fixture/a/extra.go", []byte("package a\n\ntype D struct{ A A }\n"))
+	pInfo, err = s.Package()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pInfo.TypeInfos()) != 4 {
+		t.Fatalf("unexpected: %d", len(pInfo.TypeInfos()))
+	}
+	if pInfo.Files[1] != modelFile {
+		t.Fatal("unexpected: unchanged file is parsed again")
+	}
+	if pInfo.Types.Scope().Lookup("D") == nil {
+		t.Fatal("unexpected: D is not resolved")
+	}
+
+	s.FileRemoved("misc/fixture/a/extra.go")
+	pInfo, err = s.Package()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pInfo.TypeInfos()) != 3 {
+		t.Fatalf("unexpected: %d", len(pInfo.TypeInfos()))
+	}
+}
+
+func TestSessionFileChangedIgnoresFiles(t *testing.T) {
+	s, err := NewSession(&Parser{ExcludeFiles: []string{"*_mock.go"}}, "./misc/fixture/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pInfo, err := s.Package()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.FileChanged("./misc/fixture/a/a_test.go", []byte("package a\n\ntype T struct{}\n"))
+	s.FileChanged("./misc/fixture/a/a_mock.go", []byte("package a\n\ntype M struct{}\n"))
+	s.FileChanged("./misc/fixture/a/README.md", []byte("# a\n"))
+	s.FileChanged("./misc/fixture/a/ignored.go", []byte("//go:build ignore\n\npackage main\n"))
+	s.FileChanged("./misc/fixture/extra.go", []byte("package fixture\n"))
+	if again, err := s.Package(); err != nil || again != pInfo {
+		t.Fatalf("unexpected: %v", err)
+	}
+
+	s.FileChanged("./misc/fixture/a/extra.go", []byte("package a\n\ntype D struct{}\n"))
+	if pInfo, err = s.Package(); err != nil || len(pInfo.TypeInfos()) != 4 {
+		t.Fatalf("unexpected: %v", err)
+	}
+	s.FileChanged("./misc/fixture/a/extra.go", []byte("//go:build ignore\n\npackage a\n\ntype D struct{}\n"))
+	if pInfo, err = s.Package(); err != nil || len(pInfo.TypeInfos()) != 3 {
+		t.Fatalf("unexpected: %v", err)
+	}
+}
+
+func TestSessionRemovesStaleFiles(t *testing.T) {
+	s, err := NewSession(&Parser{}, "./misc/fixture/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		s.FileChanged("./misc/fixture/a/extra.go", []byte("package a\n\ntype D struct{}\n"))
+		pInfo, err := s.Package()
+		if err != nil {
+			t.Fatal(err)
+		}
+		count := 0
+		pInfo.FileSet.Iterate(func(*token.File) bool {
+			count++
+			return true
+		})
+		if count != 2 {
+			t.Errorf("unexpected: %d", count)
+		}
+	}
+}
+
+func TestSessionDeferTypeCheck(t *testing.T) {
+	s, err := NewSession(&Parser{DeferTypeCheck: true}, "./misc/fixture/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pInfo, err := s.Package()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pInfo.Types != nil {
+		t.Fatal("unexpected: types are checked")
+	}
+	if typeInfos := pInfo.CollectTypeInfos([]string{"A"}); len(typeInfos) != 1 || pInfo.Types == nil {
+		t.Fatalf("unexpected: %v", typeInfos)
+	}
+}
@@ -0,0 +1,112 @@
+package genbase
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+)
+
+// Param is parameter or result of function type.
+type Param struct {
+	Name     string   // "" if parameter is unnamed
+	Type     ast.Expr // element type if parameter is variadic
+	Variadic bool
+}
+
+// Signature is parameter and result lists of function type.
+type Signature struct {
+	Params  []*Param
+	Results []*Param
+}
+
+// NewSignature creates Signature from ast.FuncType.
+// parameters declared together like "a, b int" are split into each Param.
+func NewSignature(ft *ast.FuncType) *Signature {
+	return &Signature{
+		Params:  newParams(ft.Params),
+		Results: newParams(ft.Results),
+	}
+}
+
+func newParams(list *ast.FieldList) []*Param {
+	if list == nil {
+		return nil
+	}
+	var params []*Param
+	for _, f := range list.List {
+		param := &Param{Type: f.Type}
+		if ellipsis, ok := f.Type.(*ast.Ellipsis); ok {
+			param.Type = ellipsis.Elt
+			param.Variadic = true
+		}
+		if len(f.Names) == 0 {
+			params = append(params, param)
+			continue
+		}
+		for _, name := range f.Names {
+			p := *param
+			p.Name = name.Name
+			params = append(params, &p)
+		}
+	}
+	return params
+}
+
+// Signature returns Signature of function type. returns nil if FieldInfo is not function.
+func (f *FieldInfo) Signature() *Signature {
+	ft, ok := f.Type.(*ast.FuncType)
+	if !ok {
+		return nil
+	}
+	return NewSignature(ft)
+}
+
+// Variadic returns true if last parameter is variadic, otherwise returns false.
+func (sig *Signature) Variadic() bool {
+	return len(sig.Params) != 0 && sig.Params[len(sig.Params)-1].Variadic
+}
+
+// String returns Go source of function type. e.g. "func(a int, b ...string) error"
+func (sig *Signature) String() string {
+	results := sig.ResultsString()
+	if results != "" {
+		results = " " + results
+	}
+	return "func" + sig.ParamsString() + results
+}
+
+// ParamsString returns Go source of parameter list. e.g. "(a int, b ...string)"
+func (sig *Signature) ParamsString() string {
+	return "(" + paramsString(sig.Params) + ")"
+}
+
+// ResultsString returns Go source of result list. e.g. "", "error", "(n int, err error)"
+func (sig *Signature) ResultsString() string {
+	switch {
+	case len(sig.Results) == 0:
+		return ""
+	case len(sig.Results) == 1 && sig.Results[0].Name == "":
+		return sig.Results[0].TypeString()
+	}
+	return "(" + paramsString(sig.Results) + ")"
+}
+
+// TypeString returns Go source of parameter type. e.g. "...string"
+func (p *Param) TypeString() string {
+	if p.Variadic {
+		return "..." + types.ExprString(p.Type)
+	}
+	return types.ExprString(p.Type)
+}
+
+func paramsString(params []*Param) string {
+	ss := make([]string, 0, len(params))
+	for _, p := range params {
+		if p.Name != "" {
+			ss = append(ss, p.Name+" "+p.TypeString())
+		} else {
+			ss = append(ss, p.TypeString())
+		}
+	}
+	return strings.Join(ss, ", ")
+}
@@ -0,0 +1,56 @@
+package genbase
+
+import (
+	"testing"
+)
+
+func TestFieldInfoSignature(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	type Sample struct {
+		A func()
+		B func(a, b int, opts ...string) error
+		C func(int) (n int, err error)
+		D int
+	}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := pInfo.CollectTypeInfos([]string{"Sample"})[0].StructType()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := st.FieldInfos()
+	expects := []string{
+		"func()",
+		"func(a int, b int, opts ...string) error",
+		"func(int) (n int, err error)",
+	}
+	for i, expect := range expects {
+		sig := fields[i].Signature()
+		if sig == nil {
+			t.Fatalf("unexpected: %v", sig)
+		}
+		if v := sig.String(); v != expect {
+			t.Errorf("unexpected: %s", v)
+		}
+	}
+
+	sig := fields[1].Signature()
+	if len(sig.Params) != 3 || sig.Params[1].Name != "b" || !sig.Variadic() {
+		t.Fatalf("unexpected: %#v", sig.Params)
+	}
+	if p := sig.Params[2]; p.Name != "opts" || p.TypeString() != "...string" {
+		t.Fatalf("unexpected: %#v", p)
+	}
+	if fields[2].Signature().Variadic() {
+		t.Fatalf("unexpected: %v", true)
+	}
+	if sig := fields[3].Signature(); sig != nil {
+		t.Fatalf("unexpected: %#v", sig)
+	}
+}
@@ -0,0 +1,65 @@
+package genbase
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// Sizes returns types.Sizes that is used for type checking.
+func (pkg *PackageInfo) Sizes() types.Sizes {
+	return pkg.checkConfig().Sizes
+}
+
+// Sizeof returns size of field type in bytes.
+// returns -1 if types are not resolved.
+func (f *FieldInfo) Sizeof(pkg *PackageInfo) int64 {
+	typ := pkg.TypeOf(f.Type)
+	if typ == nil {
+		return -1
+	}
+	return pkg.Sizes().Sizeof(typ)
+}
+
+// Alignof returns alignment of field type in bytes.
+// returns -1 if types are not resolved.
+func (f *FieldInfo) Alignof(pkg *PackageInfo) int64 {
+	typ := pkg.TypeOf(f.Type)
+	if typ == nil {
+		return -1
+	}
+	return pkg.Sizes().Alignof(typ)
+}
+
+// Sizeof returns size of struct in bytes.
+// returns -1 if types are not resolved.
+func (st *StructTypeInfo) Sizeof(pkg *PackageInfo) int64 {
+	typ := pkg.TypeOf((*ast.StructType)(st))
+	if typ == nil {
+		return -1
+	}
+	return pkg.Sizes().Sizeof(typ)
+}
+
+// Offsetof returns offset of field that has name in struct in bytes.
+// returns -1 if types are not resolved or field is not found.
+func (st *StructTypeInfo) Offsetof(pkg *PackageInfo, name string) int64 {
+	typ := pkg.TypeOf((*ast.StructType)(st))
+	if typ == nil {
+		return -1
+	}
+	s, ok := typ.(*types.Struct)
+	if !ok {
+		return -1
+	}
+	fields := make([]*types.Var, s.NumFields())
+	for i := range fields {
+		fields[i] = s.Field(i)
+	}
+	offsets := pkg.Sizes().Offsetsof(fields)
+	for i, f := range fields {
+		if f.Name() == name {
+			return offsets[i]
+		}
+	}
+	return -1
+}
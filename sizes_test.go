@@ -0,0 +1,48 @@
+package genbase
+
+import (
+	"go/types"
+	"testing"
+)
+
+func TestStructTypeInfoOffsetof(t *testing.T) {
+	p := &Parser{Sizes: types.SizesFor("gc", "amd64")}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	type Sample struct {
+		A    bool
+		B    int64
+		C, D int32
+		E    string
+	}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := pInfo.CollectTypeInfos([]string{"Sample"})[0].StructType()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := st.Sizeof(pInfo); v != 40 {
+		t.Errorf("unexpected: %v", v)
+	}
+	expects := map[string]int64{"A": 0, "B": 8, "C": 16, "D": 20, "E": 24, "F": -1}
+	for name, expect := range expects {
+		if v := st.Offsetof(pInfo, name); v != expect {
+			t.Errorf("unexpected: %s %v", name, v)
+		}
+	}
+
+	fields := st.FieldInfos()
+	if v := fields[1].Sizeof(pInfo); v != 8 {
+		t.Errorf("unexpected: %v", v)
+	}
+	if v := fields[2].Alignof(pInfo); v != 4 {
+		t.Errorf("unexpected: %v", v)
+	}
+	if v := fields[3].Sizeof(pInfo); v != 16 {
+		t.Errorf("unexpected: %v", v)
+	}
+}
@@ -0,0 +1,99 @@
+package genbase
+
+import (
+	"go/ast"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// SkipTagKey is struct tag key that is recognized by all generators.
+// field that has `gen:"-"` tag or "// +gen:-" annotation is excluded from generation.
+const SkipTagKey = "gen"
+
+// IsSkipped returns true if field is marked to be excluded from generation, otherwise returns false.
+// tag is struct tag key or annotation of generator, e.g. "json" or "+jwg", leading "+" is ignored.
+// field is skipped if it has `gen:"-"` or `<tag>:"-"` tag, or "+gen:-" or "+<tag>:-" annotation in doc or line comment.
+// `<tag>:"-,"` is not a skip marker, it means field is named "-" likes encoding/json.
+func (f *FieldInfo) IsSkipped(tag string) bool {
+	keys := []string{SkipTagKey}
+	if key := strings.TrimPrefix(tag, "+"); key != "" && key != SkipTagKey {
+		keys = append(keys, key)
+	}
+
+	var structTag reflect.StructTag
+	if f.Tag != nil {
+		if s, err := strconv.Unquote(f.Tag.Value); err == nil {
+			structTag = reflect.StructTag(s)
+		}
+	}
+	for _, key := range keys {
+		if structTag.Get(key) == "-" {
+			return true
+		}
+		for _, doc := range []*ast.CommentGroup{f.Doc, f.Comment} {
+			if isSkipAnnotation(findAnnotation(doc, "+"+key), "+"+key) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isSkipAnnotation returns true if c is "+<key>:-", otherwise returns false.
+func isSkipAnnotation(c *ast.Comment, directive string) bool {
+	if c == nil {
+		return false
+	}
+	opts := strings.TrimPrefix(strings.TrimLeft(c.Text, "/ "), directive)
+	return strings.TrimSpace(strings.TrimPrefix(opts, ":")) == "-"
+}
+
+// SuppressDirective is directive to suppress generation by specific generators. e.g. "//genbase:ignore jwg,qbg"
+// all generators are suppressed if generator names are omitted.
+const SuppressDirective = "genbase:ignore"
+
+// IsSuppressedFor returns true if type has "//genbase:ignore" directive for tool, otherwise returns false.
+// tool is name of generator, e.g. "jwg" or "+jwg", leading "+" is ignored.
+func (t *TypeInfo) IsSuppressedFor(tool string) bool {
+	for _, doc := range t.Comments() {
+		if isSuppressed(doc, tool) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSuppressedFor returns true if field has "//genbase:ignore" directive for tool in doc or line comment, otherwise returns false.
+// see TypeInfo.IsSuppressedFor.
+func (f *FieldInfo) IsSuppressedFor(tool string) bool {
+	return isSuppressed(f.Doc, tool) || isSuppressed(f.Comment, tool)
+}
+
+// isSuppressed returns true if doc has "//genbase:ignore" directive for tool, otherwise returns false.
+func isSuppressed(doc *ast.CommentGroup, tool string) bool {
+	if doc == nil {
+		return false
+	}
+	tool = strings.TrimPrefix(tool, "+")
+	for _, c := range doc.List {
+		text := strings.TrimLeft(c.Text, "/ ")
+		if !strings.HasPrefix(text, SuppressDirective) {
+			continue
+		}
+		text = text[len(SuppressDirective):]
+		if text != "" && text[0] != ' ' && text[0] != '\t' {
+			continue
+		}
+		tools := strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+		if len(tools) == 0 {
+			return true
+		}
+		for _, name := range tools {
+			if strings.TrimPrefix(name, "+") == tool {
+				return true
+			}
+		}
+	}
+	return false
+}
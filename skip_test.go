@@ -0,0 +1,95 @@
+package genbase
+
+import (
+	"testing"
+)
+
+func TestFieldInfoIsSkipped(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	type Sample struct {
+		A string `+"`gen:\"-\"`"+`
+		B string `+"`jwg:\"-\"`"+`
+		C string `+"`jwg:\"-,\"`"+`
+		// +jwg:-
+		D string
+		E string // +gen: -
+		F string // +jwg
+		G string `+"`json:\"g\"`"+`
+	}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := pInfo.CollectTypeInfos([]string{"Sample"})[0].StructType()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expects := map[string][2]bool{
+		"A": {true, true},
+		"B": {true, false},
+		"C": {false, false},
+		"D": {true, false},
+		"E": {true, true},
+		"F": {false, false},
+		"G": {false, false},
+	}
+	for _, f := range st.FieldInfos() {
+		expect := expects[f.Name()]
+		if v := f.IsSkipped("+jwg"); v != expect[0] {
+			t.Errorf("unexpected: %s %v", f.Name(), v)
+		}
+		if v := f.IsSkipped("qbg"); v != expect[1] {
+			t.Errorf("unexpected: %s %v", f.Name(), v)
+		}
+	}
+}
+
+func TestIsSuppressedFor(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	//genbase:ignore jwg,qbg
+	type Sample struct {
+		//genbase:ignore
+		A string
+		B string //genbase:ignore smg
+		C string //genbase:ignored
+	}
+
+	type Other struct{}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := pInfo.TypeInfos()
+	if !types[0].IsSuppressedFor("jwg") || !types[0].IsSuppressedFor("+qbg") || types[0].IsSuppressedFor("smg") {
+		t.Error("unexpected: Sample")
+	}
+	if types[1].IsSuppressedFor("jwg") {
+		t.Error("unexpected: Other")
+	}
+
+	st, err := types[0].StructType()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expects := map[string][2]bool{
+		"A": {true, true},
+		"B": {false, true},
+		"C": {false, false},
+	}
+	for _, f := range st.FieldInfos() {
+		expect := expects[f.Name()]
+		if v := f.IsSuppressedFor("jwg"); v != expect[0] {
+			t.Errorf("unexpected: %s %v", f.Name(), v)
+		}
+		if v := f.IsSuppressedFor("smg"); v != expect[1] {
+			t.Errorf("unexpected: %s %v", f.Name(), v)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package genbase
+
+import (
+	"errors"
+	"go/ast"
+)
+
+// ErrNoSource shows source bytes of node are not available.
+var ErrNoSource = errors.New("source is not available")
+
+func (pkg *PackageInfo) setSource(fileName string, src []byte) {
+	pkg.cacheMu.Lock()
+	defer pkg.cacheMu.Unlock()
+
+	if pkg.sources == nil {
+		pkg.sources = make(map[string][]byte)
+	}
+	pkg.sources[fileName] = src
+}
+
+// Source returns source bytes of file.
+// returns nil if it is not available.
+func (pkg *PackageInfo) Source(file *FileInfo) []byte {
+	if pkg.FileSet == nil {
+		return nil
+	}
+	pkg.cacheMu.Lock()
+	defer pkg.cacheMu.Unlock()
+
+	return pkg.sources[pkg.FileSet.Position(file.Package).Filename]
+}
+
+// ExprSource returns source text of node as it is written, includes comments and formatting.
+func (pkg *PackageInfo) ExprSource(node ast.Node) (string, error) {
+	if pkg.FileSet == nil || node == nil {
+		return "", ErrNoSource
+	}
+	start := pkg.FileSet.Position(node.Pos())
+	end := pkg.FileSet.Position(node.End())
+
+	pkg.cacheMu.Lock()
+	src, ok := pkg.sources[start.Filename]
+	pkg.cacheMu.Unlock()
+
+	if !ok || start.Filename != end.Filename || end.Offset > len(src) || start.Offset > end.Offset {
+		return "", ErrNoSource
+	}
+	return string(src[start.Offset:end.Offset]), nil
+}
+
+// TypeSourceText returns source text of field type as it is written.
+// returns TypeName() if source is not available.
+func (f *FieldInfo) TypeSourceText(pkg *PackageInfo) string {
+	text, err := pkg.ExprSource(f.Type)
+	if err != nil {
+		return f.TypeName()
+	}
+	return text
+}
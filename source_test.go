@@ -0,0 +1,49 @@
+package genbase
+
+import (
+	"testing"
+)
+
+func TestFieldInfoTypeSourceText(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	type Sample struct {
+		A map[string]/* key to values */ []int
+		B func(a int,
+			b string) error
+		C *Sample
+	}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	st, err := pInfo.CollectTypeInfos([]string{"Sample"})[0].StructType()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expects := []string{
+		"map[string]/* key to values */ []int",
+		"func(a int,\n\t\t\tb string) error",
+		"*Sample",
+	}
+	for i, f := range st.FieldInfos() {
+		if v := f.TypeSourceText(pInfo); v != expects[i] {
+			t.Errorf("unexpected: %s", v)
+		}
+	}
+
+	pInfo, err = p.ParsePackageDir("./misc/fixture/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ti := pInfo.CollectTypeInfos([]string{"A"})[0]
+	if v, err := pInfo.ExprSource(ti.TypeSpec); err != nil || v != "A struct {\n}" {
+		t.Errorf("unexpected: %s %v", v, err)
+	}
+	if len(pInfo.Source(pInfo.Files[0])) == 0 {
+		t.Error("unexpected: source is empty")
+	}
+}
@@ -0,0 +1,130 @@
+package genbase
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+)
+
+// SourceMapSuffix is suffix of source map file of generated file. e.g. "model_json.go.map.json"
+const SourceMapSuffix = ".map.json"
+
+// SourceMap maps declarations of generated file to source types and fields that produced them.
+type SourceMap struct {
+	File    string            `json:"file"`
+	Entries []*SourceMapEntry `json:"entries"`
+}
+
+// SourceMapEntry is declaration of generated file and its origin.
+type SourceMapEntry struct {
+	Decl   string          `json:"decl"`   // e.g. "SampleJSON", "SampleJSON.Convert"
+	Line   int             `json:"line"`   // line of declaration in generated file
+	Origin string          `json:"origin"` // e.g. "Sample", "Sample.Name"
+	Source *SourcePosition `json:"source"`
+}
+
+// SourcePosition is position of origin in source file.
+type SourcePosition struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+// sourceOrigin is origin of generated declaration that is recorded by Generator.
+type sourceOrigin struct {
+	decl   string
+	origin string
+	pos    token.Position
+}
+
+// MapTypeOrigin records that generated declaration decl is produced by type t.
+// decl is name of type, func, var or const, and "Recv.Name" for method.
+func (g *Generator) MapTypeOrigin(decl string, t *TypeInfo) {
+	g.origins = append(g.origins, &sourceOrigin{
+		decl:   decl,
+		origin: t.Name(),
+		pos:    t.PackageInfo.position(t.TypeSpec.Pos()),
+	})
+}
+
+// MapFieldOrigin records that generated declaration decl is produced by field f of type t.
+func (g *Generator) MapFieldOrigin(decl string, t *TypeInfo, f *FieldInfo) {
+	g.origins = append(g.origins, &sourceOrigin{
+		decl:   decl,
+		origin: t.Name() + "." + f.Name(),
+		pos:    t.PackageInfo.position(f.Pos()),
+	})
+}
+
+// SourceMap returns SourceMap of generated code src, fileName is name of generated file.
+// src is usually result of Format. returns error if recorded declaration is not found in src.
+func (g *Generator) SourceMap(fileName string, src []byte) (*SourceMap, error) {
+	fs := token.NewFileSet()
+	file, err := parser.ParseFile(fs, fileName, src, 0)
+	if err != nil {
+		return nil, fmt.Errorf("parsing generated code %s: %s", fileName, err)
+	}
+	lines := make(map[string]int)
+	for name, pos := range generatedDecls(file) {
+		lines[name] = fs.Position(pos).Line
+	}
+
+	sm := &SourceMap{File: fileName, Entries: make([]*SourceMapEntry, 0, len(g.origins))}
+	for _, o := range g.origins {
+		line, ok := lines[o.decl]
+		if !ok {
+			return nil, fmt.Errorf("declaration %s is not found in generated code %s", o.decl, fileName)
+		}
+		sm.Entries = append(sm.Entries, &SourceMapEntry{
+			Decl:   o.decl,
+			Line:   line,
+			Origin: o.origin,
+			Source: &SourcePosition{File: o.pos.Filename, Line: o.pos.Line, Column: o.pos.Column},
+		})
+	}
+	return sm, nil
+}
+
+// WriteSourceMap writes SourceMap of generated code src to fileName with SourceMapSuffix.
+func (g *Generator) WriteSourceMap(fileName string, src []byte) error {
+	sm, err := g.SourceMap(fileName, src)
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(sm, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fileName+SourceMapSuffix, append(b, '\n'), 0644)
+}
+
+// generatedDecls returns positions of top-level declarations of file by names that MapTypeOrigin accepts.
+func generatedDecls(file *ast.File) map[string]token.Pos {
+	decls := make(map[string]token.Pos)
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					decls[spec.Name.Name] = spec.Pos()
+				case *ast.ValueSpec:
+					for _, ident := range spec.Names {
+						decls[ident.Name] = ident.Pos()
+					}
+				}
+			}
+		case *ast.FuncDecl:
+			fn := &FuncInfo{FuncDecl: decl}
+			if fn.IsMethod() {
+				decls[fn.ReceiverTypeName()+"."+fn.Name()] = decl.Pos()
+			} else {
+				decls[fn.Name()] = decl.Pos()
+			}
+		}
+	}
+	return decls
+}
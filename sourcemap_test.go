@@ -0,0 +1,90 @@
+package genbase
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGeneratorSourceMap(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("model.go", "package sample\n\n// +test\ntype Sample struct {\n\tID   int64\n\tName string\n}\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	typeInfos := pInfo.CollectTaggedTypeInfos("+test")
+	if len(typeInfos) != 1 {
+		t.Fatalf("unexpected: %d", len(typeInfos))
+	}
+	st := typeInfos[0]
+	structType, err := st.StructType()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := structType.FieldInfos()
+
+	g := NewGenerator(pInfo)
+	g.PrintHeader("sample", &[]string{})
+	g.Printf("type SampleJSON struct {\nName string\n}\n\n")
+	g.Printf("func (src *Sample) ToJSON() *SampleJSON {\nreturn &SampleJSON{Name: src.Name}\n}\n\n")
+	g.Printf("const sampleNameKey = \"name\"\n")
+	g.MapTypeOrigin("SampleJSON", st)
+	g.MapTypeOrigin("Sample.ToJSON", st)
+	g.MapFieldOrigin("sampleNameKey", st, fields[1])
+	src, err := g.Format()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sm, err := g.SourceMap("model_json.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sm.Entries) != 3 {
+		t.Fatalf("unexpected: %d", len(sm.Entries))
+	}
+	expects := []struct {
+		decl   string
+		line   int
+		origin string
+		source SourcePosition
+	}{
+		{"SampleJSON", 7, "Sample", SourcePosition{File: "model.go", Line: 4, Column: 6}},
+		{"Sample.ToJSON", 11, "Sample", SourcePosition{File: "model.go", Line: 4, Column: 6}},
+		{"sampleNameKey", 15, "Sample.Name", SourcePosition{File: "model.go", Line: 6, Column: 2}},
+	}
+	for i, expect := range expects {
+		entry := sm.Entries[i]
+		if entry.Decl != expect.decl || entry.Line != expect.line || entry.Origin != expect.origin || *entry.Source != expect.source {
+			t.Errorf("unexpected: %d %#v %#v", i, entry, entry.Source)
+		}
+	}
+
+	dir, err := ioutil.TempDir("", "sourcemap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fileName := filepath.Join(dir, "model_json.go")
+	if err := g.WriteSourceMap(fileName, src); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(fileName + SourceMapSuffix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var written SourceMap
+	if err := json.Unmarshal(b, &written); err != nil {
+		t.Fatal(err)
+	}
+	if written.File != fileName || len(written.Entries) != 3 || written.Entries[2].Source.Line != 6 {
+		t.Errorf("unexpected: %s", b)
+	}
+
+	g.MapTypeOrigin("Missing", st)
+	if _, err := g.SourceMap("model_json.go", src); err == nil {
+		t.Errorf("unexpected: %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package genbase
+
+import (
+	"context"
+	"go/token"
+	"runtime/pprof"
+	"time"
+)
+
+// Phase is phase of genbase processing.
+// it is also used as value of "genbase" pprof label.
+type Phase string
+
+const (
+	// PhaseParse is parsing of files.
+	PhaseParse Phase = "parse"
+	// PhaseTypeCheck is resolving types of package.
+	PhaseTypeCheck Phase = "typecheck"
+	// PhaseCollect is collecting TypeInfos from package.
+	PhaseCollect Phase = "collect"
+)
+
+// Stats is timings and counters of processing package.
+type Stats struct {
+	Files         int // parsed files
+	Bytes         int // size of parsed files
+	Collects      int // calls of collecting TypeInfos
+	ParseTime     time.Duration
+	TypeCheckTime time.Duration
+	CollectTime   time.Duration
+}
+
+// Stats returns timings and counters of processing package.
+func (pkg *PackageInfo) Stats() Stats {
+	pkg.statsMu.Lock()
+	defer pkg.statsMu.Unlock()
+	return pkg.stats
+}
+
+func (pkg *PackageInfo) countFile(fs *token.FileSet, file *FileInfo) {
+	pkg.statsMu.Lock()
+	defer pkg.statsMu.Unlock()
+	pkg.stats.Files++
+	if f := fs.File(file.Package); f != nil {
+		pkg.stats.Bytes += f.Size()
+	}
+}
+
+// measure runs f with pprof label of phase under context of Parser, and records its elapsed time.
+func (pkg *PackageInfo) measure(phase Phase, f func()) {
+	ctx := pkg.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	start := time.Now()
+	pprof.Do(ctx, pprof.Labels("genbase", string(phase)), func(context.Context) {
+		f()
+	})
+	elapsed := time.Since(start)
+
+	pkg.statsMu.Lock()
+	switch phase {
+	case PhaseParse:
+		pkg.stats.ParseTime += elapsed
+	case PhaseTypeCheck:
+		pkg.stats.TypeCheckTime += elapsed
+	case PhaseCollect:
+		pkg.stats.CollectTime += elapsed
+		pkg.stats.Collects++
+	}
+	pkg.statsMu.Unlock()
+
+	if pkg.statsCallback != nil {
+		pkg.statsCallback(phase, elapsed)
+	}
+}
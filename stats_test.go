@@ -0,0 +1,47 @@
+package genbase
+
+import (
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPackageInfoStats(t *testing.T) {
+	var phases []Phase
+	p := &Parser{
+		StatsCallback: func(phase Phase, elapsed time.Duration) {
+			phases = append(phases, phase)
+		},
+	}
+	pInfo, err := p.ParsePackageDir("./misc/fixture/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pInfo.CollectTaggedTypeInfos("+test")
+
+	stats := pInfo.Stats()
+	if stats.Files != 1 || stats.Bytes == 0 || stats.Collects != 1 {
+		t.Fatalf("unexpected: %#v", stats)
+	}
+	if stats.ParseTime == 0 || stats.TypeCheckTime == 0 {
+		t.Fatalf("unexpected: %#v", stats)
+	}
+	if len(phases) != 3 || phases[0] != PhaseParse || phases[1] != PhaseTypeCheck || phases[2] != PhaseCollect {
+		t.Fatalf("unexpected: %v", phases)
+	}
+}
+
+func TestPackageInfoMeasureContext(t *testing.T) {
+	ctx := pprof.WithLabels(context.Background(), pprof.Labels("caller", "test"))
+	pkg := &PackageInfo{ctx: ctx}
+	var buf bytes.Buffer
+	pkg.measure(PhaseParse, func() {
+		pprof.Lookup("goroutine").WriteTo(&buf, 1)
+	})
+	if v := buf.String(); !strings.Contains(v, `"caller":"test"`) || !strings.Contains(v, `"genbase":"parse"`) {
+		t.Errorf("unexpected: %s", v)
+	}
+}
@@ -0,0 +1,102 @@
+package genbase
+
+import (
+	"go/ast"
+)
+
+// Traversal walks type and all types reachable from its fields.
+// types are resolved from package of each type or parsed packages, unresolved types are ignored.
+type Traversal struct {
+	// MaxDepth is max number of hops from root type, 0 means no limit.
+	MaxDepth int
+	// Parsed are packages that are used to resolve types in other packages. see FieldInfo.ResolveTypeInfo.
+	Parsed []*PackageInfo
+	// VisitType is called with each type once, depth of root type is 0.
+	// types referred from t are skipped if it returns false.
+	VisitType func(t *TypeInfo, depth int) bool
+}
+
+// Reachable returns t and types reachable from t in breadth first order.
+// see Traversal about resolution of types.
+func (t *TypeInfo) Reachable(maxDepth int, parsed ...*PackageInfo) TypeInfos {
+	var reachable TypeInfos
+	tr := &Traversal{
+		MaxDepth: maxDepth,
+		Parsed:   parsed,
+		VisitType: func(t *TypeInfo, depth int) bool {
+			reachable = append(reachable, t)
+			return true
+		},
+	}
+	tr.Walk(t)
+	return reachable
+}
+
+// Walk walks roots and types reachable from them in breadth first order.
+// each type is visited once even if it is referred recursively or from multiple roots.
+func (tr *Traversal) Walk(roots ...*TypeInfo) {
+	type entry struct {
+		t     *TypeInfo
+		depth int
+	}
+	visited := make(map[*ast.TypeSpec]bool)
+	var queue []entry
+	for _, t := range roots {
+		if !visited[t.TypeSpec] {
+			visited[t.TypeSpec] = true
+			queue = append(queue, entry{t: t})
+		}
+	}
+
+	for len(queue) != 0 {
+		e := queue[0]
+		queue = queue[1:]
+		if tr.VisitType != nil && !tr.VisitType(e.t, e.depth) {
+			continue
+		}
+		if tr.MaxDepth > 0 && e.depth >= tr.MaxDepth {
+			continue
+		}
+		for _, expr := range typeRefs(e.t.TypeSpec.Type, nil) {
+			ref := (*FieldInfo)(&ast.Field{Type: expr}).ResolveTypeInfo(e.t.PackageInfo, tr.Parsed...)
+			if ref == nil || visited[ref.TypeSpec] {
+				continue
+			}
+			visited[ref.TypeSpec] = true
+			queue = append(queue, entry{t: ref, depth: e.depth + 1})
+		}
+	}
+}
+
+// typeRefs appends type names that are referred by expr to refs, and returns refs.
+// types in signature of funcs and methods of interfaces are not data, they are ignored.
+func typeRefs(expr ast.Expr, refs []ast.Expr) []ast.Expr {
+	switch e := expr.(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+		refs = append(refs, e)
+	case *ast.StarExpr:
+		refs = typeRefs(e.X, refs)
+	case *ast.ParenExpr:
+		refs = typeRefs(e.X, refs)
+	case *ast.ArrayType:
+		refs = typeRefs(e.Elt, refs)
+	case *ast.MapType:
+		refs = typeRefs(e.Key, refs)
+		refs = typeRefs(e.Value, refs)
+	case *ast.ChanType:
+		refs = typeRefs(e.Value, refs)
+	case *ast.StructType:
+		for _, f := range e.Fields.List {
+			refs = typeRefs(f.Type, refs)
+		}
+	case *ast.IndexExpr:
+		refs = typeRefs(e.X, refs)
+		refs = typeRefs(e.Index, refs)
+	case *ast.IndexListExpr:
+		refs = typeRefs(e.X, refs)
+		for _, index := range e.Indices {
+			refs = typeRefs(index, refs)
+		}
+	}
+	return refs
+}
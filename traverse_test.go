@@ -0,0 +1,68 @@
+package genbase
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTypeInfoReachable(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSources(map[string]string{
+		"main.go": "package sample\n" +
+			"import \"example.com/other\"\n" +
+			"type Root struct {\n" +
+			"	Child    *Child\n" +
+			"	Items    []Item\n" +
+			"	ByName   map[Key]*Item\n" +
+			"	Inline   struct{ Leaf Leaf }\n" +
+			"	Ext      other.Ext\n" +
+			"	Callback func(Ignored) error\n" +
+			"}\n" +
+			"type Child struct{ Parent *Root; Deep Deep }\n" +
+			"type Item struct{ Child Child }\n" +
+			"type Key string\n" +
+			"type Leaf int\n" +
+			"type Deep struct{ Deeper Deeper }\n" +
+			"type Deeper struct{}\n" +
+			"type Ignored struct{}\n",
+	}, map[string]map[string]string{
+		"example.com/other": {"other.go": "package other\ntype Ext struct{ Sub Sub }\ntype Sub struct{}\n"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := p.ParseStringSource("other.go", "package other\ntype Ext struct{ Sub Sub }\ntype Sub struct{}\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := pInfo.CollectTypeInfos([]string{"Root"})[0]
+
+	names := func(typeInfos TypeInfos) string {
+		var ss []string
+		for _, t := range typeInfos {
+			ss = append(ss, t.Name())
+		}
+		return strings.Join(ss, ",")
+	}
+	if v := names(root.Reachable(0)); v != "Root,Child,Item,Key,Leaf,Deep,Deeper" {
+		t.Errorf("unexpected: %s", v)
+	}
+	if v := names(root.Reachable(0, other)); v != "Root,Child,Item,Key,Leaf,Ext,Deep,Sub,Deeper" {
+		t.Errorf("unexpected: %s", v)
+	}
+	if v := names(root.Reachable(1)); v != "Root,Child,Item,Key,Leaf" {
+		t.Errorf("unexpected: %s", v)
+	}
+
+	var visited []string
+	tr := &Traversal{
+		VisitType: func(t *TypeInfo, depth int) bool {
+			visited = append(visited, t.Name())
+			return t.Name() != "Child"
+		},
+	}
+	tr.Walk(pInfo.CollectTypeInfos([]string{"Child", "Item"})...)
+	if v := strings.Join(visited, ","); v != "Child,Item" {
+		t.Errorf("unexpected: %s", v)
+	}
+}
@@ -0,0 +1,99 @@
+package genbase
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// UnexportedPolicy is policy for annotated types that are unexported or have no exported fields.
+type UnexportedPolicy int
+
+const (
+	// UnexportedAllow accepts such types silently.
+	UnexportedAllow UnexportedPolicy = iota
+	// UnexportedWarn reports such types to Logger and accepts them.
+	UnexportedWarn
+	// UnexportedError makes FindTaggedTypeInfos fail with UnexportedTypeErrors.
+	UnexportedError
+)
+
+// UnexportedTypeError shows that annotated type is unexported or all fields of it are unexported.
+type UnexportedTypeError struct {
+	Position token.Position
+	Tag      string
+	TypeName string
+	Reason   string
+}
+
+// UnexportedTypeErrors is []*UnexportedTypeError synonym.
+type UnexportedTypeErrors []*UnexportedTypeError
+
+func (err *UnexportedTypeError) Error() string {
+	return fmt.Sprintf("%s: type %s annotated with %s %s", err.Position, err.TypeName, err.Tag, err.Reason)
+}
+
+func (errs UnexportedTypeErrors) Error() string {
+	ss := make([]string, 0, len(errs))
+	for _, err := range errs {
+		ss = append(ss, err.Error())
+	}
+	return strings.Join(ss, "\n")
+}
+
+// UnexportedReason returns reason why generators may not work with type, or empty string.
+// type is reported if it is unexported, or it is struct that has fields and all of them are unexported.
+// embedded field of exported type counts as exported field.
+func (t *TypeInfo) UnexportedReason() string {
+	if !ast.IsExported(t.Name()) {
+		return "is unexported"
+	}
+	st, err := t.StructType()
+	if err != nil || len(st.FieldInfos()) == 0 {
+		return ""
+	}
+	for _, f := range st.FieldInfos() {
+		if len(f.Names) == 0 && ast.IsExported(f.Name()) {
+			return ""
+		}
+		for _, ident := range f.Names {
+			if ident.IsExported() {
+				return ""
+			}
+		}
+	}
+	return "has no exported fields"
+}
+
+// checkUnexported applies UnexportedPolicy to typeInfos annotated with tag.
+func (pkg *PackageInfo) checkUnexported(tag string, typeInfos TypeInfos) error {
+	if pkg.UnexportedPolicy == UnexportedAllow {
+		return nil
+	}
+
+	var errs UnexportedTypeErrors
+	for _, t := range typeInfos {
+		reason := t.UnexportedReason()
+		if reason == "" {
+			continue
+		}
+		err := &UnexportedTypeError{
+			Position: pkg.position(t.TypeSpec.Pos()),
+			Tag:      tag,
+			TypeName: t.Name(),
+			Reason:   reason,
+		}
+		if pkg.UnexportedPolicy == UnexportedWarn {
+			if pkg.logger != nil {
+				pkg.logger.Warnf("%s", err)
+			}
+			continue
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package genbase
+
+import (
+	"testing"
+)
+
+const unexportedSource = "package sample\n" +
+	"// +sample\n" +
+	"type Public struct {\n" +
+	"	Name string\n" +
+	"}\n" +
+	"// +sample\n" +
+	"type private struct {\n" +
+	"	Name string\n" +
+	"}\n" +
+	"// +sample\n" +
+	"type Hidden struct {\n" +
+	"	name string\n" +
+	"}\n" +
+	"// +sample\n" +
+	"type Embedding struct {\n" +
+	"	Public\n" +
+	"}\n" +
+	"// +sample\n" +
+	"type Empty struct{}\n"
+
+func TestTypeInfoUnexportedReason(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", unexportedSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expects := map[string]string{
+		"Public":    "",
+		"private":   "is unexported",
+		"Hidden":    "has no exported fields",
+		"Embedding": "",
+		"Empty":     "",
+	}
+	for _, typeInfo := range pInfo.TypeInfos() {
+		if v := typeInfo.UnexportedReason(); v != expects[typeInfo.Name()] {
+			t.Errorf("unexpected: %s %s", typeInfo.Name(), v)
+		}
+	}
+}
+
+func TestPackageInfoFindTaggedTypeInfosUnexportedPolicy(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", unexportedSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if typeInfos, err := pInfo.FindTaggedTypeInfos("+sample"); err != nil || len(typeInfos) != 5 {
+		t.Errorf("unexpected: %v %d", err, len(typeInfos))
+	}
+
+	logger := &recordingLogger{}
+	p = &Parser{UnexportedPolicy: UnexportedWarn, Logger: logger}
+	pInfo, err = p.ParseStringSource("main.go", unexportedSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.messages = nil
+	if typeInfos, err := pInfo.FindTaggedTypeInfos("+sample"); err != nil || len(typeInfos) != 5 {
+		t.Errorf("unexpected: %v %d", err, len(typeInfos))
+	}
+	if len(logger.messages) != 2 || logger.messages[0] != "warn: main.go:7:6: type private annotated with +sample is unexported" {
+		t.Errorf("unexpected: %v", logger.messages)
+	}
+
+	p = &Parser{UnexportedPolicy: UnexportedError}
+	pInfo, err = p.ParseStringSource("main.go", unexportedSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = pInfo.FindTaggedTypeInfos("+sample")
+	errs, ok := err.(UnexportedTypeErrors)
+	if !ok || len(errs) != 2 {
+		t.Fatalf("unexpected: %v", err)
+	}
+	if v := errs[1].Error(); v != "main.go:11:6: type Hidden annotated with +sample has no exported fields" {
+		t.Errorf("unexpected: %s", v)
+	}
+}
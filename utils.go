@@ -2,22 +2,140 @@ package genbase
 
 import (
 	"errors"
+	"fmt"
 	"go/ast"
+	"os"
 	"path/filepath"
 	"strings"
 )
 
+// pathJoinAll joins directory and each name. absolute names are kept as is.
+// separators are normalized for platform, e.g. "misc/fixture" is `misc\fixture` on Windows.
 func pathJoinAll(directory string, names ...string) []string {
-	if directory == "." {
-		return names
-	}
+	directory = normalizePath(directory)
 	ret := make([]string, len(names))
 	for i, name := range names {
-		ret[i] = filepath.Join(directory, name)
+		switch {
+		case isAbsPath(name):
+			ret[i] = filepath.Clean(filepath.FromSlash(name))
+		case directory == ".":
+			ret[i] = filepath.FromSlash(name)
+		default:
+			ret[i] = filepath.Join(directory, filepath.FromSlash(name))
+		}
 	}
 	return ret
 }
 
+// normalizePath returns cleaned path that has separators for platform. "" is ".".
+func normalizePath(path string) string {
+	if path == "" {
+		return "."
+	}
+	return filepath.Clean(filepath.FromSlash(path))
+}
+
+// isAbsPath returns true if path is absolute, otherwise returns false.
+// UNC path like `\\server\share\dir` is absolute on Windows.
+func isAbsPath(path string) bool {
+	return filepath.IsAbs(filepath.FromSlash(path))
+}
+
+// realPath returns path that symlinks are resolved.
+// if path doesn't exist, only its directory is resolved. e.g. removed file.
+func realPath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	if dir, err := filepath.EvalSymlinks(filepath.Dir(path)); err == nil {
+		return filepath.Join(dir, filepath.Base(path))
+	}
+	return filepath.Clean(path)
+}
+
+// dedupeFiles removes names that point same file as former names, through symlinks or case-insensitive file system.
+// returns ErrCaseCollision if names differ only in case but point different files.
+func dedupeFiles(names []string) ([]string, []*SkippedFile, error) {
+	var ret []string
+	var skipped []*SkippedFile
+	seen := make(map[string]string)   // real path -> name
+	folded := make(map[string]string) // lower case real path -> real path
+	for _, name := range names {
+		real := realPath(name)
+		if _, ok := seen[real]; ok {
+			skipped = appendSkippedFiles(skipped, SkipDuplicateFile, name)
+			continue
+		}
+		lower := strings.ToLower(real)
+		if other, ok := folded[lower]; ok {
+			if isSameFile(other, real) {
+				skipped = appendSkippedFiles(skipped, SkipDuplicateFile, name)
+				continue
+			}
+			return nil, nil, fmt.Errorf("parsing package: %s and %s: %w", seen[other], name, ErrCaseCollision)
+		}
+		seen[real] = name
+		folded[lower] = real
+		ret = append(ret, name)
+	}
+	return ret, skipped, nil
+}
+
+// isSameFile returns true if a and b are same file, otherwise returns false.
+func isSameFile(a, b string) bool {
+	x, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	y, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(x, y)
+}
+
+func appendSkippedFiles(skipped []*SkippedFile, reason SkipReason, names ...string) []*SkippedFile {
+	for _, name := range names {
+		skipped = append(skipped, &SkippedFile{Name: name, Reason: reason})
+	}
+	return skipped
+}
+
+// FindAnnotation finds *ast.Comment that has directive in doc.
+// e.g. "// +jwg" and "// +jwg: opts" have directive "+jwg".
+func FindAnnotation(doc *ast.CommentGroup, directive string) *ast.Comment {
+	return findAnnotation(doc, directive)
+}
+
+// guessPackageName guesses package name from import path.
+// e.g. "gopkg.in/yaml.v2" to "yaml", "github.com/foo/bar/v2" to "bar", "github.com/mattn/go-sqlite3" to "sqlite3".
+func guessPackageName(path string) string {
+	elems := strings.Split(path, "/")
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && isMajorVersion(name) {
+		name = elems[len(elems)-2]
+	}
+	if idx := strings.LastIndex(name, "."); idx != -1 && isMajorVersion(name[idx+1:]) {
+		name = name[:idx]
+	}
+	name = strings.TrimPrefix(name, "go-")
+	name = strings.TrimSuffix(name, "-go")
+	name = strings.TrimSuffix(name, ".go")
+	return strings.Replace(name, "-", "_", -1)
+}
+
+func isMajorVersion(s string) bool {
+	if len(s) < 2 || s[0] != 'v' {
+		return false
+	}
+	for _, r := range s[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 func findAnnotation(doc *ast.CommentGroup, directive string) *ast.Comment {
 	if doc == nil {
 		return nil
@@ -63,70 +181,81 @@ func IsReferenceToOtherPackage(expr ast.Expr) (bool, string) {
 	}
 }
 
+// errCannotDetectTypeName is returned for expressions that are not type name, it is shared to avoid allocations.
+var errCannotDetectTypeName = errors.New("can't detect type name")
+
 // ExprToTypeName convert ast.Expr to type name.
 func ExprToTypeName(expr ast.Expr) (string, error) {
-	if ident, ok := expr.(*ast.Ident); ok {
-		return ident.Name, nil
-	}
-	if star, ok := expr.(*ast.StarExpr); ok {
-		x, err := ExprToTypeName(star.X)
-		if err != nil {
-			return "", nil
-		}
-		return "*" + x, nil
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name, nil
+	case *ast.StarExpr, *ast.SelectorExpr, *ast.ArrayType:
+	default:
+		return "", errCannotDetectTypeName
 	}
-	if selector, ok := expr.(*ast.SelectorExpr); ok {
-		x, err := ExprToTypeName(selector.X)
-		if err != nil {
-			return "", nil
-		}
-		sel, err := ExprToTypeName(selector.Sel)
-		if err != nil {
-			return "", nil
-		}
-		return x + "." + sel, nil
+	var b strings.Builder
+	b.Grow(32)
+	if !writeTypeName(&b, expr) {
+		return "", nil
 	}
-	if array, ok := expr.(*ast.ArrayType); ok {
-		x, err := ExprToTypeName(array.Elt)
-		if err != nil {
-			return "", nil
+	return b.String(), nil
+}
+
+// writeTypeName writes type name of expr to b, returns false if expr contains expression that is not type name.
+func writeTypeName(b *strings.Builder, expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		b.WriteString(e.Name)
+		return true
+	case *ast.StarExpr:
+		b.WriteByte('*')
+		return writeTypeName(b, e.X)
+	case *ast.SelectorExpr:
+		if !writeTypeName(b, e.X) {
+			return false
 		}
-		return "[]" + x, nil
+		b.WriteByte('.')
+		b.WriteString(e.Sel.Name)
+		return true
+	case *ast.ArrayType:
+		b.WriteString("[]")
+		return writeTypeName(b, e.Elt)
 	}
-	return "", errors.New("can't detect type name")
+	return false
 }
 
 // ExprToBaseTypeName convert ast.Expr to type name without "*" and "[]".
 func ExprToBaseTypeName(expr ast.Expr) (string, error) {
-	if ident, ok := expr.(*ast.Ident); ok {
-		return ident.Name, nil
-	}
-	if star, ok := expr.(*ast.StarExpr); ok {
-		x, err := ExprToBaseTypeName(star.X)
-		if err != nil {
-			return "", nil
-		}
-		return x, nil
+	qualifier, name, ok := baseTypeName(expr)
+	if !ok {
+		return "", errCannotDetectTypeName
 	}
-	if selector, ok := expr.(*ast.SelectorExpr); ok {
-		x, err := ExprToBaseTypeName(selector.X)
-		if err != nil {
-			return "", nil
-		}
-		sel, err := ExprToBaseTypeName(selector.Sel)
-		if err != nil {
-			return "", nil
-		}
-		return x + "." + sel, nil
+	if qualifier == "" {
+		return name, nil
 	}
-	if array, ok := expr.(*ast.ArrayType); ok {
-		x, err := ExprToBaseTypeName(array.Elt)
-		if err != nil {
-			return "", nil
+	return qualifier + "." + name, nil
+}
+
+// baseTypeName returns package qualifier and name of base type of expr without "*" and "[]", it doesn't allocate.
+// returns false if expr is not type name, and empty names if expr contains expression that is not type name.
+func baseTypeName(expr ast.Expr) (qualifier string, name string, ok bool) {
+	for nested := false; ; nested = true {
+		switch e := expr.(type) {
+		case *ast.Ident:
+			return "", e.Name, true
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ArrayType:
+			expr = e.Elt
+		case *ast.SelectorExpr:
+			if x, isIdent := e.X.(*ast.Ident); isIdent {
+				return x.Name, e.Sel.Name, true
+			}
+			return "", "", true
+		default:
+			return "", "", nested
 		}
-		return x, nil
 	}
-	return "", errors.New("can't detect type name")
 }
 
 // GetKeys extracts tag value.
@@ -175,3 +304,47 @@ func GetKeys(tag string) []string {
 	}
 	return result
 }
+
+// EditDistance returns edit distance between a and b.
+// transposition of adjacent characters is counted as 1 edit.
+func EditDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+func minInt(v int, vs ...int) int {
+	for _, w := range vs {
+		if w < v {
+			v = w
+		}
+	}
+	return v
+}
+
+func maxInt(v int, vs ...int) int {
+	for _, w := range vs {
+		if w > v {
+			v = w
+		}
+	}
+	return v
+}
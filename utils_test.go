@@ -1,6 +1,10 @@
 package genbase
 
 import (
+	"go/ast"
+	"go/parser"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -11,11 +15,45 @@ func TestPathJoinAll(t *testing.T) {
 		t.Fatalf("unexpected: %v", len(ps))
 	}
 
-	if ps[0] != "misc/fixture/a" || ps[1] != "misc/fixture/b" {
+	if ps[0] != filepath.FromSlash("misc/fixture/a") || ps[1] != filepath.FromSlash("misc/fixture/b") {
+		t.Fatal("unexpected", ps)
+	}
+
+	abs, err := filepath.Abs("misc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps = pathJoinAll("misc/fixture/", "a", filepath.Join(abs, "b"), "c/../d")
+	if ps[0] != filepath.FromSlash("misc/fixture/a") || ps[1] != filepath.Join(abs, "b") || ps[2] != filepath.FromSlash("misc/fixture/d") {
+		t.Fatal("unexpected", ps)
+	}
+
+	ps = pathJoinAll("", "a/b")
+	if ps[0] != filepath.FromSlash("a/b") {
 		t.Fatal("unexpected", ps)
 	}
 }
 
+func TestParserParsePackageDirAbsolute(t *testing.T) {
+	abs, err := filepath.Abs("misc/fixture/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Parser{SkipSemanticsCheck: true}
+	pInfo, err := p.ParsePackageDir(abs + string(filepath.Separator))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pInfo.Dir != abs {
+		t.Errorf("unexpected: %s", pInfo.Dir)
+	}
+	for _, file := range pInfo.Files {
+		if name := pInfo.FileSet.Position(file.AstFile().Pos()).Filename; filepath.Dir(name) != abs {
+			t.Errorf("unexpected: %s", name)
+		}
+	}
+}
+
 func TestGetKeys(t *testing.T) {
 
 	result := GetKeys("a:\"foo\" b:\"bar\"")
@@ -32,3 +70,91 @@ func TestGetKeys(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestGetKeysMalformed(t *testing.T) {
+	for tag, expected := range map[string]string{
+		`json:"a`:         "json",
+		`:"x" json:"a"`:   ",json",
+		`a:"foo" b`:       "a",
+		`a:"foo"  b:"\""`: "a,b",
+	} {
+		if v := strings.Join(GetKeys(tag), ","); v != expected {
+			t.Errorf("unexpected: %q %s", tag, v)
+		}
+	}
+}
+
+func TestGuessPackageName(t *testing.T) {
+	expects := map[string]string{
+		"fmt":                         "fmt",
+		"github.com/favclip/genbase":  "genbase",
+		"gopkg.in/yaml.v2":            "yaml",
+		"github.com/foo/bar/v2":       "bar",
+		"github.com/mattn/go-sqlite3": "sqlite3",
+		"github.com/foo/bar-baz":      "bar_baz",
+	}
+	for path, expect := range expects {
+		if v := guessPackageName(path); v != expect {
+			t.Errorf("unexpected: %s %s", path, v)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	expects := map[[2]string]int{
+		{"+jwg", "+jwg"}:       0,
+		{"+jwgg", "+jwg"}:      1,
+		{"+sampel", "+sample"}: 1,
+		{"", "abc"}:            3,
+		{"kitten", "sitting"}:  3,
+	}
+	for args, expect := range expects {
+		if v := EditDistance(args[0], args[1]); v != expect {
+			t.Errorf("unexpected: %v %v", args, v)
+		}
+	}
+}
+
+func FuzzFindAnnotation(f *testing.F) {
+	for _, seed := range []string{"// +jwg", "//+jwg:opts", "// +jwgg", "/* +jwg */", "//", "// +", "// +jwg\xff"} {
+		f.Add(seed, "+jwg")
+	}
+	f.Fuzz(func(t *testing.T, text string, directive string) {
+		c := &ast.Comment{Text: text}
+		found := findAnnotation(&ast.CommentGroup{List: []*ast.Comment{c}}, directive)
+		if found != nil && found != c {
+			t.Errorf("unexpected: %v", found)
+		}
+	})
+}
+
+func TestExprToTypeName(t *testing.T) {
+	for src, expected := range map[string][2]string{
+		"int":             {"int", "int"},
+		"*[]*time.Time":   {"*[]*time.Time", "time.Time"},
+		"[3]pkg.T":        {"[]pkg.T", "pkg.T"},
+		"*map[string]int": {"", ""},
+	} {
+		expr, err := parser.ParseExpr(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v, err := ExprToTypeName(expr); err != nil || v != expected[0] {
+			t.Errorf("unexpected: %s %q %v", src, v, err)
+		}
+		if v, err := ExprToBaseTypeName(expr); err != nil || v != expected[1] {
+			t.Errorf("unexpected: %s %q %v", src, v, err)
+		}
+	}
+
+	expr, err := parser.ParseExpr("map[string]int")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ExprToTypeName(expr); err == nil {
+		t.Error("unexpected: error is nil")
+	}
+	if _, err := ExprToBaseTypeName(expr); err == nil {
+		t.Error("unexpected: error is nil")
+	}
+}
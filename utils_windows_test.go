@@ -0,0 +1,24 @@
+package genbase
+
+import (
+	"testing"
+)
+
+func TestPathJoinAllWindows(t *testing.T) {
+	ps := pathJoinAll(`C:\work/misc`, "a.go", `D:\other\b.go`, `\\server\share\c.go`, "//server/share/d.go")
+	expects := []string{`C:\work\misc\a.go`, `D:\other\b.go`, `\\server\share\c.go`, `\\server\share\d.go`}
+	for i, expect := range expects {
+		if ps[i] != expect {
+			t.Errorf("unexpected: %d %s", i, ps[i])
+		}
+	}
+
+	ps = pathJoinAll(`\\server\share\dir`, "a.go")
+	if ps[0] != `\\server\share\dir\a.go` {
+		t.Errorf("unexpected: %s", ps[0])
+	}
+
+	if v := normalizePath("misc/fixture/"); v != `misc\fixture` {
+		t.Errorf("unexpected: %s", v)
+	}
+}
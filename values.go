@@ -0,0 +1,217 @@
+package genbase
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+	"time"
+)
+
+// ZeroValueExpr returns Go source of zero value of field type. e.g. "", 0, nil, time.Time{}
+// type is rendered as it is written in source.
+func (f *FieldInfo) ZeroValueExpr(pkg *PackageInfo) string {
+	return ZeroValueExpr(pkg, f.Type)
+}
+
+// ZeroValueExpr returns Go source of zero value of type expr.
+// expr is rendered as it is written in source.
+func ZeroValueExpr(pkg *PackageInfo, expr ast.Expr) string {
+	var typ types.Type
+	if pkg != nil {
+		typ = pkg.TypeOf(expr)
+	}
+	if typ == nil {
+		return zeroValueExprOf(NewTypeRef(expr))
+	}
+
+	return zeroValueOfType(typ, types.ExprString(expr))
+}
+
+// zeroValueOfType returns zero value of typ, typeName is rendered name of typ.
+func zeroValueOfType(typ types.Type, typeName string) string {
+	if _, ok := typ.(*types.TypeParam); ok {
+		return "*new(" + typeName + ")"
+	}
+	switch t := typ.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case t.Info()&types.IsBoolean != 0:
+			return "false"
+		case t.Info()&types.IsString != 0:
+			return `""`
+		case t.Info()&types.IsNumeric != 0:
+			return "0"
+		}
+		return "nil"
+	case *types.Struct, *types.Array:
+		return typeName + "{}"
+	}
+	return "nil"
+}
+
+// zeroValueExprOf returns zero value by syntax only.
+func zeroValueExprOf(ref *TypeRef) string {
+	switch ref.Kind {
+	case KindBasic:
+		switch ref.Name {
+		case "bool":
+			return "false"
+		case "string":
+			return `""`
+		case "error", "any":
+			return "nil"
+		}
+		return "0"
+	case KindStruct, KindArray:
+		return ref.String() + "{}"
+	case KindNamed:
+		return "*new(" + ref.String() + ")"
+	}
+	return "nil"
+}
+
+// SampleValueExpr returns Go source of plausible non-zero value of field type.
+// it is useful to generate fixtures of golden tests or example outputs.
+func (f *FieldInfo) SampleValueExpr(pkg *PackageInfo) string {
+	return SampleValueExpr(pkg, f.Type)
+}
+
+// SampleOptions is options of sample values, see SampleValueExprWithOptions.
+type SampleOptions struct {
+	Time time.Time // value of time.Time, it is rendered in UTC. 2006-01-02 15:04:05 UTC is used if it is zero
+}
+
+// defaultSampleTime is sample value of time.Time if SampleOptions has no Time.
+var defaultSampleTime = time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+
+// SampleValueExpr returns Go source of plausible non-zero value of type expr.
+// nested structs, slices, arrays and maps are filled recursively.
+// it falls back to ZeroValueExpr when type information is not available.
+func SampleValueExpr(pkg *PackageInfo, expr ast.Expr) string {
+	return SampleValueExprWithOptions(pkg, expr, nil)
+}
+
+// SampleValueExprWithOptions is SampleValueExpr with options, default options are used if opts is nil.
+// value of type parameter is zero value of it, because constraint may not have non-zero literal.
+func SampleValueExprWithOptions(pkg *PackageInfo, expr ast.Expr, opts *SampleOptions) string {
+	var typ types.Type
+	if pkg != nil {
+		typ = pkg.TypeOf(expr)
+	}
+	if typ == nil {
+		return ZeroValueExpr(pkg, expr)
+	}
+	s := &sampler{
+		pkg:       pkg.Types,
+		qualifier: types.RelativeTo(pkg.Types),
+		visited:   make(map[*types.TypeName]bool),
+		time:      defaultSampleTime,
+	}
+	if opts != nil && !opts.Time.IsZero() {
+		s.time = opts.Time.UTC()
+	}
+	return s.sample(typ)
+}
+
+type sampler struct {
+	pkg       *types.Package
+	qualifier types.Qualifier
+	visited   map[*types.TypeName]bool
+	time      time.Time
+}
+
+func (s *sampler) sample(typ types.Type) string {
+	if _, ok := typ.(*types.TypeParam); ok {
+		return s.zero(typ)
+	}
+	if named, ok := typ.(*types.Named); ok {
+		obj := named.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() == "time" {
+			switch obj.Name() {
+			case "Time":
+				t := s.time
+				return fmt.Sprintf("time.Date(%d, %d, %d, %d, %d, %d, %d, time.UTC)",
+					t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond())
+			case "Duration":
+				return "time.Second"
+			}
+		}
+		if s.visited[obj] {
+			return s.zero(typ)
+		}
+		s.visited[obj] = true
+		defer delete(s.visited, obj)
+	}
+
+	switch t := typ.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case t.Info()&types.IsBoolean != 0:
+			return "true"
+		case t.Info()&types.IsString != 0:
+			return `"sample"`
+		case t.Info()&types.IsComplex != 0:
+			return "1 + 1i"
+		case t.Info()&types.IsFloat != 0:
+			return "1.5"
+		case t.Info()&types.IsInteger != 0:
+			return "1"
+		}
+		return "nil"
+	case *types.Pointer:
+		switch elem := t.Elem(); elem.Underlying().(type) {
+		case *types.Struct:
+			if named, ok := elem.(*types.Named); ok && s.visited[named.Obj()] {
+				return "nil"
+			}
+			return "&" + s.sample(elem)
+		}
+		return "new(" + s.typeString(t.Elem()) + ")"
+	case *types.Slice:
+		return s.typeString(typ) + "{" + s.sample(t.Elem()) + "}"
+	case *types.Array:
+		if t.Len() == 0 {
+			return s.typeString(typ) + "{}"
+		}
+		return s.typeString(typ) + "{" + s.sample(t.Elem()) + "}"
+	case *types.Map:
+		return s.typeString(typ) + "{" + s.sample(t.Key()) + ": " + s.sample(t.Elem()) + "}"
+	case *types.Struct:
+		var elems []string
+		for i := 0; i < t.NumFields(); i++ {
+			f := t.Field(i)
+			if f.Name() == "_" || !f.Exported() && f.Pkg() != s.pkg {
+				continue
+			}
+			elems = append(elems, f.Name()+": "+s.sample(f.Type()))
+		}
+		return s.typeString(typ) + "{" + strings.Join(elems, ", ") + "}"
+	}
+	return "nil"
+}
+
+// zero returns zero value of typ to stop recursion.
+func (s *sampler) zero(typ types.Type) string {
+	if _, ok := typ.(*types.TypeParam); ok {
+		return "*new(" + s.typeString(typ) + ")"
+	}
+	switch t := typ.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case t.Info()&types.IsBoolean != 0:
+			return "false"
+		case t.Info()&types.IsString != 0:
+			return `""`
+		case t.Info()&types.IsNumeric != 0:
+			return "0"
+		}
+	case *types.Struct, *types.Array:
+		return s.typeString(typ) + "{}"
+	}
+	return "nil"
+}
+
+func (s *sampler) typeString(typ types.Type) string {
+	return types.TypeString(typ, s.qualifier)
+}
@@ -0,0 +1,145 @@
+package genbase
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFieldInfoZeroValueExpr(t *testing.T) {
+	src := `
+	package sample
+
+	import "time"
+
+	type Sample struct {
+		A int
+		B string
+		C bool
+		D *int
+		E []int
+		F map[string]int
+		G time.Time
+		H time.Duration
+		I [2]int
+		J Inner
+		K error
+		L struct{}
+	}
+
+	type Inner struct{}
+	`
+
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := pInfo.CollectTypeInfos([]string{"Sample"})[0].StructType()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expects := []string{"0", `""`, "false", "nil", "nil", "nil", "time.Time{}", "0", "[2]int{}", "Inner{}", "nil", "struct{}{}"}
+	for i, f := range st.FieldInfos() {
+		if v := f.ZeroValueExpr(pInfo); v != expects[i] {
+			t.Errorf("unexpected: %s %s", f.Names[0].Name, v)
+		}
+	}
+
+	// without types
+	expects = []string{"0", `""`, "false", "nil", "nil", "nil", "*new(time.Time)", "*new(time.Duration)", "[2]int{}", "*new(Inner)", "nil", "struct{}{}"}
+	for i, f := range st.FieldInfos() {
+		if v := ZeroValueExpr(nil, f.Type); v != expects[i] {
+			t.Errorf("unexpected: %s %s", f.Names[0].Name, v)
+		}
+	}
+}
+
+func TestFieldInfoSampleValueExpr(t *testing.T) {
+	src := `
+	package sample
+
+	import "time"
+
+	type Sample struct {
+		A int
+		B string
+		C []float64
+		D *Inner
+		E map[string]bool
+		F time.Time
+		G *int
+		H error
+	}
+
+	type Inner struct {
+		Name  string
+		Next  *Inner
+		Items []Inner
+	}
+	`
+
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := pInfo.CollectTypeInfos([]string{"Sample"})[0].StructType()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expects := []string{
+		"1",
+		`"sample"`,
+		"[]float64{1.5}",
+		`&Inner{Name: "sample", Next: nil, Items: []Inner{Inner{}}}`,
+		"map[string]bool{\"sample\": true}",
+		"time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)",
+		"new(int)",
+		"nil",
+	}
+	for i, f := range st.FieldInfos() {
+		if v := f.SampleValueExpr(pInfo); v != expects[i] {
+			t.Errorf("unexpected: %s %s", f.Names[0].Name, v)
+		}
+	}
+}
+
+func TestSampleValueExprWithOptions(t *testing.T) {
+	src := `
+	package sample
+
+	import "time"
+
+	type Sample[T ~int, P any] struct {
+		A T
+		B []P
+		C *T
+		D time.Time
+	}
+	`
+
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := pInfo.CollectTypeInfos([]string{"Sample"})[0].StructType()
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := &SampleOptions{Time: time.Date(2020, 12, 31, 9, 0, 0, 500, time.FixedZone("JST", 9*60*60))}
+	expects := []string{
+		"*new(T)",
+		"[]P{*new(P)}",
+		"new(T)",
+		"time.Date(2020, 12, 31, 0, 0, 0, 500, time.UTC)",
+	}
+	for i, f := range st.FieldInfos() {
+		if v := SampleValueExprWithOptions(pInfo, f.Type, opts); v != expects[i] {
+			t.Errorf("unexpected: %s %s", f.Names[0].Name, v)
+		}
+	}
+	if v := SampleValueExpr(pInfo, st.FieldInfos()[3].Type); v != "time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)" {
+		t.Errorf("unexpected: %s", v)
+	}
+}
@@ -0,0 +1,233 @@
+package genbase
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+)
+
+// ImplementsCheck is interface that a generated type must satisfy.
+type ImplementsCheck struct {
+	TypeName  string // e.g. "Sample"
+	Pointer   bool   // check *TypeName instead of TypeName
+	Interface string // e.g. "Stringer", "encoding/json.Marshaler"
+}
+
+// ImplementsError shows type doesn't satisfy interface.
+type ImplementsError struct {
+	Check  *ImplementsCheck
+	Reason string
+}
+
+// ImplementsErrors is []*ImplementsError synonym.
+type ImplementsErrors []*ImplementsError
+
+// String returns type name of ImplementsCheck.
+func (c *ImplementsCheck) String() string {
+	if c.Pointer {
+		return fmt.Sprintf("*%s implements %s", c.TypeName, c.Interface)
+	}
+	return fmt.Sprintf("%s implements %s", c.TypeName, c.Interface)
+}
+
+func (err *ImplementsError) Error() string {
+	return fmt.Sprintf("%s: %s", err.Check, err.Reason)
+}
+
+func (errs ImplementsErrors) Error() string {
+	ss := make([]string, 0, len(errs))
+	for _, err := range errs {
+		ss = append(ss, err.Error())
+	}
+	return strings.Join(ss, "\n")
+}
+
+// CheckGenerated type-checks generated code together with files of package.
+// file that has same name as fileName in package is replaced by generated code.
+// redeclarations are checked by CheckRedeclaration if StrictDuplicateCheck is enabled.
+func (pkg *PackageInfo) CheckGenerated(fileName string, src []byte) (*types.Package, error) {
+	if pkg.StrictDuplicateCheck {
+		if err := pkg.CheckRedeclaration(fileName, src); err != nil {
+			return nil, err
+		}
+	}
+	genFile, err := parser.ParseFile(pkg.FileSet, fileName, src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parsing generated code: %s: %s", fileName, err)
+	}
+	return pkg.checkWithFile(genFile, fileName, nil)
+}
+
+// checkWithFile type-checks genFile together with files of package except file that has same name as fileName.
+// all errors are passed to handler if it is not nil, otherwise checking stops at first error.
+func (pkg *PackageInfo) checkWithFile(genFile *ast.File, fileName string, handler func(err error)) (*types.Package, error) {
+	files := []*ast.File{genFile}
+	for _, file := range pkg.Files {
+		name := pkg.FileSet.Position(file.Package).Filename
+		if filepath.Base(name) == filepath.Base(fileName) {
+			continue
+		}
+		files = append(files, file.AstFile())
+	}
+
+	config := pkg.checkConfig()
+	config.IgnoreFuncBodies = false
+	config.Error = handler
+	path := pkg.ImportPath()
+	if path == "" {
+		path = pkg.Dir
+	}
+	return config.Check(path, pkg.FileSet, files, nil)
+}
+
+// GeneratedError is syntax or type error in generated code.
+type GeneratedError struct {
+	Position token.Position
+	Line     string // source line of Position in generated code, empty if error is not in generated code
+	Reason   string
+}
+
+// GeneratedErrors is []*GeneratedError synonym.
+type GeneratedErrors []*GeneratedError
+
+func (err *GeneratedError) Error() string {
+	if err.Line == "" {
+		return fmt.Sprintf("%s: %s", err.Position, err.Reason)
+	}
+	return fmt.Sprintf("%s: %s\n\t%s", err.Position, err.Reason, err.Line)
+}
+
+func (errs GeneratedErrors) Error() string {
+	ss := make([]string, 0, len(errs))
+	for _, err := range errs {
+		ss = append(ss, err.Error())
+	}
+	return strings.Join(ss, "\n")
+}
+
+// Verify type-checks accumulated output together with files of package before it is formatted or written.
+// output is treated as file named fileName, file that has same name in package is replaced by it.
+// returns GeneratedErrors that have all syntax or type errors with positions in output.
+// redeclarations are checked by CheckRedeclaration if StrictDuplicateCheck is enabled.
+func (g *Generator) Verify(fileName string) error {
+	pkg := g.Package
+	src := g.Buf.Bytes()
+	if pkg.StrictDuplicateCheck {
+		if err := pkg.CheckRedeclaration(fileName, src); err != nil {
+			return err
+		}
+	}
+
+	lines := strings.Split(string(src), "\n")
+	var errs GeneratedErrors
+	add := func(pos token.Position, reason string) {
+		gErr := &GeneratedError{Position: pos, Reason: reason}
+		if pos.Filename == fileName && 0 < pos.Line && pos.Line <= len(lines) {
+			gErr.Line = strings.TrimSpace(lines[pos.Line-1])
+		}
+		errs = append(errs, gErr)
+	}
+
+	genFile, err := parser.ParseFile(pkg.FileSet, fileName, src, parser.ParseComments|parser.AllErrors)
+	if list, ok := err.(scanner.ErrorList); ok {
+		for _, e := range list {
+			add(e.Pos, e.Msg)
+		}
+		return errs
+	} else if err != nil {
+		return err
+	}
+
+	pkg.checkWithFile(genFile, fileName, func(err error) {
+		if tErr, ok := err.(types.Error); ok {
+			add(tErr.Fset.Position(tErr.Pos), tErr.Msg)
+		} else {
+			add(token.Position{}, err.Error())
+		}
+	})
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+func (pkg *PackageInfo) checkConfig() types.Config {
+	if pkg.typesConfig.Importer == nil {
+		return (&Parser{}).typesConfig(pkg.FileSet, pkg.Dir)
+	}
+	return pkg.typesConfig
+}
+
+// VerifyImplements type-checks generated code together with files of package,
+// and confirms that types satisfy interfaces.
+func (pkg *PackageInfo) VerifyImplements(fileName string, src []byte, checks ...*ImplementsCheck) error {
+	typesPkg, err := pkg.CheckGenerated(fileName, src)
+	if err != nil {
+		return err
+	}
+
+	var errs ImplementsErrors
+	for _, check := range checks {
+		if reason := pkg.implementsReason(typesPkg, check); reason != "" {
+			errs = append(errs, &ImplementsError{Check: check, Reason: reason})
+		}
+	}
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+func (pkg *PackageInfo) implementsReason(typesPkg *types.Package, check *ImplementsCheck) string {
+	obj, ok := typesPkg.Scope().Lookup(check.TypeName).(*types.TypeName)
+	if !ok {
+		return fmt.Sprintf("type %s is not found", check.TypeName)
+	}
+	iface, err := pkg.lookupInterface(typesPkg, check.Interface)
+	if err != nil {
+		return err.Error()
+	}
+
+	var typ types.Type = obj.Type()
+	if check.Pointer {
+		typ = types.NewPointer(typ)
+	}
+	method, wrongType := types.MissingMethod(typ, iface, true)
+	switch {
+	case method == nil:
+		return ""
+	case !check.Pointer && types.Implements(types.NewPointer(typ), iface):
+		return fmt.Sprintf("method %s has pointer receiver", method.Name())
+	case wrongType:
+		return fmt.Sprintf("wrong type for method %s, requires %s", method.Name(), method.Type())
+	}
+	return fmt.Sprintf("missing method %s", method.Name())
+}
+
+func (pkg *PackageInfo) lookupInterface(typesPkg *types.Package, name string) (*types.Interface, error) {
+	var obj types.Object
+	if idx := strings.LastIndex(name, "."); idx != -1 {
+		path := name[:idx]
+		imported, err := pkg.checkConfig().Importer.Import(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot import %s: %s", path, err)
+		}
+		obj = imported.Scope().Lookup(name[idx+1:])
+	} else if obj = typesPkg.Scope().Lookup(name); obj == nil {
+		obj = types.Universe.Lookup(name)
+	}
+
+	if obj == nil {
+		return nil, fmt.Errorf("interface %s is not found", name)
+	}
+	iface, ok := obj.Type().Underlying().(*types.Interface)
+	if !ok {
+		return nil, fmt.Errorf("%s is not interface", name)
+	}
+	return iface, nil
+}
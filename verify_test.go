@@ -0,0 +1,107 @@
+package genbase
+
+import (
+	"testing"
+)
+
+func TestPackageInfoVerifyImplements(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	type Sample struct {
+		A string
+	}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := []byte(`
+	package sample
+
+	func (obj *Sample) String() string { return obj.A }
+
+	func (obj Sample) MarshalJSON() (string, error) { return "", nil }
+	`)
+
+	err = pInfo.VerifyImplements("main_gen.go", src,
+		&ImplementsCheck{TypeName: "Sample", Pointer: true, Interface: "fmt.Stringer"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pInfo.VerifyImplements("main_gen.go", src,
+		&ImplementsCheck{TypeName: "Sample", Interface: "fmt.Stringer"},
+		&ImplementsCheck{TypeName: "Sample", Interface: "encoding/json.Marshaler"},
+		&ImplementsCheck{TypeName: "Sample", Interface: "error"},
+	)
+	errs, ok := err.(ImplementsErrors)
+	if !ok || len(errs) != 3 {
+		t.Fatalf("unexpected: %v", err)
+	}
+	expects := []string{
+		"Sample implements fmt.Stringer: method String has pointer receiver",
+		"Sample implements encoding/json.Marshaler: wrong type for method MarshalJSON, requires func() ([]byte, error)",
+		"Sample implements error: missing method Error",
+	}
+	for i, expect := range expects {
+		if errs[i].Error() != expect {
+			t.Errorf("unexpected: %s", errs[i].Error())
+		}
+	}
+
+	_, err = pInfo.CheckGenerated("main_gen.go", []byte(`
+	package sample
+
+	func (obj *Sample) String() string { return obj.B }
+	`))
+	if err == nil {
+		t.Fatal("unexpected: type error is not reported")
+	}
+}
+
+func TestGeneratorVerify(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", "package sample\n"+
+		"type Sample struct {\n"+
+		"	A string\n"+
+		"}\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewGenerator(pInfo)
+	g.PrintHeader("sample", &[]string{})
+	g.Printf("func (obj *Sample) String() string {\nreturn obj.A\n}\n")
+	if err := g.Verify("main_gen.go"); err != nil {
+		t.Fatal(err)
+	}
+
+	g = NewGenerator(pInfo)
+	g.Printf("package sample\n\n")
+	g.Printf("func (obj *Sample) String() string {\nreturn obj.B\n}\n")
+	g.Printf("func (obj *Sample) Len() int {\nreturn len(obj.A) + undefinedValue\n}\n")
+	err = g.Verify("main_gen.go")
+	errs, ok := err.(GeneratedErrors)
+	if !ok {
+		t.Fatalf("unexpected: %v", err)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("unexpected: %v", errs)
+	}
+	if v := errs[0]; v.Position.Filename != "main_gen.go" || v.Position.Line != 4 || v.Line != "return obj.B" {
+		t.Errorf("unexpected: %s", v)
+	}
+	if v := errs[1]; v.Position.Line != 7 || v.Reason != "undefined: undefinedValue" {
+		t.Errorf("unexpected: %s", v)
+	}
+
+	g = NewGenerator(pInfo)
+	g.Printf("package sample\n\nfunc broken( {\n")
+	err = g.Verify("main_gen.go")
+	if errs, ok := err.(GeneratedErrors); !ok || len(errs) == 0 || errs[0].Position.Line != 3 {
+		t.Errorf("unexpected: %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package genbase
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"sort"
+)
+
+// ParseStringSources parses package that consists of virtual files, files maps file name to source.
+// deps are virtual dependency packages that map import path to their virtual files,
+// they are imported instead of Importer, so tests can model packages without file system.
+func (p *Parser) ParseStringSources(files map[string]string, deps map[string]map[string]string) (*PackageInfo, error) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	codes := make([]string, len(names))
+	for i, name := range names {
+		codes[i] = files[name]
+	}
+
+	fs := token.NewFileSet()
+	if len(deps) == 0 {
+		return p.parsePackageWithFileSet(fs, ".", "", names, codes, p.GoVersion)
+	}
+	virtual := *p
+	virtual.Importer = &virtualImporter{
+		parser:   p,
+		fs:       fs,
+		deps:     deps,
+		fallback: p.typesConfig(fs, ".").Importer,
+		pkgs:     make(map[string]*types.Package),
+		checking: make(map[string]bool),
+	}
+	return virtual.parsePackageWithFileSet(fs, ".", "", names, codes, p.GoVersion)
+}
+
+// virtualImporter is types.Importer that type checks virtual dependency packages.
+type virtualImporter struct {
+	parser   *Parser
+	fs       *token.FileSet
+	deps     map[string]map[string]string
+	fallback types.Importer
+	pkgs     map[string]*types.Package
+	checking map[string]bool
+}
+
+func (imp *virtualImporter) Import(path string) (*types.Package, error) {
+	if pkg, ok := imp.pkgs[path]; ok {
+		return pkg, nil
+	}
+	files, ok := imp.deps[path]
+	if !ok {
+		return imp.fallback.Import(path)
+	}
+	if imp.checking[path] {
+		return nil, fmt.Errorf("import cycle of virtual package %s", path)
+	}
+	imp.checking[path] = true
+	defer delete(imp.checking, path)
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var astFiles []*ast.File
+	for _, name := range names {
+		file, err := parser.ParseFile(imp.fs, name, files[name], parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("parsing virtual package %s: %s", path, err)
+		}
+		astFiles = append(astFiles, file)
+	}
+
+	config := imp.parser.typesConfig(imp.fs, ".")
+	config.Importer = imp
+	config.GoVersion = imp.parser.GoVersion
+	pkg, err := config.Check(path, imp.fs, astFiles, nil)
+	if err != nil {
+		return nil, fmt.Errorf("type checking virtual package %s: %s", path, err)
+	}
+	imp.pkgs[path] = pkg
+	return pkg, nil
+}
@@ -0,0 +1,79 @@
+package genbase
+
+import (
+	"go/types"
+	"strings"
+	"testing"
+)
+
+func TestParserParseStringSources(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSources(map[string]string{
+		"user.go": `
+		package sample
+
+		import (
+			"time"
+
+			"example.com/model"
+		)
+
+		// +jwg
+		type User struct {
+			Base  model.Base
+			Group *Group
+			At    time.Time
+		}
+		`,
+		"group.go": `
+		package sample
+
+		type Group struct {
+			Name string
+		}
+		`,
+	}, map[string]map[string]string{
+		"example.com/model": {
+			"base.go": `
+			package model
+
+			import "example.com/model/id"
+
+			type Base struct {
+				ID id.ID
+			}
+			`,
+		},
+		"example.com/model/id": {
+			"id.go": "package id\n\ntype ID int64\n",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, file := range pInfo.Files {
+		names = append(names, pInfo.FileSet.Position(file.AstFile().Pos()).Filename)
+	}
+	if v := strings.Join(names, ","); v != "group.go,user.go" {
+		t.Errorf("unexpected: %s", v)
+	}
+	if v := len(pInfo.CollectTaggedTypeInfos("+jwg")); v != 1 {
+		t.Errorf("unexpected: %d", v)
+	}
+	obj := pInfo.Types.Scope().Lookup("User")
+	if v := obj.Type().Underlying().(*types.Struct).Field(0).Type().Underlying().String(); v != "struct{ID example.com/model/id.ID}" {
+		t.Errorf("unexpected: %s", v)
+	}
+
+	_, err = p.ParseStringSources(map[string]string{
+		"main.go": "package sample\n\nimport _ \"example.com/a\"\n",
+	}, map[string]map[string]string{
+		"example.com/a": {"a.go": "package a\n\nimport _ \"example.com/b\"\n"},
+		"example.com/b": {"b.go": "package b\n\nimport _ \"example.com/a\"\n"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "import cycle") {
+		t.Errorf("unexpected: %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package genbase
+
+import (
+	"go/ast"
+)
+
+// Visitor has callbacks of Walk. nil callback is ignored.
+type Visitor struct {
+	// VisitType is called with each type. fields of type are skipped if it returns false.
+	VisitType func(t *TypeInfo) bool
+	// VisitField is called with each field of struct, owner is the type that declares field.
+	// parents are fields that contain field, for nested struct or embedded type.
+	VisitField func(owner *TypeInfo, parents FieldInfos, f *FieldInfo)
+	// VisitEmbedded is called with each embedded field and its TypeInfo, embedded is nil if it is not resolved.
+	// fields of embedded type are walked only if it returns true.
+	VisitEmbedded func(owner *TypeInfo, f *FieldInfo, embedded *TypeInfo) bool
+}
+
+type walker struct {
+	v        *Visitor
+	parsed   []*PackageInfo
+	visiting map[*ast.StructType]bool
+}
+
+// Walk walks all types in package with v.
+// embedded types are resolved from pkg or parsed packages. see FieldInfo.ResolveTypeInfo.
+func (pkg *PackageInfo) Walk(v *Visitor, parsed ...*PackageInfo) {
+	for _, t := range pkg.TypeInfos() {
+		t.Walk(v, parsed...)
+	}
+}
+
+// Walk walks type, and fields of nested structs and embedded types with v.
+// recursive embedding is walked only once.
+func (t *TypeInfo) Walk(v *Visitor, parsed ...*PackageInfo) {
+	w := &walker{
+		v:        v,
+		parsed:   parsed,
+		visiting: make(map[*ast.StructType]bool),
+	}
+	if v.VisitType != nil && !v.VisitType(t) {
+		return
+	}
+	w.walkStruct(t, nil, t.TypeSpec.Type)
+}
+
+func (w *walker) walkStruct(owner *TypeInfo, parents FieldInfos, expr ast.Expr) {
+	st := nestedStructType(expr)
+	if st == nil || w.visiting[st] {
+		return
+	}
+	w.visiting[st] = true
+	defer delete(w.visiting, st)
+
+	for _, f := range (*StructTypeInfo)(st).FieldInfos() {
+		if w.v.VisitField != nil {
+			w.v.VisitField(owner, parents, f)
+		}
+		path := append(parents[:len(parents):len(parents)], f)
+		if len(f.Names) == 0 {
+			embedded := f.ResolveTypeInfo(owner.PackageInfo, w.parsed...)
+			if w.v.VisitEmbedded != nil && w.v.VisitEmbedded(owner, f, embedded) && embedded != nil {
+				w.walkStruct(embedded, path, embedded.TypeSpec.Type)
+			}
+			continue
+		}
+		w.walkStruct(owner, path, f.Type)
+	}
+}
+
+// nestedStructType returns struct type of expr, "*", "[]" and "[N]" are ignored.
+// returns nil if expr is not struct type literal.
+func nestedStructType(expr ast.Expr) *ast.StructType {
+	for {
+		switch e := expr.(type) {
+		case *ast.StructType:
+			return e
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ArrayType:
+			expr = e.Elt
+		case *ast.ParenExpr:
+			expr = e.X
+		default:
+			return nil
+		}
+	}
+}
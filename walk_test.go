@@ -0,0 +1,72 @@
+package genbase
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPackageInfoWalk(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSource("main.go", `
+	package sample
+
+	type Sample struct {
+		A string
+		B []struct {
+			C int
+		}
+		*Base
+	}
+
+	type Base struct {
+		D bool
+		*Sample
+	}
+
+	type Other int
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var visited []string
+	pInfo.CollectTypeInfos([]string{"Sample"})[0].Walk(&Visitor{
+		VisitType: func(t *TypeInfo) bool {
+			visited = append(visited, "type:"+t.Name())
+			return true
+		},
+		VisitField: func(owner *TypeInfo, parents FieldInfos, f *FieldInfo) {
+			var names []string
+			for _, parent := range append(parents, f) {
+				if len(parent.Names) != 0 {
+					names = append(names, parent.Names[0].Name)
+				} else {
+					names = append(names, parent.TypeName())
+				}
+			}
+			visited = append(visited, owner.Name()+":"+strings.Join(names, "."))
+		},
+		VisitEmbedded: func(owner *TypeInfo, f *FieldInfo, embedded *TypeInfo) bool {
+			visited = append(visited, "embedded:"+embedded.Name())
+			return true
+		},
+	})
+	expect := "type:Sample,Sample:A,Sample:B,Sample:B.C,Sample:*Base,embedded:Base,Base:*Base.D,Base:*Base.*Sample,embedded:Sample"
+	if v := strings.Join(visited, ","); v != expect {
+		t.Errorf("unexpected: %s", v)
+	}
+
+	var types []string
+	pInfo.Walk(&Visitor{
+		VisitType: func(t *TypeInfo) bool {
+			types = append(types, t.Name())
+			return false
+		},
+		VisitField: func(owner *TypeInfo, parents FieldInfos, f *FieldInfo) {
+			t.Errorf("unexpected: %s", f.TypeName())
+		},
+	})
+	if v := strings.Join(types, ","); v != "Sample,Base,Other" {
+		t.Errorf("unexpected: %s", v)
+	}
+}
@@ -0,0 +1,124 @@
+package genbase
+
+import (
+	"go/types"
+	"sort"
+)
+
+// WrapperKind is category of wrapper type.
+type WrapperKind string
+
+const (
+	// WrapperNullable shows wrapper holds value and validity. e.g. sql.NullString
+	WrapperNullable WrapperKind = "nullable"
+	// WrapperUUID shows wrapper is UUID. e.g. uuid.UUID
+	WrapperUUID WrapperKind = "uuid"
+	// WrapperDecimal shows wrapper is arbitrary-precision decimal. e.g. decimal.Decimal
+	WrapperDecimal WrapperKind = "decimal"
+	// WrapperDate shows wrapper is date or time without time zone. e.g. civil.Date
+	WrapperDate WrapperKind = "date"
+	// WrapperKey shows wrapper is datastore key. e.g. datastore.Key
+	WrapperKey WrapperKind = "key"
+)
+
+// WrapperType is well-known type that generators treat specially instead of struct.
+type WrapperType struct {
+	Package string      // import path. e.g. "database/sql"
+	Name    string      // type name. e.g. "NullString"
+	Kind    WrapperKind // category of wrapper
+	Value   string      // Go type of wrapped value if it has. e.g. "string" for sql.NullString
+}
+
+// String returns qualified name of type. e.g. "database/sql.NullString"
+func (w *WrapperType) String() string {
+	return w.Package + "." + w.Name
+}
+
+// WellKnownWrappers are wrapper types registered to DefaultWrapperRegistry.
+var WellKnownWrappers = []*WrapperType{
+	{Package: "database/sql", Name: "NullString", Kind: WrapperNullable, Value: "string"},
+	{Package: "database/sql", Name: "NullInt64", Kind: WrapperNullable, Value: "int64"},
+	{Package: "database/sql", Name: "NullInt32", Kind: WrapperNullable, Value: "int32"},
+	{Package: "database/sql", Name: "NullInt16", Kind: WrapperNullable, Value: "int16"},
+	{Package: "database/sql", Name: "NullByte", Kind: WrapperNullable, Value: "byte"},
+	{Package: "database/sql", Name: "NullFloat64", Kind: WrapperNullable, Value: "float64"},
+	{Package: "database/sql", Name: "NullBool", Kind: WrapperNullable, Value: "bool"},
+	{Package: "database/sql", Name: "NullTime", Kind: WrapperNullable, Value: "time.Time"},
+	{Package: "github.com/google/uuid", Name: "UUID", Kind: WrapperUUID},
+	{Package: "github.com/gofrs/uuid", Name: "UUID", Kind: WrapperUUID},
+	{Package: "github.com/shopspring/decimal", Name: "Decimal", Kind: WrapperDecimal},
+	{Package: "cloud.google.com/go/civil", Name: "Date", Kind: WrapperDate},
+	{Package: "cloud.google.com/go/civil", Name: "Time", Kind: WrapperDate},
+	{Package: "cloud.google.com/go/civil", Name: "DateTime", Kind: WrapperDate},
+	{Package: "cloud.google.com/go/datastore", Name: "Key", Kind: WrapperKey},
+	{Package: "google.golang.org/appengine/datastore", Name: "Key", Kind: WrapperKey},
+	{Package: "go.mercari.io/datastore", Name: "Key", Kind: WrapperKey},
+}
+
+// WrapperRegistry classifies named types by registered wrapper types.
+type WrapperRegistry struct {
+	wrappers map[string]*WrapperType
+}
+
+// DefaultWrapperRegistry is WrapperRegistry that has WellKnownWrappers, it is used by FieldInfo.WrapperType.
+var DefaultWrapperRegistry = NewWrapperRegistry(WellKnownWrappers...)
+
+// NewWrapperRegistry creates new WrapperRegistry that has wrappers.
+func NewWrapperRegistry(wrappers ...*WrapperType) *WrapperRegistry {
+	r := &WrapperRegistry{}
+	r.Register(wrappers...)
+	return r
+}
+
+// Register registers wrapper types. registered type of same package and name is replaced.
+func (r *WrapperRegistry) Register(wrappers ...*WrapperType) {
+	if r.wrappers == nil {
+		r.wrappers = make(map[string]*WrapperType)
+	}
+	for _, w := range wrappers {
+		r.wrappers[w.String()] = w
+	}
+}
+
+// Wrappers returns registered wrapper types sorted by qualified name.
+func (r *WrapperRegistry) Wrappers() []*WrapperType {
+	wrappers := make([]*WrapperType, 0, len(r.wrappers))
+	for _, w := range r.wrappers {
+		wrappers = append(wrappers, w)
+	}
+	sort.Slice(wrappers, func(i, j int) bool { return wrappers[i].String() < wrappers[j].String() })
+	return wrappers
+}
+
+// Lookup returns wrapper type of typ or pointer to it, or nil if typ is not registered.
+func (r *WrapperRegistry) Lookup(typ types.Type) *WrapperType {
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	named, ok := typ.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return nil
+	}
+	return r.wrappers[named.Obj().Pkg().Path()+"."+named.Obj().Name()]
+}
+
+// FieldWrapperType returns wrapper type of field type, or nil if it is not registered or types are not resolved.
+func (r *WrapperRegistry) FieldWrapperType(pkg *PackageInfo, f *FieldInfo) *WrapperType {
+	typ := pkg.TypeOf(f.Type)
+	if typ == nil {
+		return nil
+	}
+	return r.Lookup(typ)
+}
+
+// WrapperType returns wrapper type of field type in DefaultWrapperRegistry, or nil.
+// pointer to wrapper type is also classified. e.g. *datastore.Key
+func (f *FieldInfo) WrapperType(pkg *PackageInfo) *WrapperType {
+	return DefaultWrapperRegistry.FieldWrapperType(pkg, f)
+}
+
+// IsWrapper returns true if field type is wrapper type of kind in DefaultWrapperRegistry, otherwise returns false.
+func (f *FieldInfo) IsWrapper(pkg *PackageInfo, kind WrapperKind) bool {
+	w := f.WrapperType(pkg)
+	return w != nil && w.Kind == kind
+}
@@ -0,0 +1,59 @@
+package genbase
+
+import (
+	"testing"
+)
+
+func TestFieldInfoWrapperType(t *testing.T) {
+	p := &Parser{}
+	pInfo, err := p.ParseStringSources(map[string]string{
+		"main.go": "package sample\n" +
+			"import (\n" +
+			"	\"database/sql\"\n" +
+			"	\"github.com/google/uuid\"\n" +
+			"	\"example.com/money\"\n" +
+			")\n" +
+			"type Sample struct {\n" +
+			"	Name  sql.NullString\n" +
+			"	ID    *uuid.UUID\n" +
+			"	Price money.Amount\n" +
+			"	Count int\n" +
+			"}\n",
+	}, map[string]map[string]string{
+		"github.com/google/uuid": {"uuid.go": "package uuid\ntype UUID [16]byte\n"},
+		"example.com/money":      {"money.go": "package money\ntype Amount struct{ Units int64 }\n"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := pInfo.CollectTypeInfos([]string{"Sample"})[0].StructType()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := st.FieldInfos()
+
+	if w := fields[0].WrapperType(pInfo); w == nil || w.Kind != WrapperNullable || w.Value != "string" || w.String() != "database/sql.NullString" {
+		t.Errorf("unexpected: %v", w)
+	}
+	if !fields[1].IsWrapper(pInfo, WrapperUUID) {
+		t.Error("unexpected: *uuid.UUID is not uuid")
+	}
+	if w := fields[2].WrapperType(pInfo); w != nil {
+		t.Errorf("unexpected: %v", w)
+	}
+	if w := fields[3].WrapperType(pInfo); w != nil {
+		t.Errorf("unexpected: %v", w)
+	}
+
+	r := NewWrapperRegistry(WellKnownWrappers...)
+	r.Register(&WrapperType{Package: "example.com/money", Name: "Amount", Kind: WrapperDecimal})
+	if w := r.FieldWrapperType(pInfo, fields[2]); w == nil || w.Kind != WrapperDecimal {
+		t.Errorf("unexpected: %v", w)
+	}
+	if DefaultWrapperRegistry.FieldWrapperType(pInfo, fields[2]) != nil {
+		t.Error("unexpected: DefaultWrapperRegistry is modified")
+	}
+	if v := len(r.Wrappers()); v != len(WellKnownWrappers)+1 {
+		t.Errorf("unexpected: %d", v)
+	}
+}